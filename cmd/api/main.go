@@ -3,31 +3,68 @@ package main
 import (
 	"context"
 	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/expvar"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"go.uber.org/zap"
 
+	"github.com/itsahyarr/gofiber-boilerplate/internal/audit"
+	auditHandler "github.com/itsahyarr/gofiber-boilerplate/internal/audit/handler"
+	auditRepo "github.com/itsahyarr/gofiber-boilerplate/internal/audit/repository"
+	auditService "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/auth"
 	authHandler "github.com/itsahyarr/gofiber-boilerplate/internal/auth/handler"
 	authRepo "github.com/itsahyarr/gofiber-boilerplate/internal/auth/repository"
 	authService "github.com/itsahyarr/gofiber-boilerplate/internal/auth/service"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/config"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/database/migration"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email"
+	emailHandler "github.com/itsahyarr/gofiber-boilerplate/internal/email/handler"
+	emailRepo "github.com/itsahyarr/gofiber-boilerplate/internal/email/repository"
+	emailService "github.com/itsahyarr/gofiber-boilerplate/internal/email/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/job"
+	jobHandler "github.com/itsahyarr/gofiber-boilerplate/internal/job/handler"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory"
+	loginHistoryHandler "github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/handler"
+	loginEventRepo "github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/repository"
+	loginHistoryService "github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/migrations"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification"
+	notificationHandler "github.com/itsahyarr/gofiber-boilerplate/internal/notification/handler"
+	notificationRepo "github.com/itsahyarr/gofiber-boilerplate/internal/notification/repository"
+	notificationService "github.com/itsahyarr/gofiber-boilerplate/internal/notification/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/stream"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/scheduler"
+	schedulerHandler "github.com/itsahyarr/gofiber-boilerplate/internal/scheduler/handler"
+	schedulerRepo "github.com/itsahyarr/gofiber-boilerplate/internal/scheduler/repository"
+	schedulerService "github.com/itsahyarr/gofiber-boilerplate/internal/scheduler/service"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/user"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/activity"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/exporter"
 	userHandler "github.com/itsahyarr/gofiber-boilerplate/internal/user/handler"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/importer"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/lookup"
 	userRepo "github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/search"
 	userService "github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/delivery"
+	webhookHandler "github.com/itsahyarr/gofiber-boilerplate/internal/webhook/handler"
+	webhookRepo "github.com/itsahyarr/gofiber-boilerplate/internal/webhook/repository"
+	webhookService "github.com/itsahyarr/gofiber-boilerplate/internal/webhook/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/clientip"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
 	pkgLogger "github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/shutdown"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
 )
 
@@ -56,8 +93,10 @@ func main() {
 		pkgLogger.Fatal("Failed to connect to Redis", zap.Error(err))
 	}
 
-	// Internal database migrations
-	migration.RunMigrations(mongodb)
+	// Internal database migrations; refuse to serve on an unmigrated database
+	if err := migration.RunMigrations(mongodb, migrations.All()...); err != nil {
+		pkgLogger.Fatal("Failed to run database migrations", zap.Error(err))
+	}
 
 	// Initialize PASETO token maker
 	tokenMaker, err := token.NewPasetoMaker(cfg.Token.SymmetricKey)
@@ -67,19 +106,134 @@ func main() {
 
 	// Initialize repositories
 	userRepository := userRepo.NewUserRepository(mongodb)
+	if cfg.User.CacheEnabled {
+		userRepository = userRepo.NewCachedUserRepository(userRepository, redis, cfg.User.CacheTTL)
+	}
+	userStatsRepository := userRepo.NewUserStatsRepository(mongodb)
 	tokenRepository := authRepo.NewTokenRepository(redis)
+	webhookRepository := webhookRepo.NewWebhookRepository(mongodb)
+	deliveryRepository := webhookRepo.NewDeliveryRepository(mongodb)
+	taskRunRepository := schedulerRepo.NewRunRepository(mongodb)
+	emailRepository := emailRepo.NewEmailRepository(mongodb)
+	notificationRepository := notificationRepo.NewNotificationRepository(mongodb)
+	auditRepository := auditRepo.NewAuditRepository(mongodb)
+	loginEventRepository := loginEventRepo.NewLoginEventRepository(mongodb)
+
+	txManager, err := database.NewTxManager(mongodb)
+	if err != nil {
+		pkgLogger.Fatal("Failed to probe MongoDB", zap.Error(err))
+	}
+	eventOutbox := event.NewOutbox(mongodb)
+
+	// Slow work is queued for cmd/worker
+	jobQueue := queue.NewQueue(redis, cfg.Queue.Name, cfg.Queue.VisibilityTimeout, cfg.Queue.MaxAttempts)
+
+	// Email templates are parsed at startup so a broken template fails fast
+	emailTemplates, err := email.Templates(cfg.Mail.DefaultLocale)
+	if err != nil {
+		pkgLogger.Fatal("Failed to parse email templates", zap.Error(err))
+	}
 
 	// Initialize services
-	authSvc := authService.NewAuthService(userRepository, tokenRepository, tokenMaker, cfg)
-	userSvc := userService.NewUserService(userRepository, mongodb)
+	auditSvc := auditService.NewAuditService(auditRepository)
+	// Live notifications reach clients on every replica through Redis pub/sub
+	notificationBroker := stream.NewBroker(redis)
+	notificationSvc := notificationService.NewNotificationService(notificationRepository, notificationBroker)
+	// Login attempts are queued here and stored by the worker
+	loginHistorySvc := loginHistoryService.NewLoginHistoryService(loginEventRepository, nil, nil, jobQueue)
+	authSvc := authService.NewAuthService(userRepository, userStatsRepository, tokenRepository, tokenMaker, cfg, txManager, eventOutbox, auditSvc, loginHistorySvc)
+	userSearch := search.NewMongoProvider(userRepository)
+	userSvc := userService.NewUserService(userRepository, userStatsRepository, userSearch, txManager, eventOutbox, auditSvc)
+	userImporter := importer.NewImporter(userSvc, userRepository, mongodb, importer.NewJobStore(redis), jobQueue)
+	userExporter := exporter.NewExporter(userSvc, mongodb, exporter.NewJobStore(redis), jobQueue)
+	userLookup := lookup.NewLookup(userSvc, redis, cfg.User.LookupCacheTTL)
+	activityTracker := activity.NewTracker(userRepository, cfg.User.LastSeenInterval)
+	webhookSvc := webhookService.NewWebhookService(webhookRepository, deliveryRepository, cfg.Webhook.AllowPrivateTargets)
+	taskScheduler := schedulerService.NewScheduler(redis, taskRunRepository)
+	emailSvc := emailService.NewEmailService(emailRepository, emailTemplates, jobQueue, cfg.Mail.From, cfg.Mail.MaxAttempts)
+
+	// Periodic tasks, registered per module
+	if err := user.RegisterTasks(taskScheduler, userSvc, userExporter, cfg.User); err != nil {
+		pkgLogger.Fatal("Failed to register scheduled tasks", zap.Error(err))
+	}
+
+	// Background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		if cfg.Scheduler.Enabled {
+			taskScheduler.Run(workerCtx)
+		}
+	}()
+
+	// Stopping the broker disconnects streaming clients so shutdown isn't held up
+	brokerDone := make(chan struct{})
+	go func() {
+		defer close(brokerDone)
+		notificationBroker.Run(workerCtx)
+	}()
+
+	// Last-seen times are written in the background, off the request path
+	activityDone := make(chan struct{})
+	go func() {
+		defer close(activityDone)
+		activityTracker.Run(workerCtx)
+	}()
+
+	// Domain events are published from the outbox to the Redis Stream and to
+	// in-process subscribers
+	eventDispatcher := event.NewDispatcher()
+	eventDispatcher.Subscribe(event.AllEvents, webhookSvc.HandleEvent)
+	user.SubscribeNotifications(eventDispatcher, emailSvc, notificationSvc, cfg.App.Name)
+	eventRelay := event.NewRelay(eventOutbox, cfg.Event.RelayBatchSize,
+		event.NewRedisStreamSink(redis, cfg.Event.Stream, cfg.Event.StreamMaxLen),
+		eventDispatcher,
+	)
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		event.StartRelay(workerCtx, eventRelay, cfg.Event.RelayInterval)
+	}()
+
+	// Recorded audit entries are appended to the hash chain in the background
+	chainerDone := make(chan struct{})
+	go func() {
+		defer close(chainerDone)
+		auditService.StartChainer(workerCtx, auditSvc, time.Second)
+	}()
+
+	webhookWorker := delivery.NewWorker(webhookRepository, deliveryRepository,
+		delivery.NewSender(cfg.Webhook.Timeout, cfg.Webhook.AllowPrivateTargets), cfg.Webhook.MaxAttempts, cfg.Webhook.BatchSize)
+	webhookDone := make(chan struct{})
+	go func() {
+		defer close(webhookDone)
+		delivery.StartWorker(workerCtx, webhookWorker, cfg.Webhook.WorkerInterval)
+	}()
 
 	// Initialize handlers
 	authHdl := authHandler.NewAuthHandler(authSvc)
-	userHdl := userHandler.NewUserHandler(userSvc)
+	userHdl := userHandler.NewUserHandler(userSvc, userImporter, userExporter, userLookup)
+	webhookHdl := webhookHandler.NewWebhookHandler(webhookSvc)
+	jobHdl := jobHandler.NewJobHandler(jobQueue)
+	schedulerHdl := schedulerHandler.NewSchedulerHandler(taskScheduler)
+	emailHdl := emailHandler.NewEmailHandler(emailSvc)
+	auditHdl := auditHandler.NewAuditHandler(auditSvc)
+	notificationHdl := notificationHandler.NewNotificationHandler(notificationSvc, notificationBroker)
+	loginHistoryHdl := loginHistoryHandler.NewLoginHistoryHandler(loginHistorySvc)
+
+	// Client IPs are read from the proxy header only as far back as the
+	// trusted proxies go
+	clientResolver, err := clientip.New(cfg.Server.ProxyHeader, cfg.Server.TrustedProxies)
+	if err != nil {
+		pkgLogger.Fatal("Invalid trusted proxies", zap.Error(err))
+	}
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
-		AppName:       "GoFiber Boilerplate",
+		AppName:       cfg.App.Name,
 		ServerHeader:  "Fiber",
 		ReadTimeout:   cfg.Server.ReadTimeout,
 		WriteTimeout:  cfg.Server.WriteTimeout,
@@ -101,13 +255,16 @@ func main() {
 	// Global middleware
 	app.Use(recover.New())
 	app.Use(requestid.New())
+	app.Use(audit.Middleware(clientResolver))
+	app.Use(middleware.TrackActivity(activityTracker))
 	app.Use(logger.New(logger.Config{
 		Format: "[${time}] ${status} - ${latency} ${method} ${path}\n",
 	}))
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "*",
-		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowMethods:     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,If-Match",
+		ExposeHeaders:    "ETag",
 		AllowCredentials: false,
 	}))
 
@@ -119,12 +276,22 @@ func main() {
 		})
 	})
 
+	// Runtime metrics (e.g. user cache hits/misses), admins only
+	app.Get("/debug/vars", middleware.AuthMiddleware(tokenMaker), middleware.RequireAdmin(), expvar.New())
+
 	// API v1 routes
 	api := app.Group("/api/v1")
 
 	// Register feature routes
 	auth.RegisterRoutes(api, authHdl, tokenMaker)
+	notification.RegisterRoutes(api, notificationHdl, tokenMaker)
+	loginhistory.RegisterRoutes(api, loginHistoryHdl, tokenMaker)
 	user.RegisterRoutes(api, userHdl, tokenMaker)
+	webhook.RegisterRoutes(api, webhookHdl, tokenMaker)
+	job.RegisterRoutes(api, jobHdl, tokenMaker)
+	scheduler.RegisterRoutes(api, schedulerHdl, tokenMaker)
+	email.RegisterRoutes(api, emailHdl, tokenMaker)
+	audit.RegisterRoutes(api, auditHdl, tokenMaker)
 
 	// Start server in a goroutine
 	go func() {
@@ -135,29 +302,24 @@ func main() {
 	}()
 
 	// Graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	shutdown.Wait()
 
 	pkgLogger.Info("Shutting down server...")
 
-	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	// Shutdown Fiber app
-	if err := app.ShutdownWithContext(ctx); err != nil {
-		pkgLogger.Error("Server forced to shutdown", zap.Error(err))
-	}
-
-	// Close database connections
-	if err := mongodb.Close(ctx); err != nil {
-		pkgLogger.Error("Failed to close MongoDB connection", zap.Error(err))
-	}
+	// Stop background workers
+	stopWorkers()
 
-	if err := redis.Close(); err != nil {
-		pkgLogger.Error("Failed to close Redis connection", zap.Error(err))
-	}
+	shutdown.Run(30*time.Second,
+		shutdown.Hook{Name: "http server", Fn: app.ShutdownWithContext},
+		shutdown.Done("scheduler", schedulerDone),
+		shutdown.Done("notification broker", brokerDone),
+		shutdown.Done("activity tracker", activityDone),
+		shutdown.Done("event relay", relayDone),
+		shutdown.Done("audit chainer", chainerDone),
+		shutdown.Done("webhook worker", webhookDone),
+		shutdown.Hook{Name: "mongodb", Fn: mongodb.Close},
+		shutdown.Hook{Name: "redis", Fn: func(context.Context) error { return redis.Close() }},
+	)
 
 	pkgLogger.Info("Server shutdown complete")
 }
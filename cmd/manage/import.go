@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/importer"
+)
+
+// importUsers imports users from a CSV or NDJSON file and prints the report
+// as JSON. It fails if any row was rejected.
+func (a *app) importUsers(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	filePath := flags.String("file", "", "path to the CSV or NDJSON file to import")
+	formatName := flags.String("format", "", "file format (csv or ndjson), detected from the extension when empty")
+	dryRun := flags.Bool("dry-run", false, "only validate, do not create users")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if *filePath == "" {
+		return fmt.Errorf("%w: -file is required", errUsage)
+	}
+
+	format, err := importer.FormatFromFilename(*filePath)
+	if *formatName != "" {
+		format, err = importer.ParseFormat(*formatName)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	userImporter := importer.NewImporter(a.userService, a.userRepo, nil, nil, nil)
+	job, err := userImporter.Run(ctx, file, importer.Options{
+		Format: format,
+		DryRun: *dryRun,
+	}, func(job *dto.ImportJob) {
+		fmt.Fprintf(os.Stderr, "\rprocessed %d/%d", job.Processed, job.Total)
+	})
+	fmt.Fprintln(os.Stderr)
+	if job == nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(job)
+
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	if job.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", job.Failed, job.Total)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	auditRepo "github.com/itsahyarr/gofiber-boilerplate/internal/audit/repository"
+	auditService "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+	authRepo "github.com/itsahyarr/gofiber-boilerplate/internal/auth/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/config"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+	userRepo "github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/search"
+	userService "github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	pkgLogger "github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+)
+
+const usage = `Usage: manage <command> [arguments]
+
+Commands:
+  migrate up                      apply pending migrations
+  migrate down [-steps N]         revert the last N migrations (default 1)
+  migrate status                  list migrations and whether they are applied
+  seed [-file fixtures/users.json] create fixture users that don't exist yet (not in production)
+  import -file F [-format csv|ndjson] [-dry-run]
+                                  import users from a CSV or NDJSON file
+  user create -email E -first-name F -last-name L [-password P] [-team T] [-admin]
+  user set-password -user EMAIL|ID [-password P] [-temporary]
+  user deactivate -user EMAIL|ID
+`
+
+var errUsage = errors.New("invalid arguments")
+
+// app holds the dependencies shared by the commands
+type app struct {
+	environment string
+	db          *database.MongoDB
+	userRepo    userRepo.UserRepository
+	userService userService.UserService
+	// tokenRepo is only set for commands that end sessions
+	tokenRepo authRepo.TokenRepository
+}
+
+// Runs administrative tasks against the configured database without starting
+// the server.
+//
+//	go run ./cmd/manage migrate up
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize logger
+	pkgLogger.Init(cfg.App.LogLevel, cfg.App.Environment)
+	defer pkgLogger.Sync()
+
+	// Connect to MongoDB
+	mongodb, err := database.NewMongoDB(cfg.Database.URI, cfg.Database.Database)
+	if err != nil {
+		pkgLogger.Fatal("Failed to connect to MongoDB", zap.Error(err))
+	}
+	defer mongodb.Close(context.Background())
+
+	command, args := os.Args[1], os.Args[2:]
+
+	// User commands revoke refresh tokens, which live in Redis
+	var redis *database.Redis
+	if cfg.User.CacheEnabled || command == "user" {
+		redis, err = database.NewRedis(cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Password, cfg.Redis.DB)
+		if err != nil {
+			pkgLogger.Fatal("Failed to connect to Redis", zap.Error(err))
+		}
+		defer redis.Close()
+	}
+
+	userRepository := userRepo.NewUserRepository(mongodb)
+	if cfg.User.CacheEnabled {
+		// Go through the cache so changes made here invalidate what the API cached
+		userRepository = userRepo.NewCachedUserRepository(userRepository, redis, cfg.User.CacheTTL)
+	}
+	txManager, err := database.NewTxManager(mongodb)
+	if err != nil {
+		pkgLogger.Fatal("Failed to probe MongoDB", zap.Error(err))
+	}
+	userStatsRepository := userRepo.NewUserStatsRepository(mongodb)
+	a := &app{
+		environment: cfg.App.Environment,
+		db:          mongodb,
+		userRepo:    userRepository,
+		userService: userService.NewUserService(userRepository, userStatsRepository, search.NewMongoProvider(userRepository), txManager, event.NewOutbox(mongodb), auditService.NewAuditService(auditRepo.NewAuditRepository(mongodb))),
+	}
+	if redis != nil {
+		a.tokenRepo = authRepo.NewTokenRepository(redis)
+	}
+
+	ctx := context.Background()
+
+	switch command {
+	case "migrate":
+		err = a.migrate(ctx, args)
+	case "seed":
+		err = a.seed(ctx, args)
+	case "import":
+		err = a.importUsers(ctx, args)
+	case "user":
+		err = a.user(ctx, args)
+	default:
+		err = fmt.Errorf("%w: unknown command %q", errUsage, command)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, usage)
+		}
+		// Deferred cleanup doesn't run after os.Exit
+		_ = mongodb.Close(context.Background())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/validator"
+)
+
+// seed creates the users in a JSON fixture file. Users whose email already
+// exists are skipped, so seeding can be repeated safely. Fixtures have
+// well-known passwords, so production databases are never seeded.
+func (a *app) seed(ctx context.Context, args []string) error {
+	if a.environment == "production" {
+		return errors.New("refusing to seed fixtures when APP_ENV=production")
+	}
+
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	file := flags.String("file", "fixtures/users.json", "JSON array of users to create")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	var users []dto.CreateUserRequest
+	if err := json.Unmarshal(data, &users); err != nil {
+		return fmt.Errorf("parse %s: %w", *file, err)
+	}
+
+	// Validate everything first so a broken fixture creates nothing
+	for i := range users {
+		if err := validator.ValidateStruct(&users[i]); err != nil {
+			return fmt.Errorf("%s entry %d: %s", *file, i+1, validator.FormatValidationErrors(err))
+		}
+	}
+
+	created, skipped := 0, 0
+	for i := range users {
+		exists, err := a.userRepo.ExistsByEmail(ctx, users[i].Email)
+		if err != nil {
+			return err
+		}
+		if exists {
+			skipped++
+			continue
+		}
+
+		result, err := a.userService.Create(ctx, &users[i])
+		if err != nil {
+			return fmt.Errorf("create %s: %w", users[i].Email, err)
+		}
+		created++
+
+		fmt.Printf("created %s %s\n", result.User.Role, result.User.Email)
+		if result.TemporaryPassword != "" {
+			fmt.Printf("  temporary password: %s\n", result.TemporaryPassword)
+		}
+	}
+
+	fmt.Printf("seeded %d users, %d already existed\n", created, skipped)
+	return nil
+}
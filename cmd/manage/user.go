@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	userRepo "github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/validator"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// minPasswordLength matches the validation on the password endpoints
+const minPasswordLength = 8
+
+func (a *app) user(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: user needs create, set-password or deactivate", errUsage)
+	}
+
+	switch args[0] {
+	case "create":
+		return a.createUser(ctx, args[1:])
+	case "set-password":
+		return a.setPassword(ctx, args[1:])
+	case "deactivate":
+		return a.deactivateUser(ctx, args[1:])
+	}
+
+	return fmt.Errorf("%w: unknown user command %q", errUsage, args[0])
+}
+
+func (a *app) createUser(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	email := flags.String("email", "", "email address")
+	firstName := flags.String("first-name", "", "first name")
+	lastName := flags.String("last-name", "", "last name")
+	password := flags.String("password", "", "password, a temporary one is generated when empty")
+	team := flags.String("team", "", "team")
+	admin := flags.Bool("admin", false, "create an ADMIN instead of a USER")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	role := entity.RoleUser
+	if *admin {
+		role = entity.RoleAdmin
+	}
+
+	req := &dto.CreateUserRequest{
+		Email:     *email,
+		Password:  *password,
+		FirstName: *firstName,
+		LastName:  *lastName,
+		Role:      role,
+		Team:      *team,
+	}
+	if err := validator.ValidateStruct(req); err != nil {
+		return fmt.Errorf("%w: %s", errUsage, validator.FormatValidationErrors(err))
+	}
+
+	created, err := a.userService.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("created %s %s (%s)\n", created.User.Role, created.User.Email, created.User.ID)
+	if created.TemporaryPassword != "" {
+		fmt.Printf("temporary password: %s\n", created.TemporaryPassword)
+	}
+	return nil
+}
+
+func (a *app) setPassword(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("user set-password", flag.ContinueOnError)
+	ref := flags.String("user", "", "email or ID of the user")
+	password := flags.String("password", "", "new password, a temporary one is generated when empty")
+	temporary := flags.Bool("temporary", false, "require the user to change the password on next login")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	user, err := a.findUser(ctx, *ref)
+	if err != nil {
+		return err
+	}
+
+	generated := ""
+	if *password == "" {
+		generated, err = utils.GenerateTemporaryPassword()
+		if err != nil {
+			return err
+		}
+		*password = generated
+		*temporary = true
+	}
+	if len(*password) < minPasswordLength {
+		return fmt.Errorf("%w: password must be at least %d characters", errUsage, minPasswordLength)
+	}
+
+	if err := a.userService.SetPassword(ctx, user.ID.Hex(), *password, *temporary); err != nil {
+		return err
+	}
+	if err := a.endSessions(ctx, user); err != nil {
+		return err
+	}
+
+	fmt.Printf("password set for %s\n", user.Email)
+	if generated != "" {
+		fmt.Printf("temporary password: %s\n", generated)
+	}
+	return nil
+}
+
+func (a *app) deactivateUser(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("user deactivate", flag.ContinueOnError)
+	ref := flags.String("user", "", "email or ID of the user")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	user, err := a.findUser(ctx, *ref)
+	if err != nil {
+		return err
+	}
+
+	inactive := false
+	if _, err := a.userService.Update(ctx, user.ID.Hex(), &dto.UpdateUserRequest{IsActive: &inactive}, userRepo.AnyVersion); err != nil {
+		return err
+	}
+	if err := a.endSessions(ctx, user); err != nil {
+		return err
+	}
+
+	fmt.Printf("deactivated %s\n", user.Email)
+	return nil
+}
+
+// endSessions revokes the user's refresh token, so the old password or the
+// deactivated account can't be used to get new access tokens
+func (a *app) endSessions(ctx context.Context, user *entity.User) error {
+	if err := a.tokenRepo.Delete(ctx, user.ID.Hex()); err != nil {
+		return fmt.Errorf("revoke sessions of %s: %w", user.Email, err)
+	}
+	return nil
+}
+
+// findUser resolves a user by ID or email
+func (a *app) findUser(ctx context.Context, ref string) (*entity.User, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("%w: -user is required", errUsage)
+	}
+
+	var user *entity.User
+	var err error
+	if _, idErr := bson.ObjectIDFromHex(ref); idErr == nil {
+		user, err = a.userRepo.FindByID(ctx, ref)
+	} else {
+		user, err = a.userRepo.FindByEmail(ctx, ref)
+	}
+	if errors.Is(err, userRepo.ErrUserNotFound) {
+		return nil, fmt.Errorf("user %q not found", ref)
+	}
+	return user, err
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	auditRepo "github.com/itsahyarr/gofiber-boilerplate/internal/audit/repository"
+	auditService "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/config"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email/delivery"
+	emailRepo "github.com/itsahyarr/gofiber-boilerplate/internal/email/repository"
+	emailService "github.com/itsahyarr/gofiber-boilerplate/internal/email/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+	loginEventRepo "github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/repository"
+	loginHistoryService "github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/service"
+	notificationRepo "github.com/itsahyarr/gofiber-boilerplate/internal/notification/repository"
+	notificationService "github.com/itsahyarr/gofiber-boilerplate/internal/notification/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/stream"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/exporter"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/importer"
+	userRepo "github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/search"
+	userService "github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/geoip"
+	pkgLogger "github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/shutdown"
+)
+
+// Processes background jobs queued by the API. Run as many replicas as
+// needed; each job is handed to one worker at a time.
+//
+//	go run ./cmd/worker
+func main() {
+	// Load configuration
+	cfg := config.Load()
+
+	// Initialize logger
+	pkgLogger.Init(cfg.App.LogLevel, cfg.App.Environment)
+	defer pkgLogger.Sync()
+
+	pkgLogger.Info("Starting worker",
+		zap.String("environment", cfg.App.Environment),
+		zap.String("queue", cfg.Queue.Name),
+	)
+
+	// Connect to MongoDB
+	mongodb, err := database.NewMongoDB(cfg.Database.URI, cfg.Database.Database)
+	if err != nil {
+		pkgLogger.Fatal("Failed to connect to MongoDB", zap.Error(err))
+	}
+
+	// Connect to Redis
+	redis, err := database.NewRedis(cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Password, cfg.Redis.DB)
+	if err != nil {
+		pkgLogger.Fatal("Failed to connect to Redis", zap.Error(err))
+	}
+
+	// Initialize repositories
+	userRepository := userRepo.NewUserRepository(mongodb)
+	if cfg.User.CacheEnabled {
+		userRepository = userRepo.NewCachedUserRepository(userRepository, redis, cfg.User.CacheTTL)
+	}
+	userStatsRepository := userRepo.NewUserStatsRepository(mongodb)
+	emailRepository := emailRepo.NewEmailRepository(mongodb)
+	loginEventRepository := loginEventRepo.NewLoginEventRepository(mongodb)
+	notificationRepository := notificationRepo.NewNotificationRepository(mongodb)
+
+	jobQueue := queue.NewQueue(redis, cfg.Queue.Name, cfg.Queue.VisibilityTimeout, cfg.Queue.MaxAttempts)
+
+	// Initialize services
+	txManager, err := database.NewTxManager(mongodb)
+	if err != nil {
+		pkgLogger.Fatal("Failed to probe MongoDB", zap.Error(err))
+	}
+	userSvc := userService.NewUserService(userRepository, userStatsRepository, search.NewMongoProvider(userRepository), txManager, event.NewOutbox(mongodb), auditService.NewAuditService(auditRepo.NewAuditRepository(mongodb)))
+	userExporter := exporter.NewExporter(userSvc, mongodb, exporter.NewJobStore(redis), jobQueue)
+	userImporter := importer.NewImporter(userSvc, userRepository, mongodb, importer.NewJobStore(redis), jobQueue)
+
+	mailSender, err := email.NewSender(cfg.Mail)
+	if err != nil {
+		pkgLogger.Fatal("Failed to create mail sender", zap.Error(err))
+	}
+	emailDeliverer := delivery.NewDeliverer(emailRepository, mailSender)
+
+	// Logins are located with a local GeoIP database when one is configured
+	geoLocator := geoip.Disabled()
+	if cfg.Login.GeoIPDatabase != "" {
+		geoReader, err := geoip.Open(cfg.Login.GeoIPDatabase)
+		if err != nil {
+			pkgLogger.Fatal("Failed to open GeoIP database", zap.Error(err))
+		}
+		defer geoReader.Close()
+		geoLocator = geoReader
+	} else {
+		pkgLogger.Warn("No GeoIP database configured; logins from unusual locations won't be flagged")
+	}
+
+	// Notifications reach the user's clients through the API's broker
+	notificationSvc := notificationService.NewNotificationService(notificationRepository, stream.NewBroker(redis))
+	loginHistorySvc := loginHistoryService.NewLoginHistoryService(loginEventRepository, geoLocator, notificationSvc, nil)
+
+	// Register job handlers
+	worker := queue.NewWorker(jobQueue, cfg.Queue.Concurrency, cfg.Queue.PollInterval)
+	worker.Handle(exporter.JobType, userExporter.Run)
+	worker.Handle(importer.JobType, userImporter.RunJob)
+	worker.Handle(emailService.JobType, emailDeliverer.Run)
+	worker.Handle(loginHistoryService.JobType, loginHistorySvc.RunJob)
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		worker.Run(workerCtx)
+	}()
+
+	// Graceful shutdown
+	shutdown.Wait()
+
+	pkgLogger.Info("Shutting down worker...")
+
+	// Stop taking jobs and let the running ones finish; jobs still running
+	// at the timeout are picked up again after their visibility timeout
+	stopWorker()
+
+	shutdown.Run(30*time.Second,
+		shutdown.Done("job worker", done),
+		shutdown.Hook{Name: "mongodb", Fn: mongodb.Close},
+		shutdown.Hook{Name: "redis", Fn: func(context.Context) error { return redis.Close() }},
+	)
+
+	pkgLogger.Info("Worker shutdown complete")
+}
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// ChangeResponse is the before and after value of a changed field. Both are
+// omitted for secrets such as passwords.
+type ChangeResponse struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditLogResponse represents an audit log entry
+type AuditLogResponse struct {
+	ID         string              `json:"id"`
+	Seq        int64               `json:"seq"`
+	ActorID    string              `json:"actorId,omitempty"`
+	ActorRole  string              `json:"actorRole,omitempty"`
+	Action     string              `json:"action"`
+	TargetType string              `json:"targetType,omitempty"`
+	TargetID   string              `json:"targetId,omitempty"`
+	Outcome    entity.AuditOutcome `json:"outcome"`
+	Reason     string              `json:"reason,omitempty"`
+	Changes    []ChangeResponse    `json:"changes,omitempty"`
+	IP         string              `json:"ip,omitempty"`
+	UserAgent  string              `json:"userAgent,omitempty"`
+	RequestID  string              `json:"requestId,omitempty"`
+	CreatedAt  time.Time           `json:"createdAt"`
+	PrevHash   string              `json:"prevHash"`
+	Hash       string              `json:"hash"`
+}
+
+// VerifyResponse reports the result of checking the audit log hash chain.
+// Removing the newest entries can't be detected from the chain alone, so
+// keep LastSeq and LastHash somewhere outside the database and compare them
+// with a later verification.
+type VerifyResponse struct {
+	Valid   bool  `json:"valid"`
+	Checked int64 `json:"checked"`
+	// BrokenAt is the sequence number of the first entry that fails the check
+	BrokenAt *int64 `json:"brokenAt,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	LastSeq  int64  `json:"lastSeq"`
+	LastHash string `json:"lastHash,omitempty"`
+}
+
+// ToAuditLogResponse converts an audit log entity to a response
+func ToAuditLogResponse(entry *entity.AuditLog) AuditLogResponse {
+	var changes []ChangeResponse
+	for _, change := range entry.Changes {
+		changes = append(changes, ChangeResponse{
+			Field:  change.Field,
+			Before: rawJSON(change.Before),
+			After:  rawJSON(change.After),
+		})
+	}
+
+	return AuditLogResponse{
+		ID:         entry.ID.Hex(),
+		Seq:        entry.Seq,
+		ActorID:    entry.ActorID,
+		ActorRole:  entry.ActorRole,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Outcome:    entry.Outcome,
+		Reason:     entry.Reason,
+		Changes:    changes,
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		RequestID:  entry.RequestID,
+		CreatedAt:  entry.CreatedAt,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}
+}
+
+// ToAuditLogResponses converts audit log entities to responses
+func ToAuditLogResponses(entries []*entity.AuditLog) []AuditLogResponse {
+	responses := make([]AuditLogResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = ToAuditLogResponse(entry)
+	}
+	return responses
+}
+
+func rawJSON(value string) json.RawMessage {
+	if value == "" {
+		return nil
+	}
+	return json.RawMessage(value)
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+)
+
+// AuditHandler handles audit log HTTP requests
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+// NewAuditHandler creates a new audit log handler
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// VerifyAuditLogs godoc
+// @Summary      Verify audit log
+// @Description  Check the audit log hash chain for modified, removed or reordered entries (ADMIN only). Compare lastSeq and lastHash with a previously saved result to detect removal of the newest entries
+// @Tags         audit
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} response.Response{data=dto.VerifyResponse}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /audit-logs/verify [get]
+func (h *AuditHandler) VerifyAuditLogs(c *fiber.Ctx) error {
+	result, err := h.auditService.Verify(c.Context())
+	if err != nil {
+		return response.InternalServerError(c, "failed to verify audit log")
+	}
+
+	message := "audit log is intact"
+	if !result.Valid {
+		message = "audit log hash chain is broken"
+	}
+	return response.Success(c, fiber.StatusOK, message, result)
+}
@@ -0,0 +1,25 @@
+package audit
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/clientip"
+)
+
+// Middleware captures the request metadata recorded with audit log entries,
+// taking the client IP from resolver. Register it after the requestid
+// middleware.
+func Middleware(resolver *clientip.Resolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Fiber strings point into the request buffer, which is reused
+		c.Locals(service.MetadataKey, service.Metadata{
+			IP:        resolver.IP(c),
+			UserAgent: strings.Clone(c.Get(fiber.HeaderUserAgent)),
+			RequestID: strings.Clone(c.GetRespHeader(fiber.HeaderXRequestID)),
+		})
+		return c.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/database/migration"
+)
+
+// Migrations returns the audit module's database migrations. Audit logs are
+// kept forever: expiring entries would break the hash chain. Pending entries
+// are removed once chained.
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		migration.CreateIndexes(2026101912, "audit_logs_indexes", "audit_logs",
+			mongo.IndexModel{
+				// Serializes appends: concurrent chainers can't both take the next seq
+				Keys:    bson.D{{Key: "seq", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "actorId", Value: 1}, {Key: "seq", Value: -1}},
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "targetId", Value: 1}, {Key: "seq", Value: -1}},
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "action", Value: 1}, {Key: "seq", Value: -1}},
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "createdAt", Value: -1}},
+			},
+		),
+		migration.CreateIndexes(2026101915, "audit_pending_indexes", "audit_pending",
+			mongo.IndexModel{
+				// The chainer takes pending entries oldest first
+				Keys: bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}},
+			},
+		),
+	}
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// ErrSeqTaken means another writer appended an entry with the same sequence
+// number or ID first
+var ErrSeqTaken = errors.New("audit sequence number already taken")
+
+// AuditRepository defines the interface for audit log data access. Entries
+// are recorded as pending and then appended to the chain by the chainer;
+// chained entries are never updated or deleted.
+type AuditRepository interface {
+	// AddPending stores an entry that is not chained yet
+	AddPending(ctx context.Context, entry *entity.AuditLog) error
+	// Pending returns up to limit pending entries, oldest first
+	Pending(ctx context.Context, limit int) ([]*entity.AuditLog, error)
+	// RemovePending deletes a pending entry once it is chained
+	RemovePending(ctx context.Context, id bson.ObjectID) error
+	// Exists reports whether the chain holds the entry with this ID
+	Exists(ctx context.Context, id bson.ObjectID) (bool, error)
+	// Last returns the entry with the highest sequence number, nil when the
+	// log is empty
+	Last(ctx context.Context) (*entity.AuditLog, error)
+	// Append inserts an entry into the chain, keeping its ID; ErrSeqTaken
+	// means its Seq or ID is already used
+	Append(ctx context.Context, entry *entity.AuditLog) error
+	// FindAll returns a page of the chained entries matching filter, newest
+	// first
+	FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.AuditLog, int64, error)
+	// Stream calls fn for every chained entry matching filter in sequence
+	// order without loading them all into memory
+	Stream(ctx context.Context, filter bson.M, fn func(entry *entity.AuditLog) error) error
+}
+
+type auditRepositoryMongo struct {
+	collection *mongo.Collection
+	pending    *mongo.Collection
+}
+
+// NewAuditRepository creates a new MongoDB audit log repository
+func NewAuditRepository(db *database.MongoDB) AuditRepository {
+	return &auditRepositoryMongo{
+		collection: db.Collection("audit_logs"),
+		pending:    db.Collection("audit_pending"),
+	}
+}
+
+func (r *auditRepositoryMongo) AddPending(ctx context.Context, entry *entity.AuditLog) error {
+	entry.ID = bson.NewObjectID()
+
+	_, err := r.pending.InsertOne(ctx, entry)
+	return err
+}
+
+func (r *auditRepositoryMongo) Pending(ctx context.Context, limit int) ([]*entity.AuditLog, error) {
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.pending.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*entity.AuditLog
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *auditRepositoryMongo) RemovePending(ctx context.Context, id bson.ObjectID) error {
+	_, err := r.pending.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *auditRepositoryMongo) Exists(ctx context.Context, id bson.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *auditRepositoryMongo) Last(ctx context.Context) (*entity.AuditLog, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}})
+
+	var entry entity.AuditLog
+	err := r.collection.FindOne(ctx, bson.M{}, opts).Decode(&entry)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *auditRepositoryMongo) Append(ctx context.Context, entry *entity.AuditLog) error {
+	_, err := r.collection.InsertOne(ctx, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrSeqTaken
+	}
+	return err
+}
+
+func (r *auditRepositoryMongo) FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.AuditLog, int64, error) {
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "seq", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*entity.AuditLog
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+func (r *auditRepositoryMongo) Stream(ctx context.Context, filter bson.M, fn func(entry *entity.AuditLog) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry entity.AuditLog
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
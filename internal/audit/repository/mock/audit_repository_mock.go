@@ -0,0 +1,53 @@
+package mock
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// MockAuditRepository is a mock implementation of repository.AuditRepository
+type MockAuditRepository struct {
+	AddPendingFunc    func(ctx context.Context, entry *entity.AuditLog) error
+	PendingFunc       func(ctx context.Context, limit int) ([]*entity.AuditLog, error)
+	RemovePendingFunc func(ctx context.Context, id bson.ObjectID) error
+	ExistsFunc        func(ctx context.Context, id bson.ObjectID) (bool, error)
+	LastFunc          func(ctx context.Context) (*entity.AuditLog, error)
+	AppendFunc        func(ctx context.Context, entry *entity.AuditLog) error
+	FindAllFunc       func(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.AuditLog, int64, error)
+	StreamFunc        func(ctx context.Context, filter bson.M, fn func(entry *entity.AuditLog) error) error
+}
+
+func (m *MockAuditRepository) AddPending(ctx context.Context, entry *entity.AuditLog) error {
+	return m.AddPendingFunc(ctx, entry)
+}
+
+func (m *MockAuditRepository) Pending(ctx context.Context, limit int) ([]*entity.AuditLog, error) {
+	return m.PendingFunc(ctx, limit)
+}
+
+func (m *MockAuditRepository) RemovePending(ctx context.Context, id bson.ObjectID) error {
+	return m.RemovePendingFunc(ctx, id)
+}
+
+func (m *MockAuditRepository) Exists(ctx context.Context, id bson.ObjectID) (bool, error) {
+	return m.ExistsFunc(ctx, id)
+}
+
+func (m *MockAuditRepository) Last(ctx context.Context) (*entity.AuditLog, error) {
+	return m.LastFunc(ctx)
+}
+
+func (m *MockAuditRepository) Append(ctx context.Context, entry *entity.AuditLog) error {
+	return m.AppendFunc(ctx, entry)
+}
+
+func (m *MockAuditRepository) FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.AuditLog, int64, error) {
+	return m.FindAllFunc(ctx, filter, page, pageSize)
+}
+
+func (m *MockAuditRepository) Stream(ctx context.Context, filter bson.M, fn func(entry *entity.AuditLog) error) error {
+	return m.StreamFunc(ctx, filter, fn)
+}
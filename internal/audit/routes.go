@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/audit/handler"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+)
+
+// RegisterRoutes registers all audit log routes (ADMIN only)
+func RegisterRoutes(router fiber.Router, h *handler.AuditHandler, tokenMaker *token.PasetoMaker) {
+	auditLogs := router.Group("/audit-logs", middleware.AuthMiddleware(tokenMaker), middleware.RequireAdmin())
+
+	auditLogs.Get("/", h.GetAuditLogs)
+	auditLogs.Get("/export", h.ExportAuditLogs)
+	auditLogs.Get("/verify", h.VerifyAuditLogs)
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/audit/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/audit/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// Audited actions
+const (
+	ActionRegister       = "auth.register"
+	ActionLogin          = "auth.login"
+	ActionFirstLogin     = "auth.first_login"
+	ActionLogout         = "auth.logout"
+	ActionUserCreate     = "user.create"
+	ActionUserUpdate     = "user.update"
+	ActionUserDelete     = "user.delete"
+	ActionUserRestore    = "user.restore"
+	ActionPasswordChange = "user.password_change"
+	ActionPasswordSet    = "user.password_set"
+	ActionUserPurge      = "user.purge"
+)
+
+// TargetUser is the target type of actions on user accounts
+const TargetUser = "user"
+
+// chainBatchSize is how many pending entries one Chain call appends
+const chainBatchSize = 100
+
+// errChainBroken stops Verify at the first entry that fails the check
+var errChainBroken = errors.New("audit chain broken")
+
+// Entry describes an action to audit
+type Entry struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	// ActorID and ActorRole default to the authenticated user of the request.
+	// Set them for actions that happen before authentication, such as logins.
+	ActorID   string
+	ActorRole string
+	// Outcome defaults to entity.AuditSuccess
+	Outcome entity.AuditOutcome
+	Reason  string
+	Changes []entity.AuditChange
+}
+
+// Recorder adds entries to the audit log
+type Recorder interface {
+	// Record stores an entry as pending; Chain appends it to the log. It is
+	// called after the audited change was committed, so failures are logged
+	// rather than returned.
+	Record(ctx context.Context, entry Entry)
+}
+
+// AuditService defines the interface for audit log operations
+type AuditService interface {
+	Recorder
+	// Chain appends pending entries to the hash chain in the order they were
+	// recorded and returns how many were appended
+	Chain(ctx context.Context) (int, error)
+	GetAll(ctx context.Context, filter bson.M, page, pageSize int) ([]dto.AuditLogResponse, int64, error)
+	// Export streams every matching entry to fn in sequence order
+	Export(ctx context.Context, filter bson.M, fn func(entry dto.AuditLogResponse) error) error
+	// Verify walks the whole log and checks the hash chain
+	Verify(ctx context.Context) (*dto.VerifyResponse, error)
+}
+
+type auditServiceImpl struct {
+	auditRepo repository.AuditRepository
+}
+
+// NewAuditService creates a new audit log service
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditServiceImpl{
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *auditServiceImpl) Record(ctx context.Context, entry Entry) {
+	// The change is already committed; a client disconnecting must not lose
+	// its audit entry
+	ctx = context.WithoutCancel(ctx)
+
+	// A plain insert: writers never contend, the chainer orders entries later
+	log := s.newLog(ctx, entry)
+	if err := s.auditRepo.AddPending(ctx, log); err != nil {
+		logger.Error("failed to record audit log",
+			zap.Error(err),
+			zap.String("action", log.Action),
+			zap.String("target_id", log.TargetID),
+		)
+	}
+}
+
+func (s *auditServiceImpl) Chain(ctx context.Context) (int, error) {
+	pending, err := s.auditRepo.Pending(ctx, chainBatchSize)
+	if err != nil || len(pending) == 0 {
+		return 0, err
+	}
+
+	last, err := s.auditRepo.Last(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	chained := 0
+	for _, log := range pending {
+		log.Seq, log.PrevHash = 1, ""
+		if last != nil {
+			log.Seq, log.PrevHash = last.Seq+1, last.Hash
+		}
+		log.Hash = computeHash(log)
+
+		err := s.auditRepo.Append(ctx, log)
+		if errors.Is(err, repository.ErrSeqTaken) {
+			// Another chainer moved the head, or this entry was appended
+			// before a crash kept it from being removed from pending. Stop
+			// here; the next call starts from the new head.
+			appended, err := s.auditRepo.Exists(ctx, log.ID)
+			if err != nil {
+				return chained, err
+			}
+			if appended {
+				return chained, s.auditRepo.RemovePending(ctx, log.ID)
+			}
+			return chained, nil
+		}
+		if err != nil {
+			return chained, err
+		}
+
+		if err := s.auditRepo.RemovePending(ctx, log.ID); err != nil {
+			return chained, err
+		}
+		last = log
+		chained++
+	}
+
+	return chained, nil
+}
+
+// StartChainer appends pending entries every interval until ctx is
+// cancelled. Batches run back to back while entries are pending.
+func StartChainer(ctx context.Context, service AuditService, interval time.Duration) {
+	logger.Info("starting audit log chainer", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("audit log chainer stopped")
+			return
+		case <-ticker.C:
+			for {
+				chained, err := service.Chain(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						logger.Error("audit log chainer failed", zap.Error(err))
+					}
+					break
+				}
+				if chained < chainBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (s *auditServiceImpl) newLog(ctx context.Context, entry Entry) *entity.AuditLog {
+	md := MetadataFrom(ctx)
+
+	actorID, actorRole := entry.ActorID, entry.ActorRole
+	if actorID == "" {
+		actorID, actorRole = actorFrom(ctx)
+	}
+	outcome := entry.Outcome
+	if outcome == "" {
+		outcome = entity.AuditSuccess
+	}
+
+	return &entity.AuditLog{
+		ActorID:    actorID,
+		ActorRole:  actorRole,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Outcome:    outcome,
+		Reason:     entry.Reason,
+		Changes:    entry.Changes,
+		IP:         md.IP,
+		UserAgent:  md.UserAgent,
+		RequestID:  md.RequestID,
+		// MongoDB stores milliseconds; the hash must match the stored time
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func (s *auditServiceImpl) GetAll(ctx context.Context, filter bson.M, page, pageSize int) ([]dto.AuditLogResponse, int64, error) {
+	entries, total, err := s.auditRepo.FindAll(ctx, filter, page, pageSize)
+	if err != nil {
+		logger.Error("failed to get audit logs", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return dto.ToAuditLogResponses(entries), total, nil
+}
+
+func (s *auditServiceImpl) Export(ctx context.Context, filter bson.M, fn func(entry dto.AuditLogResponse) error) error {
+	err := s.auditRepo.Stream(ctx, filter, func(entry *entity.AuditLog) error {
+		return fn(dto.ToAuditLogResponse(entry))
+	})
+	if err != nil {
+		logger.Error("failed to export audit logs", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *auditServiceImpl) Verify(ctx context.Context) (*dto.VerifyResponse, error) {
+	result := &dto.VerifyResponse{Valid: true}
+
+	err := s.auditRepo.Stream(ctx, bson.M{}, func(entry *entity.AuditLog) error {
+		result.Checked++
+
+		var reason string
+		switch {
+		case entry.Seq != result.LastSeq+1:
+			reason = "sequence gap, entries were removed"
+		case entry.PrevHash != result.LastHash:
+			reason = "previous hash mismatch, entries were removed or reordered"
+		case computeHash(entry) != entry.Hash:
+			reason = "hash mismatch, entry was modified"
+		}
+		if reason != "" {
+			seq := entry.Seq
+			result.Valid, result.BrokenAt, result.Reason = false, &seq, reason
+			return errChainBroken
+		}
+
+		result.LastSeq, result.LastHash = entry.Seq, entry.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, errChainBroken) {
+		logger.Error("failed to verify audit log", zap.Error(err))
+		return nil, err
+	}
+
+	if !result.Valid {
+		logger.Warn("audit log hash chain is broken", zap.Int64("seq", *result.BrokenAt), zap.String("reason", result.Reason))
+	}
+	return result, nil
+}
+
+// hashedLog is the content an entry's hash covers: everything but its ID
+// and the hash itself. The fields are fixed so the encoding is stable.
+type hashedLog struct {
+	Seq        int64                `json:"seq"`
+	ActorID    string               `json:"actorId"`
+	ActorRole  string               `json:"actorRole"`
+	Action     string               `json:"action"`
+	TargetType string               `json:"targetType"`
+	TargetID   string               `json:"targetId"`
+	Outcome    entity.AuditOutcome  `json:"outcome"`
+	Reason     string               `json:"reason"`
+	Changes    []entity.AuditChange `json:"changes"`
+	IP         string               `json:"ip"`
+	UserAgent  string               `json:"userAgent"`
+	RequestID  string               `json:"requestId"`
+	CreatedAt  string               `json:"createdAt"`
+	PrevHash   string               `json:"prevHash"`
+}
+
+// computeHash returns the hex SHA-256 of the entry's content, which includes
+// the previous entry's hash
+func computeHash(entry *entity.AuditLog) string {
+	changes := entry.Changes
+	if changes == nil {
+		changes = []entity.AuditChange{}
+	}
+
+	// Marshalling a struct of strings and ints can't fail
+	content, _ := json.Marshal(hashedLog{
+		Seq:        entry.Seq,
+		ActorID:    entry.ActorID,
+		ActorRole:  entry.ActorRole,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Outcome:    entry.Outcome,
+		Reason:     entry.Reason,
+		Changes:    changes,
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		RequestID:  entry.RequestID,
+		CreatedAt:  entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:   entry.PrevHash,
+	})
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/audit/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/audit/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// memoryLog is an in-memory audit log whose Append fails with ErrSeqTaken
+// like the unique seq and _id indexes do
+func memoryLog() (*mock.MockAuditRepository, *[]*entity.AuditLog, *[]*entity.AuditLog) {
+	var entries, pending []*entity.AuditLog
+	repo := &mock.MockAuditRepository{
+		AddPendingFunc: func(ctx context.Context, entry *entity.AuditLog) error {
+			entry.ID = bson.NewObjectID()
+			stored := *entry
+			pending = append(pending, &stored)
+			return nil
+		},
+		PendingFunc: func(ctx context.Context, limit int) ([]*entity.AuditLog, error) {
+			var batch []*entity.AuditLog
+			for _, entry := range pending[:min(limit, len(pending))] {
+				copied := *entry
+				batch = append(batch, &copied)
+			}
+			return batch, nil
+		},
+		RemovePendingFunc: func(ctx context.Context, id bson.ObjectID) error {
+			pending = slices.DeleteFunc(pending, func(entry *entity.AuditLog) bool { return entry.ID == id })
+			return nil
+		},
+		ExistsFunc: func(ctx context.Context, id bson.ObjectID) (bool, error) {
+			return slices.ContainsFunc(entries, func(entry *entity.AuditLog) bool { return entry.ID == id }), nil
+		},
+		LastFunc: func(ctx context.Context) (*entity.AuditLog, error) {
+			if len(entries) == 0 {
+				return nil, nil
+			}
+			return entries[len(entries)-1], nil
+		},
+		AppendFunc: func(ctx context.Context, entry *entity.AuditLog) error {
+			for _, existing := range entries {
+				if existing.Seq == entry.Seq || existing.ID == entry.ID {
+					return repository.ErrSeqTaken
+				}
+			}
+			stored := *entry
+			entries = append(entries, &stored)
+			return nil
+		},
+		StreamFunc: func(ctx context.Context, filter bson.M, fn func(entry *entity.AuditLog) error) error {
+			for _, entry := range entries {
+				if err := fn(entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	return repo, &entries, &pending
+}
+
+func TestRecord_ChainsEntries(t *testing.T) {
+	// 1. Setup Mocks and a request with metadata and an authenticated admin
+	repo, entries, pending := memoryLog()
+	service := NewAuditService(repo)
+
+	ctx := WithMetadata(context.Background(), Metadata{IP: "203.0.113.7", UserAgent: "curl/8.0", RequestID: "req-1"})
+	ctx = context.WithValue(ctx, middleware.AuthPayloadKey, &token.Payload{UserID: "admin-1", Role: "ADMIN"})
+
+	// 2. Call Method
+	service.Record(ctx, Entry{Action: ActionUserDelete, TargetType: TargetUser, TargetID: "user-1"})
+	service.Record(ctx, Entry{Action: ActionLogin, ActorID: "user-2", Outcome: entity.AuditFailure, Reason: "invalid email or password"})
+	assert.Len(t, *pending, 2)
+	assert.Empty(t, *entries)
+
+	chained, err := service.Chain(context.Background())
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 2, chained)
+	assert.Empty(t, *pending)
+	if assert.Len(t, *entries, 2) {
+		first, second := (*entries)[0], (*entries)[1]
+		assert.Equal(t, int64(1), first.Seq)
+		assert.Empty(t, first.PrevHash)
+		assert.Equal(t, "admin-1", first.ActorID)
+		assert.Equal(t, entity.AuditSuccess, first.Outcome)
+		assert.Equal(t, "203.0.113.7", first.IP)
+		assert.Equal(t, "req-1", first.RequestID)
+
+		assert.Equal(t, int64(2), second.Seq)
+		assert.Equal(t, first.Hash, second.PrevHash)
+		assert.Equal(t, "user-2", second.ActorID)
+		assert.Empty(t, second.ActorRole)
+	}
+}
+
+func TestChain_AnotherChainerTookTheSeq(t *testing.T) {
+	// 1. Setup Mocks where another replica appends between Last and Append
+	repo, entries, pending := memoryLog()
+	last := repo.LastFunc
+	raced := false
+	repo.LastFunc = func(ctx context.Context) (*entity.AuditLog, error) {
+		head, err := last(ctx)
+		if !raced {
+			raced = true
+			_ = repo.AppendFunc(ctx, &entity.AuditLog{ID: bson.NewObjectID(), Seq: 1, Action: ActionLogout, Hash: "other"})
+		}
+		return head, err
+	}
+	service := NewAuditService(repo)
+	service.Record(context.Background(), Entry{Action: ActionLogin})
+
+	// 2. Call Method
+	chained, err := service.Chain(context.Background())
+
+	// 3. Assertions: the entry stays pending and is chained by the next call
+	assert.NoError(t, err)
+	assert.Zero(t, chained)
+	assert.Len(t, *pending, 1)
+
+	chained, err = service.Chain(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, chained)
+	assert.Empty(t, *pending)
+	if assert.Len(t, *entries, 2) {
+		assert.Equal(t, int64(2), (*entries)[1].Seq)
+		assert.Equal(t, "other", (*entries)[1].PrevHash)
+	}
+}
+
+func TestChain_EntryAlreadyAppended(t *testing.T) {
+	// 1. Setup Mocks where a chainer crashed after appending an entry but
+	// before removing it from pending
+	repo, entries, pending := memoryLog()
+	service := NewAuditService(repo)
+	service.Record(context.Background(), Entry{Action: ActionLogin})
+	service.Record(context.Background(), Entry{Action: ActionLogout})
+	_, err := service.Chain(context.Background())
+	assert.NoError(t, err)
+	*pending = append(*pending, (*entries)[1])
+
+	// 2. Call Method
+	chained, err := service.Chain(context.Background())
+
+	// 3. Assertions: the entry isn't appended twice
+	assert.NoError(t, err)
+	assert.Zero(t, chained)
+	assert.Empty(t, *pending)
+	assert.Len(t, *entries, 2)
+}
+
+func TestVerify(t *testing.T) {
+	// 1. Setup Mocks with a chain of three entries
+	repo, entries, _ := memoryLog()
+	service := NewAuditService(repo)
+	for _, action := range []string{ActionLogin, ActionUserUpdate, ActionLogout} {
+		service.Record(context.Background(), Entry{Action: action})
+	}
+	_, err := service.Chain(context.Background())
+	assert.NoError(t, err)
+
+	// 2. Call Method on the intact chain
+	result, err := service.Verify(context.Background())
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int64(3), result.Checked)
+	assert.Equal(t, int64(3), result.LastSeq)
+	assert.Equal(t, (*entries)[2].Hash, result.LastHash)
+
+	// 4. Modify an entry and remove another
+	(*entries)[1].Reason = "edited"
+	result, err = service.Verify(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(2), *result.BrokenAt)
+
+	*entries = append((*entries)[:1], (*entries)[2:]...)
+	result, err = service.Verify(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(3), *result.BrokenAt)
+}
+
+func TestDiff(t *testing.T) {
+	type user struct {
+		Name     string `json:"name"`
+		Role     string `json:"role"`
+		Active   bool   `json:"active"`
+		Team     string `json:"team,omitempty"`
+		Modified string `json:"modified"`
+	}
+
+	before := user{Name: "Jane", Role: "USER", Active: true, Modified: "yesterday"}
+	after := user{Name: "Jane", Role: "ADMIN", Active: true, Team: "core", Modified: "today"}
+
+	assert.Equal(t, []entity.AuditChange{
+		{Field: "role", Before: `"USER"`, After: `"ADMIN"`},
+		{Field: "team", After: `"core"`},
+	}, Diff(before, after, "modified"))
+	assert.Len(t, Diff(nil, after, "modified"), 4)
+}
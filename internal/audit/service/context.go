@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+)
+
+// MetadataKey is the Locals key the audit middleware stores request metadata
+// under. Handlers pass the request context to services, so services find it
+// through ctx.Value.
+const MetadataKey = "audit_metadata"
+
+// Metadata describes where an audited action came from
+type Metadata struct {
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+type metadataContextKey struct{}
+
+// WithMetadata attaches request metadata to ctx, for callers outside HTTP
+// handlers
+func WithMetadata(ctx context.Context, md Metadata) context.Context {
+	return context.WithValue(ctx, metadataContextKey{}, md)
+}
+
+// MetadataFrom returns the request metadata of ctx, zero outside requests
+func MetadataFrom(ctx context.Context) Metadata {
+	if md, ok := ctx.Value(metadataContextKey{}).(Metadata); ok {
+		return md
+	}
+	if md, ok := ctx.Value(MetadataKey).(Metadata); ok {
+		return md
+	}
+	return Metadata{}
+}
+
+// actorFrom returns the authenticated user of the request ctx belongs to.
+// AuthMiddleware runs after the audit middleware, so the actor is looked up
+// when the entry is recorded rather than captured with the metadata.
+func actorFrom(ctx context.Context) (id, role string) {
+	if payload, ok := ctx.Value(middleware.AuthPayloadKey).(*token.Payload); ok {
+		return payload.UserID, payload.Role
+	}
+	return "", ""
+}
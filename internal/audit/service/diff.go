@@ -0,0 +1,62 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// Diff compares the JSON encodings of two snapshots, typically response DTOs,
+// and returns the top-level fields that differ, sorted by name. Fields in
+// ignore, such as timestamps and versions, are skipped.
+func Diff(before, after any, ignore ...string) []entity.AuditChange {
+	beforeFields := jsonFields(before)
+	afterFields := jsonFields(after)
+
+	skip := make(map[string]bool, len(ignore))
+	for _, field := range ignore {
+		skip[field] = true
+	}
+
+	names := make([]string, 0, len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []entity.AuditChange
+	for _, name := range names {
+		if skip[name] || bytes.Equal(beforeFields[name], afterFields[name]) {
+			continue
+		}
+		changes = append(changes, entity.AuditChange{
+			Field:  name,
+			Before: string(beforeFields[name]),
+			After:  string(afterFields[name]),
+		})
+	}
+	return changes
+}
+
+// Redacted records that a secret field changed without its values
+func Redacted(field string) entity.AuditChange {
+	return entity.AuditChange{Field: field}
+}
+
+func jsonFields(v any) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if v == nil {
+		return fields
+	}
+	if data, err := json.Marshal(v); err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
+	return fields
+}
@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+)
+
+// MockRecorder is a mock implementation of service.Recorder that keeps
+// recorded entries in Entries
+type MockRecorder struct {
+	Entries []service.Entry
+}
+
+func (m *MockRecorder) Record(ctx context.Context, entry service.Entry) {
+	m.Entries = append(m.Entries, entry)
+}
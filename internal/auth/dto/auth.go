@@ -14,6 +14,13 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// FirstLoginRequest represents the request to replace a temporary password
+type FirstLoginRequest struct {
+	Email       string `json:"email" validate:"required,email"`
+	Password    string `json:"password" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=8,nefield=Password"`
+}
+
 // RefreshTokenRequest represents the refresh token request body
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refreshToken" validate:"required"`
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/auth/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/auth/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/validator"
+)
+
+// FirstLogin godoc
+// @Summary      Complete first login
+// @Description  Replace the temporary password assigned by an admin and log in
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.FirstLoginRequest true "First login request"
+// @Success      200 {object} response.Response{data=dto.AuthResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /auth/first-login [post]
+func (h *AuthHandler) FirstLogin(c *fiber.Ctx) error {
+	var req dto.FirstLoginRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.BadRequest(c, "invalid request body", validator.FormatValidationErrors(err))
+	}
+
+	result, err := h.authService.CompleteFirstLogin(c.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			return response.Unauthorized(c, "invalid email or password")
+		}
+		if errors.Is(err, service.ErrUserNotActive) {
+			return response.Forbidden(c, "user account is not active")
+		}
+		if errors.Is(err, service.ErrPasswordChangeNotRequired) {
+			return response.BadRequest(c, "password change not required, use /auth/login", "")
+		}
+		return response.InternalServerError(c, "failed to complete first login")
+	}
+
+	return response.Success(c, fiber.StatusOK, "login successful", result)
+}
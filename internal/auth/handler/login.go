@@ -21,6 +21,7 @@ import (
 // @Success      200 {object} response.Response{data=dto.AuthResponse}
 // @Failure      400 {object} response.Response
 // @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
 // @Failure      500 {object} response.Response
 // @Router       /auth/login [post]
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
@@ -37,6 +38,9 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		if errors.Is(err, service.ErrUserNotActive) {
 			return response.Forbidden(c, "user account is not active")
 		}
+		if errors.Is(err, service.ErrPasswordChangeRequired) {
+			return response.Error(c, fiber.StatusForbidden, "temporary password must be changed via /auth/first-login", "PASSWORD_CHANGE_REQUIRED", "")
+		}
 		return response.InternalServerError(c, "failed to login")
 	}
 
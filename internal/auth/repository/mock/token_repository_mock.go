@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+	"time"
+)
+
+// MockTokenRepository is a mock implementation of repository.TokenRepository
+type MockTokenRepository struct {
+	StoreFunc  func(ctx context.Context, userID string, token string, expiration time.Duration) error
+	GetFunc    func(ctx context.Context, userID string) (string, error)
+	DeleteFunc func(ctx context.Context, userID string) error
+	ExistsFunc func(ctx context.Context, userID string) (bool, error)
+}
+
+func (m *MockTokenRepository) Store(ctx context.Context, userID string, token string, expiration time.Duration) error {
+	return m.StoreFunc(ctx, userID, token, expiration)
+}
+
+func (m *MockTokenRepository) Get(ctx context.Context, userID string) (string, error) {
+	return m.GetFunc(ctx, userID)
+}
+
+func (m *MockTokenRepository) Delete(ctx context.Context, userID string) error {
+	return m.DeleteFunc(ctx, userID)
+}
+
+func (m *MockTokenRepository) Exists(ctx context.Context, userID string) (bool, error) {
+	return m.ExistsFunc(ctx, userID)
+}
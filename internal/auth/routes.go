@@ -15,6 +15,7 @@ func RegisterRoutes(router fiber.Router, h *handler.AuthHandler, tokenMaker *tok
 	// Public routes
 	auth.Post("/register", h.Register)
 	auth.Post("/login", h.Login)
+	auth.Post("/first-login", h.FirstLogin)
 	auth.Post("/refresh", h.RefreshToken)
 
 	// Protected routes
@@ -3,13 +3,20 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
+	auditService "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/auth/dto"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/auth/repository"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/config"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+	loginHistoryService "github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/service"
+	userDto "github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/events"
 	userRepo "github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
 	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
@@ -21,35 +28,54 @@ var (
 	ErrInvalidCredentials  = errors.New("invalid email or password")
 	ErrInvalidRefreshToken = errors.New("invalid refresh token")
 	ErrUserNotActive       = errors.New("user account is not active")
+
+	ErrPasswordChangeRequired    = errors.New("password change required")
+	ErrPasswordChangeNotRequired = errors.New("password change not required")
 )
 
 // AuthService defines the interface for authentication operations
 type AuthService interface {
 	Register(ctx context.Context, req *dto.RegisterRequest) (*dto.AuthResponse, error)
 	Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error)
+	CompleteFirstLogin(ctx context.Context, req *dto.FirstLoginRequest) (*dto.AuthResponse, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*dto.TokenResponse, error)
 	Logout(ctx context.Context, userID string) error
 }
 
 type authServiceImpl struct {
 	userRepo   userRepo.UserRepository
+	statsRepo  userRepo.UserStatsRepository
 	tokenRepo  repository.TokenRepository
 	tokenMaker *token.PasetoMaker
 	config     *config.Config
+	txManager  database.TxManager
+	outbox     event.Outbox
+	auditor    auditService.Recorder
+	logins     loginHistoryService.Recorder
 }
 
 // NewAuthService creates a new authentication service
 func NewAuthService(
 	userRepository userRepo.UserRepository,
+	userStatsRepository userRepo.UserStatsRepository,
 	tokenRepository repository.TokenRepository,
 	tokenMaker *token.PasetoMaker,
 	cfg *config.Config,
+	txManager database.TxManager,
+	outbox event.Outbox,
+	auditor auditService.Recorder,
+	logins loginHistoryService.Recorder,
 ) AuthService {
 	return &authServiceImpl{
 		userRepo:   userRepository,
+		statsRepo:  userStatsRepository,
 		tokenRepo:  tokenRepository,
 		tokenMaker: tokenMaker,
 		config:     cfg,
+		txManager:  txManager,
+		outbox:     outbox,
+		auditor:    auditor,
+		logins:     logins,
 	}
 }
 
@@ -81,102 +107,160 @@ func (s *authServiceImpl) Register(ctx context.Context, req *dto.RegisterRequest
 		IsActive:  true,
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Create(txCtx, user); err != nil {
+			return err
+		}
+		return event.Record(txCtx, s.outbox, events.TypeRegistered, user.ID.Hex(), events.UserPayload{User: userDto.ToUserResponse(user)})
+	})
+	if err != nil {
 		logger.Error("failed to create user", zap.Error(err))
 		return nil, err
 	}
 
-	// Generate tokens
-	accessToken, _, err := s.tokenMaker.CreateAccessToken(
-		user.ID.Hex(),
-		string(user.Role),
-		s.config.Token.AccessTokenDuration,
-	)
+	result, err := s.issueTokens(ctx, user)
 	if err != nil {
-		logger.Error("failed to create access token", zap.Error(err))
 		return nil, err
 	}
 
-	refreshToken, _, err := s.tokenMaker.CreateRefreshToken(
-		user.ID.Hex(),
-		string(user.Role),
-		s.config.Token.RefreshTokenDuration,
-	)
-	if err != nil {
-		logger.Error("failed to create refresh token", zap.Error(err))
-		return nil, err
-	}
+	s.auditor.Record(ctx, auditService.Entry{
+		Action:     auditService.ActionRegister,
+		TargetType: auditService.TargetUser,
+		TargetID:   user.ID.Hex(),
+		ActorID:    user.ID.Hex(),
+		ActorRole:  string(user.Role),
+	})
 
-	// Store refresh token in Redis
-	if err := s.tokenRepo.Store(ctx, user.ID.Hex(), refreshToken, s.config.Token.RefreshTokenDuration); err != nil {
-		logger.Error("failed to store refresh token", zap.Error(err))
+	logger.Info("user registered successfully", zap.String("user_id", user.ID.Hex()))
+	return result, nil
+}
+
+func (s *authServiceImpl) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
+	user, result, err := s.login(ctx, req)
+	s.recordAttempt(ctx, auditService.ActionLogin, req.Email, user, err)
+	if err != nil {
 		return nil, err
 	}
 
-	logger.Info("user registered successfully", zap.String("user_id", user.ID.Hex()))
-
-	return &dto.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User:         toUserResponse(user),
-	}, nil
+	s.trackLogin(ctx, user)
+	logger.Info("user logged in successfully", zap.String("user_id", user.ID.Hex()))
+	return result, nil
 }
 
-func (s *authServiceImpl) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
+// login checks the credentials and issues tokens. The user is returned
+// whenever the email matched an account, so failed attempts can be audited.
+func (s *authServiceImpl) login(ctx context.Context, req *dto.LoginRequest) (*entity.User, *dto.AuthResponse, error) {
 	// Find user by email
 	user, err := s.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, userRepo.ErrUserNotFound) {
-			return nil, ErrInvalidCredentials
+			return nil, nil, ErrInvalidCredentials
 		}
 		logger.Error("failed to find user", zap.Error(err))
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Soft-deleted accounts cannot log in
+	if user.IsDeleted() {
+		return user, nil, ErrInvalidCredentials
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		return nil, ErrUserNotActive
+		return user, nil, ErrUserNotActive
 	}
 
 	// Compare password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, ErrInvalidCredentials
+		return user, nil, ErrInvalidCredentials
 	}
 
-	// Generate tokens
-	accessToken, _, err := s.tokenMaker.CreateAccessToken(
-		user.ID.Hex(),
-		string(user.Role),
-		s.config.Token.AccessTokenDuration,
-	)
+	// Temporary passwords must be replaced through CompleteFirstLogin
+	if user.MustChangePassword {
+		return user, nil, ErrPasswordChangeRequired
+	}
+
+	result, err := s.issueTokens(ctx, user)
 	if err != nil {
-		logger.Error("failed to create access token", zap.Error(err))
-		return nil, err
+		return user, nil, err
 	}
 
-	refreshToken, _, err := s.tokenMaker.CreateRefreshToken(
-		user.ID.Hex(),
-		string(user.Role),
-		s.config.Token.RefreshTokenDuration,
-	)
+	return user, result, nil
+}
+
+// CompleteFirstLogin replaces a temporary password set by an admin and logs
+// the user in
+func (s *authServiceImpl) CompleteFirstLogin(ctx context.Context, req *dto.FirstLoginRequest) (*dto.AuthResponse, error) {
+	user, result, err := s.completeFirstLogin(ctx, req)
+	s.recordAttempt(ctx, auditService.ActionFirstLogin, req.Email, user, err)
 	if err != nil {
-		logger.Error("failed to create refresh token", zap.Error(err))
 		return nil, err
 	}
 
-	// Store refresh token in Redis
-	if err := s.tokenRepo.Store(ctx, user.ID.Hex(), refreshToken, s.config.Token.RefreshTokenDuration); err != nil {
-		logger.Error("failed to store refresh token", zap.Error(err))
-		return nil, err
+	s.trackLogin(ctx, user)
+	logger.Info("temporary password replaced on first login", zap.String("user_id", user.ID.Hex()))
+	return result, nil
+}
+
+func (s *authServiceImpl) completeFirstLogin(ctx context.Context, req *dto.FirstLoginRequest) (*entity.User, *dto.AuthResponse, error) {
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, userRepo.ErrUserNotFound) {
+			return nil, nil, ErrInvalidCredentials
+		}
+		logger.Error("failed to find user", zap.Error(err))
+		return nil, nil, err
 	}
 
-	logger.Info("user logged in successfully", zap.String("user_id", user.ID.Hex()))
+	if user.IsDeleted() {
+		return user, nil, ErrInvalidCredentials
+	}
 
-	return &dto.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User:         toUserResponse(user),
-	}, nil
+	if !user.IsActive {
+		return user, nil, ErrUserNotActive
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return user, nil, ErrInvalidCredentials
+	}
+
+	if !user.MustChangePassword {
+		return user, nil, ErrPasswordChangeNotRequired
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		logger.Error("failed to hash password", zap.Error(err))
+		return user, nil, err
+	}
+
+	update := userRepo.NewUserUpdate().
+		Set(userRepo.FieldPassword, string(hashedPassword)).
+		Set(userRepo.FieldMustChangePassword, false)
+	var updated *entity.User
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		updated, err = s.userRepo.Update(txCtx, user.ID.Hex(), user.Version, update)
+		if err != nil {
+			return err
+		}
+		return event.Record(txCtx, s.outbox, events.TypePasswordChanged, user.ID.Hex(), events.UserRefPayload{UserID: user.ID.Hex()})
+	})
+	if err != nil {
+		if errors.Is(err, userRepo.ErrVersionConflict) || errors.Is(err, userRepo.ErrUserNotFound) {
+			return user, nil, ErrInvalidCredentials
+		}
+		logger.Error("failed to replace temporary password", zap.Error(err))
+		return user, nil, err
+	}
+	user = updated
+
+	result, err := s.issueTokens(ctx, user)
+	if err != nil {
+		return user, nil, err
+	}
+
+	return user, result, nil
 }
 
 func (s *authServiceImpl) RefreshToken(ctx context.Context, refreshTokenStr string) (*dto.TokenResponse, error) {
@@ -197,10 +281,25 @@ func (s *authServiceImpl) RefreshToken(ctx context.Context, refreshTokenStr stri
 		return nil, ErrInvalidRefreshToken
 	}
 
-	// Generate new tokens
+	// Reject sessions of users that were deleted or deactivated after the
+	// token was issued
+	user, err := s.userRepo.FindByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, userRepo.ErrUserNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		logger.Error("failed to find user for token refresh", zap.Error(err))
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Generate new tokens with the current role, so a role change applies
+	// from the next refresh
 	accessToken, _, err := s.tokenMaker.CreateAccessToken(
 		payload.UserID,
-		payload.Role,
+		string(user.Role),
 		s.config.Token.AccessTokenDuration,
 	)
 	if err != nil {
@@ -210,7 +309,7 @@ func (s *authServiceImpl) RefreshToken(ctx context.Context, refreshTokenStr stri
 
 	newRefreshToken, _, err := s.tokenMaker.CreateRefreshToken(
 		payload.UserID,
-		payload.Role,
+		string(user.Role),
 		s.config.Token.RefreshTokenDuration,
 	)
 	if err != nil {
@@ -239,10 +338,102 @@ func (s *authServiceImpl) Logout(ctx context.Context, userID string) error {
 		return err
 	}
 
+	s.auditor.Record(ctx, auditService.Entry{
+		Action:     auditService.ActionLogout,
+		TargetType: auditService.TargetUser,
+		TargetID:   userID,
+	})
+
 	logger.Info("user logged out successfully", zap.String("user_id", userID))
 	return nil
 }
 
+// recordAttempt records a login attempt in the audit log and the login
+// history. user is nil when the email matched no account; the attempt is then
+// recorded without an actor.
+func (s *authServiceImpl) recordAttempt(ctx context.Context, action, email string, user *entity.User, err error) {
+	attempt := loginHistoryService.Attempt{Email: email, User: user}
+	entry := auditService.Entry{Action: action, TargetType: auditService.TargetUser}
+	if user != nil {
+		entry.TargetID = user.ID.Hex()
+		entry.ActorID = user.ID.Hex()
+		entry.ActorRole = string(user.Role)
+	}
+	if action == auditService.ActionFirstLogin && err == nil {
+		entry.Changes = []entity.AuditChange{auditService.Redacted("password")}
+	}
+	if err != nil {
+		entry.Outcome = entity.AuditFailure
+		entry.Reason = failureReason(err)
+		attempt.Reason = entry.Reason
+	}
+	s.auditor.Record(ctx, entry)
+	s.logins.RecordLogin(ctx, attempt)
+}
+
+// trackLogin updates the user's last login and login counts. Failures are
+// logged rather than returned, since the user is already logged in.
+func (s *authServiceImpl) trackLogin(ctx context.Context, user *entity.User) {
+	ctx = context.WithoutCancel(ctx)
+
+	if err := s.userRepo.RecordLogin(ctx, user.ID.Hex(), time.Now()); err != nil {
+		logger.Warn("failed to record last login", zap.Error(err), zap.String("user_id", user.ID.Hex()))
+	}
+	if err := s.statsRepo.IncrementLoginCount(ctx, user.ID); err != nil {
+		logger.Warn("failed to count login", zap.Error(err), zap.String("user_id", user.ID.Hex()))
+	}
+}
+
+// failureReason describes why a login attempt failed without exposing
+// internal errors
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidCredentials),
+		errors.Is(err, ErrUserNotActive),
+		errors.Is(err, ErrPasswordChangeRequired),
+		errors.Is(err, ErrPasswordChangeNotRequired):
+		return err.Error()
+	}
+	return "internal error"
+}
+
+// issueTokens creates an access/refresh token pair for the user and stores the
+// refresh token in Redis
+func (s *authServiceImpl) issueTokens(ctx context.Context, user *entity.User) (*dto.AuthResponse, error) {
+	// Generate tokens
+	accessToken, _, err := s.tokenMaker.CreateAccessToken(
+		user.ID.Hex(),
+		string(user.Role),
+		s.config.Token.AccessTokenDuration,
+	)
+	if err != nil {
+		logger.Error("failed to create access token", zap.Error(err))
+		return nil, err
+	}
+
+	refreshToken, _, err := s.tokenMaker.CreateRefreshToken(
+		user.ID.Hex(),
+		string(user.Role),
+		s.config.Token.RefreshTokenDuration,
+	)
+	if err != nil {
+		logger.Error("failed to create refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	// Store refresh token in Redis
+	if err := s.tokenRepo.Store(ctx, user.ID.Hex(), refreshToken, s.config.Token.RefreshTokenDuration); err != nil {
+		logger.Error("failed to store refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	return &dto.AuthResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		User:         toUserResponse(user),
+	}, nil
+}
+
 func toUserResponse(user *entity.User) dto.UserResponse {
 	return dto.UserResponse{
 		ID:        user.ID.Hex(),
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/crypto/bcrypt"
+
+	auditService "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+	auditMock "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/auth/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/auth/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/config"
+	eventMock "github.com/itsahyarr/gofiber-boilerplate/internal/event/mock"
+	loginHistoryMock "github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/service/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/events"
+	userRepo "github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	userMock "github.com/itsahyarr/gofiber-boilerplate/internal/user/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+const testPassword = "correct horse battery"
+
+// fixture holds the collaborators of an AuthService under test. The login
+// side effects are recorded in auditor, logins, trackedAt and counted.
+type fixture struct {
+	userRepo  *userMock.MockUserRepository
+	statsRepo *userMock.MockUserStatsRepository
+	outbox    *eventMock.MockOutbox
+	auditor   *auditMock.MockRecorder
+	logins    *loginHistoryMock.MockRecorder
+	trackedAt []time.Time
+	counted   []bson.ObjectID
+	tokens    map[string]string
+}
+
+func newFixture(t *testing.T, user *entity.User) (*fixture, AuthService) {
+	t.Helper()
+	f := &fixture{
+		outbox:  &eventMock.MockOutbox{},
+		auditor: &auditMock.MockRecorder{},
+		logins:  &loginHistoryMock.MockRecorder{},
+		tokens:  map[string]string{},
+	}
+	f.userRepo = &userMock.MockUserRepository{
+		FindByEmailFunc: func(ctx context.Context, email string) (*entity.User, error) {
+			if user == nil {
+				return nil, userRepo.ErrUserNotFound
+			}
+			return user, nil
+		},
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.User, error) {
+			if user == nil || user.ID.Hex() != id {
+				return nil, userRepo.ErrUserNotFound
+			}
+			return user, nil
+		},
+		RecordLoginFunc: func(ctx context.Context, id string, at time.Time) error {
+			assert.Equal(t, user.ID.Hex(), id)
+			f.trackedAt = append(f.trackedAt, at)
+			return nil
+		},
+	}
+	f.statsRepo = &userMock.MockUserStatsRepository{
+		IncrementLoginCountFunc: func(ctx context.Context, userID bson.ObjectID) error {
+			f.counted = append(f.counted, userID)
+			return nil
+		},
+	}
+	tokenRepo := &mock.MockTokenRepository{
+		StoreFunc: func(ctx context.Context, userID string, token string, expiration time.Duration) error {
+			f.tokens[userID] = token
+			return nil
+		},
+		GetFunc: func(ctx context.Context, userID string) (string, error) {
+			return f.tokens[userID], nil
+		},
+	}
+
+	tokenMaker, err := token.NewPasetoMaker("01234567890123456789012345678901")
+	require.NoError(t, err)
+	cfg := &config.Config{Token: config.TokenConfig{AccessTokenDuration: time.Minute, RefreshTokenDuration: time.Hour}}
+
+	service := NewAuthService(f.userRepo, f.statsRepo, tokenRepo, tokenMaker, cfg, database.NewNoopTxManager(), f.outbox, f.auditor, f.logins)
+	return f, service
+}
+
+func testUser(t *testing.T) *entity.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+	return &entity.User{ID: bson.NewObjectID(), Email: "jane@example.com", Password: string(hash), Role: entity.RoleUser, IsActive: true, Version: 1}
+}
+
+func TestLogin_RecordsAttemptAndTracksLogin(t *testing.T) {
+	// 1. Setup Mocks
+	user := testUser(t)
+	f, service := newFixture(t, user)
+
+	// 2. Call Method
+	res, err := service.Login(context.Background(), &dto.LoginRequest{Email: user.Email, Password: testPassword})
+
+	// 3. Assertions
+	require.NoError(t, err)
+	assert.NotEmpty(t, res.AccessToken)
+
+	if assert.Len(t, f.auditor.Entries, 1) {
+		entry := f.auditor.Entries[0]
+		assert.Equal(t, auditService.ActionLogin, entry.Action)
+		assert.Equal(t, user.ID.Hex(), entry.ActorID)
+		assert.Equal(t, string(entity.RoleUser), entry.ActorRole)
+		assert.Equal(t, user.ID.Hex(), entry.TargetID)
+		assert.Empty(t, entry.Outcome)
+		assert.Empty(t, entry.Changes)
+	}
+	if assert.Len(t, f.logins.Attempts, 1) {
+		assert.Equal(t, user, f.logins.Attempts[0].User)
+		assert.Empty(t, f.logins.Attempts[0].Reason)
+	}
+	if assert.Len(t, f.trackedAt, 1) {
+		assert.WithinDuration(t, time.Now(), f.trackedAt[0], time.Minute)
+	}
+	assert.Equal(t, []bson.ObjectID{user.ID}, f.counted)
+}
+
+func TestLogin_FailedAttempts(t *testing.T) {
+	inactive := testUser(t)
+	inactive.IsActive = false
+	deleted := testUser(t)
+	deletedAt := time.Now()
+	deleted.DeletedAt = &deletedAt
+	temporary := testUser(t)
+	temporary.MustChangePassword = true
+
+	tests := []struct {
+		name     string
+		user     *entity.User
+		password string
+		wantErr  error
+	}{
+		{"wrong password", testUser(t), "wrong", ErrInvalidCredentials},
+		{"inactive user", inactive, testPassword, ErrUserNotActive},
+		{"deleted user", deleted, testPassword, ErrInvalidCredentials},
+		{"temporary password", temporary, testPassword, ErrPasswordChangeRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 1. Setup Mocks
+			f, service := newFixture(t, tt.user)
+
+			// 2. Call Method
+			res, err := service.Login(context.Background(), &dto.LoginRequest{Email: tt.user.Email, Password: tt.password})
+
+			// 3. Assertions: audited as a failure of the account, never tracked
+			assert.Nil(t, res)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if assert.Len(t, f.auditor.Entries, 1) {
+				entry := f.auditor.Entries[0]
+				assert.Equal(t, entity.AuditFailure, entry.Outcome)
+				assert.Equal(t, tt.wantErr.Error(), entry.Reason)
+				assert.Equal(t, tt.user.ID.Hex(), entry.ActorID)
+			}
+			if assert.Len(t, f.logins.Attempts, 1) {
+				assert.Equal(t, tt.wantErr.Error(), f.logins.Attempts[0].Reason)
+			}
+			assert.Empty(t, f.trackedAt)
+			assert.Empty(t, f.counted)
+		})
+	}
+}
+
+func TestLogin_UnknownEmail(t *testing.T) {
+	// 1. Setup Mocks with no matching account
+	f, service := newFixture(t, nil)
+
+	// 2. Call Method
+	_, err := service.Login(context.Background(), &dto.LoginRequest{Email: "nobody@example.com", Password: testPassword})
+
+	// 3. Assertions: recorded without an actor
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	if assert.Len(t, f.auditor.Entries, 1) {
+		assert.Empty(t, f.auditor.Entries[0].ActorID)
+		assert.Empty(t, f.auditor.Entries[0].TargetID)
+	}
+	if assert.Len(t, f.logins.Attempts, 1) {
+		assert.Nil(t, f.logins.Attempts[0].User)
+		assert.Equal(t, "nobody@example.com", f.logins.Attempts[0].Email)
+	}
+}
+
+func TestLogin_InternalErrorIsNotExposed(t *testing.T) {
+	// 1. Setup Mocks where the database fails
+	f, service := newFixture(t, nil)
+	f.userRepo.FindByEmailFunc = func(ctx context.Context, email string) (*entity.User, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	// 2. Call Method
+	_, err := service.Login(context.Background(), &dto.LoginRequest{Email: "jane@example.com", Password: testPassword})
+
+	// 3. Assertions
+	assert.Error(t, err)
+	if assert.Len(t, f.auditor.Entries, 1) {
+		assert.Equal(t, "internal error", f.auditor.Entries[0].Reason)
+	}
+}
+
+func TestLogin_TrackingFailureKeepsLogin(t *testing.T) {
+	// 1. Setup Mocks where recording the login fails
+	user := testUser(t)
+	f, service := newFixture(t, user)
+	f.userRepo.RecordLoginFunc = func(ctx context.Context, id string, at time.Time) error {
+		return errors.New("write conflict")
+	}
+	f.statsRepo.IncrementLoginCountFunc = func(ctx context.Context, userID bson.ObjectID) error {
+		return errors.New("write conflict")
+	}
+
+	// 2. Call Method
+	res, err := service.Login(context.Background(), &dto.LoginRequest{Email: user.Email, Password: testPassword})
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+	assert.Len(t, f.auditor.Entries, 1)
+}
+
+func TestCompleteFirstLogin_AuditsPasswordChange(t *testing.T) {
+	// 1. Setup Mocks with a user holding a temporary password
+	user := testUser(t)
+	user.MustChangePassword = true
+	f, service := newFixture(t, user)
+	var gotVersion int64
+	f.userRepo.UpdateFunc = func(ctx context.Context, id string, expectedVersion int64, update *userRepo.UserUpdate) (*entity.User, error) {
+		gotVersion = expectedVersion
+		updated := *user
+		updated.MustChangePassword = false
+		updated.Version++
+		return &updated, nil
+	}
+
+	// 2. Call Method
+	res, err := service.CompleteFirstLogin(context.Background(), &dto.FirstLoginRequest{Email: user.Email, Password: testPassword, NewPassword: "a new passphrase"})
+
+	// 3. Assertions: the password change is audited without its value
+	require.NoError(t, err)
+	assert.NotNil(t, res)
+	assert.Equal(t, int64(1), gotVersion)
+	if assert.Len(t, f.outbox.Events, 1) {
+		assert.Equal(t, events.TypePasswordChanged, f.outbox.Events[0].Type)
+	}
+	if assert.Len(t, f.auditor.Entries, 1) {
+		entry := f.auditor.Entries[0]
+		assert.Equal(t, auditService.ActionFirstLogin, entry.Action)
+		assert.Equal(t, []entity.AuditChange{auditService.Redacted("password")}, entry.Changes)
+	}
+	assert.Len(t, f.logins.Attempts, 1)
+	assert.Len(t, f.trackedAt, 1)
+	assert.Len(t, f.counted, 1)
+}
+
+func TestRefreshToken_IssuesCurrentRole(t *testing.T) {
+	// 1. Setup Mocks
+	user := testUser(t)
+	user.Role = entity.RoleAdmin
+	_, service := newFixture(t, user)
+	login, err := service.Login(context.Background(), &dto.LoginRequest{Email: user.Email, Password: testPassword})
+	require.NoError(t, err)
+	user.Role = entity.RoleUser
+
+	// 2. Call Method
+	res, err := service.RefreshToken(context.Background(), login.RefreshToken)
+
+	// 3. Assertions: the demoted user no longer gets admin tokens
+	require.NoError(t, err)
+	tokenMaker, err := token.NewPasetoMaker("01234567890123456789012345678901")
+	require.NoError(t, err)
+	payload, err := tokenMaker.VerifyToken(res.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, string(entity.RoleUser), payload.Role)
+}
+
+func TestRefreshToken_RejectsDeactivatedUser(t *testing.T) {
+	// 1. Setup Mocks
+	user := testUser(t)
+	_, service := newFixture(t, user)
+	login, err := service.Login(context.Background(), &dto.LoginRequest{Email: user.Email, Password: testPassword})
+	require.NoError(t, err)
+	user.IsActive = false
+
+	// 2. Call Method
+	_, err = service.RefreshToken(context.Background(), login.RefreshToken)
+
+	// 3. Assertions
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -9,11 +10,18 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Token    TokenConfig
-	App      AppConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Token     TokenConfig
+	User      UserConfig
+	Event     EventConfig
+	Webhook   WebhookConfig
+	Queue     QueueConfig
+	Scheduler SchedulerConfig
+	Mail      MailConfig
+	Login     LoginConfig
+	App       AppConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -23,6 +31,12 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	// ProxyHeader carries the client IP behind a load balancer, e.g.
+	// X-Forwarded-For. It is only read from requests sent by TrustedProxies
+	// (IPs or CIDR ranges), and only back to the first hop that isn't one;
+	// otherwise the connection's address is used.
+	ProxyHeader    string
+	TrustedProxies []string
 }
 
 // DatabaseConfig holds MongoDB configuration
@@ -46,8 +60,75 @@ type TokenConfig struct {
 	RefreshTokenDuration time.Duration
 }
 
+// UserConfig holds user lifecycle configuration
+type UserConfig struct {
+	DeletedRetention time.Duration
+	PurgeSchedule    string
+	LookupCacheTTL   time.Duration
+	CacheEnabled     bool
+	CacheTTL         time.Duration
+	// LastSeenInterval is how often a user's lastSeenAt is written at most
+	LastSeenInterval time.Duration
+}
+
+// EventConfig holds domain event relay configuration
+type EventConfig struct {
+	Stream         string
+	StreamMaxLen   int64
+	RelayInterval  time.Duration
+	RelayBatchSize int
+}
+
+// WebhookConfig holds webhook delivery configuration
+type WebhookConfig struct {
+	Timeout        time.Duration
+	MaxAttempts    int
+	WorkerInterval time.Duration
+	BatchSize      int
+	// AllowPrivateTargets permits webhook URLs on loopback and private
+	// networks, for local development only
+	AllowPrivateTargets bool
+}
+
+// QueueConfig holds background job queue and worker configuration
+type QueueConfig struct {
+	Name              string
+	VisibilityTimeout time.Duration
+	MaxAttempts       int
+	Concurrency       int
+	PollInterval      time.Duration
+}
+
+// SchedulerConfig holds periodic task scheduler configuration
+type SchedulerConfig struct {
+	Enabled bool
+}
+
+// MailConfig holds outgoing email configuration. Driver is "smtp", or
+// "mailbox" to write messages to MailboxDir instead of sending them.
+type MailConfig struct {
+	Driver        string
+	From          string
+	DefaultLocale string
+	MaxAttempts   int
+	MailboxDir    string
+	SMTPHost      string
+	SMTPPort      int
+	SMTPUsername  string
+	SMTPPassword  string
+	SMTPTimeout   time.Duration
+}
+
+// LoginConfig holds login history configuration. GeoIPDatabase is the path
+// of a MaxMind DB file (e.g. GeoLite2-City.mmdb); without it logins aren't
+// located and unusual locations aren't flagged.
+type LoginConfig struct {
+	GeoIPDatabase string
+}
+
 // AppConfig holds general application configuration
 type AppConfig struct {
+	Name        string
 	Environment string
 	LogLevel    string
 }
@@ -67,11 +148,13 @@ func Load() *Config {
 
 	return &Config{
 		Server: ServerConfig{
-			Host:         viper.GetString("SERVER_HOST"),
-			Port:         viper.GetString("SERVER_PORT"),
-			ReadTimeout:  viper.GetDuration("SERVER_READ_TIMEOUT"),
-			WriteTimeout: viper.GetDuration("SERVER_WRITE_TIMEOUT"),
-			IdleTimeout:  viper.GetDuration("SERVER_IDLE_TIMEOUT"),
+			Host:           viper.GetString("SERVER_HOST"),
+			Port:           viper.GetString("SERVER_PORT"),
+			ReadTimeout:    viper.GetDuration("SERVER_READ_TIMEOUT"),
+			WriteTimeout:   viper.GetDuration("SERVER_WRITE_TIMEOUT"),
+			IdleTimeout:    viper.GetDuration("SERVER_IDLE_TIMEOUT"),
+			ProxyHeader:    viper.GetString("SERVER_PROXY_HEADER"),
+			TrustedProxies: splitList(viper.GetString("SERVER_TRUSTED_PROXIES")),
 		},
 		Database: DatabaseConfig{
 			URI:      viper.GetString("MONGODB_URI"),
@@ -88,7 +171,54 @@ func Load() *Config {
 			AccessTokenDuration:  viper.GetDuration("ACCESS_TOKEN_DURATION"),
 			RefreshTokenDuration: viper.GetDuration("REFRESH_TOKEN_DURATION"),
 		},
+		User: UserConfig{
+			DeletedRetention: viper.GetDuration("USER_DELETED_RETENTION"),
+			PurgeSchedule:    viper.GetString("USER_PURGE_SCHEDULE"),
+			LookupCacheTTL:   viper.GetDuration("USER_LOOKUP_CACHE_TTL"),
+			CacheEnabled:     viper.GetBool("USER_CACHE_ENABLED"),
+			CacheTTL:         viper.GetDuration("USER_CACHE_TTL"),
+			LastSeenInterval: viper.GetDuration("USER_LAST_SEEN_INTERVAL"),
+		},
+		Event: EventConfig{
+			Stream:         viper.GetString("EVENT_STREAM"),
+			StreamMaxLen:   viper.GetInt64("EVENT_STREAM_MAX_LEN"),
+			RelayInterval:  viper.GetDuration("EVENT_RELAY_INTERVAL"),
+			RelayBatchSize: viper.GetInt("EVENT_RELAY_BATCH_SIZE"),
+		},
+		Webhook: WebhookConfig{
+			Timeout:             viper.GetDuration("WEBHOOK_TIMEOUT"),
+			MaxAttempts:         viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			WorkerInterval:      viper.GetDuration("WEBHOOK_WORKER_INTERVAL"),
+			BatchSize:           viper.GetInt("WEBHOOK_BATCH_SIZE"),
+			AllowPrivateTargets: viper.GetBool("WEBHOOK_ALLOW_PRIVATE_TARGETS"),
+		},
+		Queue: QueueConfig{
+			Name:              viper.GetString("QUEUE_NAME"),
+			VisibilityTimeout: viper.GetDuration("QUEUE_VISIBILITY_TIMEOUT"),
+			MaxAttempts:       viper.GetInt("QUEUE_MAX_ATTEMPTS"),
+			Concurrency:       viper.GetInt("QUEUE_CONCURRENCY"),
+			PollInterval:      viper.GetDuration("QUEUE_POLL_INTERVAL"),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled: viper.GetBool("SCHEDULER_ENABLED"),
+		},
+		Mail: MailConfig{
+			Driver:        viper.GetString("MAIL_DRIVER"),
+			From:          viper.GetString("MAIL_FROM"),
+			DefaultLocale: viper.GetString("MAIL_DEFAULT_LOCALE"),
+			MaxAttempts:   viper.GetInt("MAIL_MAX_ATTEMPTS"),
+			MailboxDir:    viper.GetString("MAIL_MAILBOX_DIR"),
+			SMTPHost:      viper.GetString("MAIL_SMTP_HOST"),
+			SMTPPort:      viper.GetInt("MAIL_SMTP_PORT"),
+			SMTPUsername:  viper.GetString("MAIL_SMTP_USERNAME"),
+			SMTPPassword:  viper.GetString("MAIL_SMTP_PASSWORD"),
+			SMTPTimeout:   viper.GetDuration("MAIL_SMTP_TIMEOUT"),
+		},
+		Login: LoginConfig{
+			GeoIPDatabase: viper.GetString("LOGIN_GEOIP_DATABASE"),
+		},
 		App: AppConfig{
+			Name:        viper.GetString("APP_NAME"),
 			Environment: viper.GetString("APP_ENV"),
 			LogLevel:    viper.GetString("LOG_LEVEL"),
 		},
@@ -103,6 +233,8 @@ func setDefaults() {
 	viper.SetDefault("SERVER_READ_TIMEOUT", "10s")
 	viper.SetDefault("SERVER_WRITE_TIMEOUT", "10s")
 	viper.SetDefault("SERVER_IDLE_TIMEOUT", "120s")
+	viper.SetDefault("SERVER_PROXY_HEADER", "")
+	viper.SetDefault("SERVER_TRUSTED_PROXIES", "")
 
 	// MongoDB defaults
 	viper.SetDefault("MONGODB_URI", "mongodb://localhost:27017")
@@ -119,7 +251,66 @@ func setDefaults() {
 	viper.SetDefault("ACCESS_TOKEN_DURATION", "15m")
 	viper.SetDefault("REFRESH_TOKEN_DURATION", "168h")
 
+	// User lifecycle defaults (soft-deleted users are kept for 30 days)
+	viper.SetDefault("USER_DELETED_RETENTION", "720h")
+	viper.SetDefault("USER_PURGE_SCHEDULE", "0 * * * *")
+	viper.SetDefault("USER_LOOKUP_CACHE_TTL", "30s")
+	viper.SetDefault("USER_CACHE_ENABLED", false)
+	viper.SetDefault("USER_CACHE_TTL", "5m")
+	viper.SetDefault("USER_LAST_SEEN_INTERVAL", "5m")
+
+	// Domain event defaults
+	viper.SetDefault("EVENT_STREAM", "events")
+	viper.SetDefault("EVENT_STREAM_MAX_LEN", 100000)
+	viper.SetDefault("EVENT_RELAY_INTERVAL", "1s")
+	viper.SetDefault("EVENT_RELAY_BATCH_SIZE", 100)
+
+	// Webhook defaults (10 attempts span roughly 4 hours)
+	viper.SetDefault("WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 10)
+	viper.SetDefault("WEBHOOK_WORKER_INTERVAL", "2s")
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 50)
+	viper.SetDefault("WEBHOOK_ALLOW_PRIVATE_TARGETS", false)
+
+	// Job queue defaults (a job not finished within the visibility timeout is
+	// handed to another worker)
+	viper.SetDefault("QUEUE_NAME", "default")
+	viper.SetDefault("QUEUE_VISIBILITY_TIMEOUT", "5m")
+	viper.SetDefault("QUEUE_MAX_ATTEMPTS", 5)
+	viper.SetDefault("QUEUE_CONCURRENCY", 10)
+	viper.SetDefault("QUEUE_POLL_INTERVAL", "1s")
+
+	// Scheduler defaults (replicas elect one leader to fire tasks)
+	viper.SetDefault("SCHEDULER_ENABLED", true)
+
+	// Mail defaults (development writes emails to a local mailbox directory)
+	viper.SetDefault("MAIL_DRIVER", "mailbox")
+	viper.SetDefault("MAIL_FROM", "GoFiber Boilerplate <noreply@localhost>")
+	viper.SetDefault("MAIL_DEFAULT_LOCALE", "en")
+	viper.SetDefault("MAIL_MAX_ATTEMPTS", 8)
+	viper.SetDefault("MAIL_MAILBOX_DIR", "tmp/mailbox")
+	viper.SetDefault("MAIL_SMTP_HOST", "localhost")
+	viper.SetDefault("MAIL_SMTP_PORT", 587)
+	viper.SetDefault("MAIL_SMTP_USERNAME", "")
+	viper.SetDefault("MAIL_SMTP_PASSWORD", "")
+	viper.SetDefault("MAIL_SMTP_TIMEOUT", "30s")
+
+	// Login history defaults
+	viper.SetDefault("LOGIN_GEOIP_DATABASE", "")
+
 	// App defaults
+	viper.SetDefault("APP_NAME", "GoFiber Boilerplate")
 	viper.SetDefault("APP_ENV", "development")
 	viper.SetDefault("LOG_LEVEL", "debug")
 }
+
+// splitList parses a comma separated setting, skipping empty items
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
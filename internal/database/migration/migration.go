@@ -2,46 +2,159 @@ package migration
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
-	"go.uber.org/zap"
 
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
 )
 
-// RunMigrations executes all database migrations (indexes, etc.)
-func RunMigrations(db *database.MongoDB) {
-	logger.Info("Running database migrations...")
+// Migration is a numbered, recorded change to the database. Modules declare
+// their migrations and the application passes them all to a Migrator, which
+// applies them in Version order across modules.
+type Migration struct {
+	// Version orders migrations and must be unique across all modules. Use
+	// the date and a sequence number, e.g. 2026101901.
+	Version int64
+	Name    string
+	Up      func(ctx context.Context, db *database.MongoDB) error
+	// Down reverts Up. Migrations without Down cannot be rolled back.
+	Down func(ctx context.Context, db *database.MongoDB) error
+	// Definition describes what the migration does. It is hashed into the
+	// checksum, so editing an applied migration is detected.
+	Definition any
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// Checksum identifies the migration's content. It changes whenever the
+// version, name or definition changes.
+func (m Migration) Checksum() string {
+	data, err := bson.Marshal(bson.D{{Key: "version", Value: m.Version}, {Key: "name", Value: m.Name}, {Key: "definition", Value: m.Definition}})
+	if err != nil {
+		// Definitions that can't be encoded only contribute their Go syntax
+		data = []byte(fmt.Sprintf("%d|%s|%#v", m.Version, m.Name, m.Definition))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
 
-	// 1. User Indexes
-	migrateUserIndexes(ctx, db)
+// CreateIndexes builds a migration that creates indexes on a collection and
+// drops them again on the way down. Index specs form the definition.
+func CreateIndexes(version int64, name, collection string, models ...mongo.IndexModel) Migration {
+	definition := bson.A{collection}
+	names := make([]string, len(models))
+	for i, model := range models {
+		opts := resolveIndexOptions(model)
+		definition = append(definition, indexSpec(model.Keys, opts))
+		names[i] = indexName(model.Keys, opts)
+	}
 
-	// Add more migration modules here as needed
+	return Migration{
+		Version:    version,
+		Name:       name,
+		Definition: definition,
+		Up: func(ctx context.Context, db *database.MongoDB) error {
+			_, err := db.Collection(collection).Indexes().CreateMany(ctx, models)
+			return err
+		},
+		Down: func(ctx context.Context, db *database.MongoDB) error {
+			indexes := db.Collection(collection).Indexes()
+			for _, name := range names {
+				if err := indexes.DropOne(ctx, name); err != nil {
+					return fmt.Errorf("drop index %s: %w", name, err)
+				}
+			}
+			return nil
+		},
+	}
+}
 
-	logger.Info("Database migrations completed successfully")
+func resolveIndexOptions(model mongo.IndexModel) *options.IndexOptions {
+	opts := &options.IndexOptions{}
+	if model.Options == nil {
+		return opts
+	}
+	for _, set := range model.Options.List() {
+		_ = set(opts)
+	}
+	return opts
 }
 
-func migrateUserIndexes(ctx context.Context, db *database.MongoDB) {
-	collection := db.Collection("users")
+// indexSpec describes an index by its keys and the options that are set,
+// named as in createIndexes. Unset options are left out so the checksum
+// doesn't depend on the driver's options struct.
+func indexSpec(keys any, opts *options.IndexOptions) bson.D {
+	spec := bson.D{{Key: "key", Value: keys}}
+	add := func(name string, value any, set bool) {
+		if set {
+			spec = append(spec, bson.E{Key: name, Value: value})
+		}
+	}
 
-	indexModels := []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "email", Value: 1}},
-			Options: options.Index().SetUnique(true),
-		},
+	add("name", opts.Name, opts.Name != nil)
+	add("unique", opts.Unique, opts.Unique != nil)
+	add("sparse", opts.Sparse, opts.Sparse != nil)
+	add("hidden", opts.Hidden, opts.Hidden != nil)
+	add("expireAfterSeconds", opts.ExpireAfterSeconds, opts.ExpireAfterSeconds != nil)
+	add("partialFilterExpression", opts.PartialFilterExpression, opts.PartialFilterExpression != nil)
+	add("collation", opts.Collation, opts.Collation != nil)
+	add("weights", opts.Weights, opts.Weights != nil)
+	add("default_language", opts.DefaultLanguage, opts.DefaultLanguage != nil)
+	add("language_override", opts.LanguageOverride, opts.LanguageOverride != nil)
+	add("textIndexVersion", opts.TextVersion, opts.TextVersion != nil)
+	add("2dsphereIndexVersion", opts.SphereVersion, opts.SphereVersion != nil)
+	add("bits", opts.Bits, opts.Bits != nil)
+	add("min", opts.Min, opts.Min != nil)
+	add("max", opts.Max, opts.Max != nil)
+	add("bucketSize", opts.BucketSize, opts.BucketSize != nil)
+	add("wildcardProjection", opts.WildcardProjection, opts.WildcardProjection != nil)
+	add("storageEngine", opts.StorageEngine, opts.StorageEngine != nil)
+	add("v", opts.Version, opts.Version != nil)
+	return spec
+}
+
+// indexName returns the explicit index name or the one MongoDB generates,
+// e.g. "email_1" or "createdAt_-1__id_-1"
+func indexName(keys any, opts *options.IndexOptions) string {
+	if opts.Name != nil {
+		return *opts.Name
+	}
+
+	doc, ok := keys.(bson.D)
+	if !ok {
+		return fmt.Sprint(keys)
+	}
+	parts := make([]string, 0, len(doc)*2)
+	for _, key := range doc {
+		parts = append(parts, key.Key, fmt.Sprint(key.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+// RunMigrations applies every pending migration and returns the first error,
+// so the application can refuse to start on a database it doesn't match
+func RunMigrations(db *database.MongoDB, migrations ...Migration) error {
+	logger.Info("Running database migrations...")
+
+	migrator, err := NewMigrator(db, migrations...)
+	if err != nil {
+		return err
 	}
 
-	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	applied, err := migrator.Up(ctx)
 	if err != nil {
-		logger.Error("Failed to create user indexes", zap.Error(err))
-	} else {
-		logger.Info("User indexes verified/created")
+		return err
 	}
+
+	logger.Info(fmt.Sprintf("Database migrations completed successfully (%d applied)", len(applied)))
+	return nil
 }
@@ -0,0 +1,71 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func emailIndex(unique bool) Migration {
+	return CreateIndexes(1, "users_email", "users", mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(unique),
+	})
+}
+
+func TestChecksum_StableForSameDefinition(t *testing.T) {
+	assert.Equal(t, emailIndex(true).Checksum(), emailIndex(true).Checksum())
+}
+
+func TestChecksum_ChangesWithIndexOptions(t *testing.T) {
+	assert.NotEqual(t, emailIndex(true).Checksum(), emailIndex(false).Checksum())
+}
+
+func TestChecksum_ChangesWithCollation(t *testing.T) {
+	build := func(strength int) Migration {
+		return CreateIndexes(1, "users_email", "users", mongo.IndexModel{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetCollation(&options.Collation{Locale: "en", Strength: strength}),
+		})
+	}
+
+	assert.NotEqual(t, build(1).Checksum(), build(2).Checksum())
+}
+
+func TestIndexName(t *testing.T) {
+	assert.Equal(t, "email_1", indexName(bson.D{{Key: "email", Value: 1}}, &options.IndexOptions{}))
+	assert.Equal(t, "createdAt_-1__id_-1", indexName(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}, &options.IndexOptions{}))
+
+	name := "team_ci"
+	assert.Equal(t, "team_ci", indexName(bson.D{{Key: "team", Value: 1}}, &options.IndexOptions{Name: &name}))
+}
+
+func TestNewMigrator_RejectsDuplicateVersions(t *testing.T) {
+	_, err := NewMigrator(nil, Migration{Version: 2, Name: "a"}, Migration{Version: 1, Name: "b"}, Migration{Version: 2, Name: "c"})
+
+	assert.ErrorIs(t, err, ErrDuplicateVersion)
+}
+
+func TestNewMigrator_SortsByVersion(t *testing.T) {
+	migrator, err := NewMigrator(nil, Migration{Version: 3}, Migration{Version: 1}, Migration{Version: 2})
+	require.NoError(t, err)
+
+	versions := []int64{}
+	for _, m := range migrator.Migrations() {
+		versions = append(versions, m.Version)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, versions)
+}
+
+func TestIndexSpec_OnlySetOptions(t *testing.T) {
+	keys := bson.D{{Key: "email", Value: 1}}
+
+	assert.Equal(t, bson.D{{Key: "key", Value: keys}}, indexSpec(keys, &options.IndexOptions{}))
+
+	unique := true
+	assert.Equal(t, bson.D{{Key: "key", Value: keys}, {Key: "unique", Value: &unique}}, indexSpec(keys, &options.IndexOptions{Unique: &unique}))
+}
@@ -0,0 +1,318 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
+)
+
+const (
+	historyCollection = "schema_migrations"
+	lockCollection    = "schema_migrations_lock"
+	lockID            = "migrations"
+
+	// lockTTL bounds how long a crashed replica can block the others. The
+	// holder extends the lock every lockHeartbeat while it runs.
+	lockTTL          = time.Minute
+	lockHeartbeat    = lockTTL / 3
+	lockPollInterval = time.Second
+)
+
+var (
+	ErrDuplicateVersion = errors.New("duplicate migration version")
+	ErrChecksumMismatch = errors.New("applied migration has changed")
+	ErrIrreversible     = errors.New("migration cannot be rolled back")
+	ErrLockLost         = errors.New("migration lock lost")
+)
+
+// Record is a row in the schema_migrations collection
+type Record struct {
+	Version   int64     `bson:"_id"`
+	Name      string    `bson:"name"`
+	Checksum  string    `bson:"checksum"`
+	AppliedAt time.Time `bson:"appliedAt"`
+}
+
+// Status describes a known or applied migration
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+	// Modified means the migration changed after it was applied
+	Modified bool `json:"modified,omitempty"`
+	// Unknown means the version is recorded but no longer registered
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// Migrator applies and reverts migrations, recording them in schema_migrations.
+// Only one Migrator at a time may run across all replicas.
+type Migrator struct {
+	db         *database.MongoDB
+	migrations []Migration
+	owner      string
+}
+
+// NewMigrator creates a Migrator for the given migrations, which may be passed
+// in any order. Versions must be unique.
+func NewMigrator(db *database.MongoDB, migrations ...Migration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("%w: %d (%s, %s)", ErrDuplicateVersion, sorted[i].Version, sorted[i-1].Name, sorted[i].Name)
+		}
+	}
+
+	return &Migrator{db: db, migrations: sorted, owner: utils.NewInstanceID()}, nil
+}
+
+// Migrations returns the registered migrations in version order
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies every pending migration in version order and returns the ones
+// it applied. It refuses to run if an applied migration has been modified.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		records, err := m.records(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if record, ok := records[migration.Version]; ok {
+				if record.Checksum != migration.Checksum() {
+					return fmt.Errorf("%w: %d %s", ErrChecksumMismatch, migration.Version, migration.Name)
+				}
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := records[migration.Version]; ok {
+				continue
+			}
+
+			logger.Info("Applying migration", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+			if err := migration.Up(ctx, m.db); err != nil {
+				return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+			}
+
+			record := Record{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum(),
+				AppliedAt: time.Now(),
+			}
+			if _, err := m.db.Collection(historyCollection).InsertOne(ctx, record); err != nil {
+				return fmt.Errorf("record migration %d: %w", migration.Version, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns
+// the ones it reverted
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		records, err := m.records(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := records[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("%w: %d %s", ErrIrreversible, migration.Version, migration.Name)
+			}
+
+			logger.Info("Reverting migration", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+			if err := migration.Down(ctx, m.db); err != nil {
+				return fmt.Errorf("revert migration %d %s: %w", migration.Version, migration.Name, err)
+			}
+			if _, err := m.db.Collection(historyCollection).DeleteOne(ctx, bson.M{"_id": migration.Version}); err != nil {
+				return fmt.Errorf("unrecord migration %d: %w", migration.Version, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status lists registered migrations and whether they are applied, followed by
+// any applied versions that are no longer registered
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	known := make(map[int64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := records[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Modified = record.Checksum != migration.Checksum()
+		}
+		statuses = append(statuses, status)
+	}
+
+	var unknown []Status
+	for version, record := range records {
+		if known[version] {
+			continue
+		}
+		appliedAt := record.AppliedAt
+		unknown = append(unknown, Status{Version: version, Name: record.Name, Applied: true, AppliedAt: &appliedAt, Unknown: true})
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Version < unknown[j].Version })
+
+	return append(statuses, unknown...), nil
+}
+
+func (m *Migrator) records(ctx context.Context) (map[int64]Record, error) {
+	cursor, err := m.db.Collection(historyCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]Record, len(records))
+	for _, record := range records {
+		byVersion[record.Version] = record
+	}
+	return byVersion, nil
+}
+
+// withLock runs fn while holding the migration lock, waiting for other
+// replicas to finish first. The lock is extended while fn runs; fn's context
+// is cancelled if the lock is lost.
+func (m *Migrator) withLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := m.acquireLock(ctx); err != nil {
+		return err
+	}
+	defer m.releaseLock()
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	done := make(chan struct{})
+	defer close(done)
+	go m.heartbeat(ctx, cancel, done)
+
+	err := fn(ctx)
+	if err != nil && errors.Is(context.Cause(ctx), ErrLockLost) {
+		return fmt.Errorf("%w: %w", ErrLockLost, err)
+	}
+	return err
+}
+
+// heartbeat extends the lock until done is closed. It cancels the migration
+// if the lock can no longer be extended, since another replica may take it
+// once it expires.
+func (m *Migrator) heartbeat(ctx context.Context, cancel context.CancelCauseFunc, done <-chan struct{}) {
+	ticker := time.NewTicker(lockHeartbeat)
+	defer ticker.Stop()
+
+	expiresAt := time.Now().Add(lockTTL)
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		extended := time.Now().Add(lockTTL)
+		result, err := m.db.Collection(lockCollection).UpdateOne(ctx,
+			bson.M{"_id": lockID, "owner": m.owner},
+			bson.M{"$set": bson.M{"expiresAt": extended}},
+		)
+		switch {
+		case err == nil && result.MatchedCount > 0:
+			expiresAt = extended
+		case err != nil && time.Now().Before(expiresAt):
+			// The lock is still ours until it expires, retry on the next beat
+			logger.Warn("Failed to extend migration lock", zap.Error(err))
+		default:
+			logger.Error("Lost migration lock", zap.Error(err))
+			cancel(ErrLockLost)
+			return
+		}
+	}
+}
+
+// acquireLock takes the lock document if it is missing or expired. When
+// another owner holds it the upsert collides on _id and we poll again.
+func (m *Migrator) acquireLock(ctx context.Context) error {
+	collection := m.db.Collection(lockCollection)
+
+	for {
+		now := time.Now()
+		_, err := collection.UpdateOne(ctx,
+			bson.M{"_id": lockID, "expiresAt": bson.M{"$lt": now}},
+			bson.M{"$set": bson.M{"owner": m.owner, "lockedAt": now, "expiresAt": now.Add(lockTTL)}},
+			options.UpdateOne().SetUpsert(true),
+		)
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+
+		logger.Info("Waiting for migration lock held by another instance")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("acquire migration lock: %w", ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+func (m *Migrator) releaseLock() {
+	// Release even if the migration context was cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := m.db.Collection(lockCollection).DeleteOne(ctx, bson.M{"_id": lockID, "owner": m.owner}); err != nil {
+		logger.Error("Failed to release migration lock", zap.Error(err))
+	}
+}
@@ -0,0 +1,95 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/notifier"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// Deliverer sends queued emails; it runs in cmd/worker
+type Deliverer struct {
+	emailRepo repository.EmailRepository
+	sender    notifier.Sender
+}
+
+// NewDeliverer creates a Deliverer sending through sender
+func NewDeliverer(emailRepo repository.EmailRepository, sender notifier.Sender) *Deliverer {
+	return &Deliverer{
+		emailRepo: emailRepo,
+		sender:    sender,
+	}
+}
+
+// Run sends one queued email; it is the queue handler for service.JobType.
+// Failed attempts are retried by the queue until the job's last attempt, or
+// not at all when the mail server rejected the message.
+func (d *Deliverer) Run(ctx context.Context, job *queue.Job) error {
+	var payload service.JobPayload
+	if err := job.Decode(&payload); err != nil {
+		return queue.Permanent(err)
+	}
+
+	email, err := d.emailRepo.FindByID(ctx, payload.EmailID)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailNotFound) {
+			// Purged by the retention index before the worker got to it
+			return queue.Permanent(err)
+		}
+		return err
+	}
+	if email.Status == entity.EmailSent {
+		// A redelivered job for an email that already went out
+		return nil
+	}
+
+	fields := []zap.Field{
+		zap.String("email_id", email.ID.Hex()),
+		zap.String("template", email.Template),
+		zap.Int("attempt", job.Attempts),
+	}
+
+	email.Attempts = job.Attempts
+	err = d.sender.Send(ctx, &notifier.Message{
+		ID:      email.ID.Hex(),
+		From:    email.From,
+		To:      email.To,
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    email.HTML,
+	})
+	if err == nil {
+		now := time.Now()
+		email.Status = entity.EmailSent
+		email.LastError = ""
+		email.SentAt = &now
+		d.save(ctx, email)
+		logger.Info("email sent", fields...)
+		return nil
+	}
+
+	email.LastError = err.Error()
+	if notifier.IsRejected(err) {
+		err = queue.Permanent(err)
+	}
+	if queue.IsPermanent(err) || job.Attempts >= job.MaxAttempts {
+		email.Status = entity.EmailFailed
+	}
+	d.save(ctx, email)
+	logger.Warn("failed to send email", append(fields, zap.Error(err))...)
+	return err
+}
+
+func (d *Deliverer) save(ctx context.Context, email *entity.Email) {
+	if err := d.emailRepo.SaveAttempt(ctx, email); err != nil {
+		logger.Error("failed to save email attempt", zap.Error(err), zap.String("email_id", email.ID.Hex()))
+	}
+}
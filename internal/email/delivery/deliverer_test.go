@@ -0,0 +1,100 @@
+package delivery_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email/delivery"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/notifier"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// runDeliverer runs one attempt of an email job and returns the email as
+// saved and the job's error
+func runDeliverer(t *testing.T, sender notifier.Sender, status entity.EmailStatus, attempts, maxAttempts int) (*entity.Email, error) {
+	t.Helper()
+
+	email := &entity.Email{
+		ID:       bson.NewObjectID(),
+		From:     "noreply@example.com",
+		To:       "budi@example.com",
+		Template: "welcome",
+		Subject:  "Welcome",
+		Text:     "Hi Budi",
+		Status:   status,
+	}
+
+	var saved *entity.Email
+	emailRepo := &mock.MockEmailRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Email, error) {
+			return email, nil
+		},
+		SaveAttemptFunc: func(ctx context.Context, e *entity.Email) error {
+			saved = e
+			return nil
+		},
+	}
+
+	payload, _ := json.Marshal(map[string]string{"emailId": email.ID.Hex()})
+	job := &queue.Job{Type: "email.send", Payload: payload, Attempts: attempts, MaxAttempts: maxAttempts}
+
+	err := delivery.NewDeliverer(emailRepo, sender).Run(context.Background(), job)
+	return saved, err
+}
+
+func TestRun_SendsAndMarksSent(t *testing.T) {
+	// 1. Setup
+	sender := notifier.NewMemorySender()
+
+	// 2. Call Method
+	saved, err := runDeliverer(t, sender, entity.EmailQueued, 1, 5)
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, entity.EmailSent, saved.Status)
+	assert.NotNil(t, saved.SentAt)
+	if assert.Len(t, sender.Messages(), 1) {
+		assert.Equal(t, "budi@example.com", sender.Messages()[0].To)
+		assert.Equal(t, "Hi Budi", sender.Messages()[0].Text)
+	}
+}
+
+func TestRun_SkipsEmailAlreadySent(t *testing.T) {
+	sender := notifier.NewMemorySender()
+
+	saved, err := runDeliverer(t, sender, entity.EmailSent, 2, 5)
+
+	assert.NoError(t, err)
+	assert.Nil(t, saved)
+	assert.Empty(t, sender.Messages())
+}
+
+func TestRun_TemporaryFailureStaysQueuedUntilLastAttempt(t *testing.T) {
+	sender := &notifier.MemorySender{Err: errors.New("connection refused")}
+
+	saved, err := runDeliverer(t, sender, entity.EmailQueued, 2, 5)
+	assert.Error(t, err)
+	assert.False(t, queue.IsPermanent(err))
+	assert.Equal(t, entity.EmailQueued, saved.Status)
+	assert.Equal(t, 2, saved.Attempts)
+	assert.Equal(t, "connection refused", saved.LastError)
+
+	saved, _ = runDeliverer(t, sender, entity.EmailQueued, 5, 5)
+	assert.Equal(t, entity.EmailFailed, saved.Status)
+}
+
+func TestRun_RejectedMessageFailsPermanently(t *testing.T) {
+	sender := &notifier.MemorySender{Err: notifier.Rejected(errors.New("550 mailbox unavailable"))}
+
+	saved, err := runDeliverer(t, sender, entity.EmailQueued, 1, 5)
+
+	assert.True(t, queue.IsPermanent(err))
+	assert.Equal(t, entity.EmailFailed, saved.Status)
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// SendEmailRequest asks for a templated email to be sent. Other modules build
+// it; it is not an API request body.
+type SendEmailRequest struct {
+	To       string
+	Template string
+	// Locale picks the template variant; empty uses the default locale
+	Locale string
+	Data   map[string]any
+	// Key, when set, makes sending idempotent: an email with the same key is
+	// only queued once. Use it when reacting to events.
+	Key string
+}
+
+// EmailResponse represents an email and its delivery status. Bodies are only
+// included when a single email is requested.
+type EmailResponse struct {
+	ID        string             `json:"id"`
+	To        string             `json:"to"`
+	Template  string             `json:"template"`
+	Locale    string             `json:"locale"`
+	Subject   string             `json:"subject"`
+	Text      string             `json:"text,omitempty"`
+	HTML      string             `json:"html,omitempty"`
+	Status    entity.EmailStatus `json:"status"`
+	Attempts  int                `json:"attempts"`
+	LastError string             `json:"lastError,omitempty"`
+	SentAt    *time.Time         `json:"sentAt,omitempty"`
+	CreatedAt time.Time          `json:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// ToEmailResponse converts an email entity to a response
+func ToEmailResponse(email *entity.Email) EmailResponse {
+	return EmailResponse{
+		ID:        email.ID.Hex(),
+		To:        email.To,
+		Template:  email.Template,
+		Locale:    email.Locale,
+		Subject:   email.Subject,
+		Text:      email.Text,
+		HTML:      email.HTML,
+		Status:    email.Status,
+		Attempts:  email.Attempts,
+		LastError: email.LastError,
+		SentAt:    email.SentAt,
+		CreatedAt: email.CreatedAt,
+		UpdatedAt: email.UpdatedAt,
+	}
+}
+
+// ToEmailResponses converts email entities to responses
+func ToEmailResponses(emails []*entity.Email) []EmailResponse {
+	responses := make([]EmailResponse, len(emails))
+	for i, email := range emails {
+		responses[i] = ToEmailResponse(email)
+	}
+	return responses
+}
@@ -0,0 +1,40 @@
+package email
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/config"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/notifier"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Templates parses the email templates shipped with the application. Add a
+// template as templates/<name>/<locale>.{subject,text,html}.tmpl.
+func Templates(defaultLocale string) (*notifier.Templates, error) {
+	fsys, err := fs.Sub(templateFS, "templates")
+	if err != nil {
+		return nil, err
+	}
+	return notifier.ParseTemplates(fsys, defaultLocale)
+}
+
+// NewSender creates the sender selected by cfg.Driver
+func NewSender(cfg config.MailConfig) (notifier.Sender, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return notifier.NewSMTPSender(notifier.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			Timeout:  cfg.SMTPTimeout,
+		}), nil
+	case "mailbox":
+		return notifier.NewMailboxSender(cfg.MailboxDir)
+	}
+	return nil, fmt.Errorf("unknown mail driver %q, use smtp or mailbox", cfg.Driver)
+}
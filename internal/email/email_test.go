@@ -0,0 +1,22 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplates_ShippedTemplatesRender(t *testing.T) {
+	templates, err := Templates("en")
+	require.NoError(t, err)
+
+	data := map[string]any{"AppName": "Acme", "FirstName": "Budi", "Email": "budi@example.com"}
+	for _, locale := range []string{"en", "id"} {
+		content, err := templates.Render("welcome", locale, data)
+		require.NoError(t, err)
+		assert.Equal(t, locale, content.Locale)
+		assert.Contains(t, content.Subject, "Budi")
+		assert.Contains(t, content.HTML, "budi@example.com")
+	}
+}
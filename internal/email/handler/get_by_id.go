@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetEmailByID godoc
+// @Summary      Get email by ID
+// @Description  Get an email with its rendered bodies and delivery status (ADMIN only)
+// @Tags         emails
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Email ID"
+// @Success      200 {object} response.Response{data=dto.EmailResponse}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /emails/{id} [get]
+func (h *EmailHandler) GetEmailByID(c *fiber.Ctx) error {
+	email, err := h.emailService.GetByID(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrEmailNotFound) {
+			return response.NotFound(c, "email not found")
+		}
+		return response.InternalServerError(c, "failed to get email")
+	}
+
+	return response.Success(c, fiber.StatusOK, "email retrieved successfully", email)
+}
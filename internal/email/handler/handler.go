@@ -0,0 +1,15 @@
+package handler
+
+import "github.com/itsahyarr/gofiber-boilerplate/internal/email/service"
+
+// EmailHandler handles email-related HTTP requests
+type EmailHandler struct {
+	emailService service.EmailService
+}
+
+// NewEmailHandler creates a new email handler
+func NewEmailHandler(emailService service.EmailService) *EmailHandler {
+	return &EmailHandler{
+		emailService: emailService,
+	}
+}
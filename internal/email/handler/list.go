@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/query"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// GetEmails godoc
+// @Summary      Get emails
+// @Description  Get sent and queued emails with their delivery status, newest first (ADMIN only)
+// @Tags         emails
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status query string false "Only emails with this status; also status[ne|in|nin]" Enums(queued, sent, failed)
+// @Param        to query string false "Only emails to this address; also to[ne|in|nin|contains]"
+// @Param        template query string false "Only emails of this template; also template[ne|in|nin]"
+// @Param        created-at[gte] query string false "Created on or after (RFC 3339 or YYYY-MM-DD); also gt, lt, lte"
+// @Param        page query int false "Page number" default(1)
+// @Param        per-page query int false "Items per page" default(10)
+// @Success      200 {object} response.PaginatedResponse{data=[]dto.EmailResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /emails [get]
+func (h *EmailHandler) GetEmails(c *fiber.Ctx) error {
+	page, perPage := pagination.ParsePage(c)
+
+	q, err := emailQuerySchema.Parse(c.Queries())
+	if err != nil {
+		return response.BadRequest(c, "invalid query", err.Error())
+	}
+
+	emails, total, err := h.emailService.GetAll(c.Context(), q.Filter, page, perPage)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get emails")
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "emails retrieved successfully", emails, page, perPage, total)
+}
+
+// emailQuerySchema whitelists the fields clients may filter emails by
+var emailQuerySchema = query.Schema{
+	Fields: map[string]query.Field{
+		"status": {
+			Name:      "status",
+			Type:      query.String,
+			Operators: []query.Operator{query.OpEq, query.OpNe, query.OpIn, query.OpNin},
+			Values:    []string{string(entity.EmailQueued), string(entity.EmailSent), string(entity.EmailFailed)},
+		},
+		"to":         {Name: "to", Type: query.String},
+		"template":   {Name: "template", Type: query.String, Operators: []query.Operator{query.OpEq, query.OpNe, query.OpIn, query.OpNin}},
+		"created-at": {Name: "createdAt", Type: query.Time},
+	},
+}
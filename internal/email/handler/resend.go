@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// Resend godoc
+// @Summary      Resend email
+// @Description  Queue a failed email to be sent again, unchanged, with a fresh retry budget (ADMIN only)
+// @Tags         emails
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Email ID"
+// @Success      202 {object} response.Response{data=dto.EmailResponse}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /emails/{id}/resend [post]
+func (h *EmailHandler) Resend(c *fiber.Ctx) error {
+	email, err := h.emailService.Resend(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrEmailNotFound) {
+			return response.NotFound(c, "failed email not found")
+		}
+		return response.InternalServerError(c, "failed to resend email")
+	}
+
+	return response.Success(c, fiber.StatusAccepted, "email resend queued", email)
+}
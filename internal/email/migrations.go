@@ -0,0 +1,38 @@
+package email
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/database/migration"
+)
+
+// emailRetention is how long sent and failed emails are kept
+const emailRetention = 30 * 24 * time.Hour
+
+// Migrations returns the email module's database migrations
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		migration.CreateIndexes(2026101910, "emails_indexes", "emails",
+			mongo.IndexModel{
+				// An event triggers an email at most once
+				Keys:    bson.D{{Key: "key", Value: 1}},
+				Options: options.Index().SetUnique(true).SetSparse(true),
+			},
+			mongo.IndexModel{
+				// Admin listing filtered by status, newest first
+				Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}},
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "to", Value: 1}, {Key: "createdAt", Value: -1}},
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "createdAt", Value: 1}},
+				Options: options.Index().SetName("emails_ttl").SetExpireAfterSeconds(int32(emailRetention.Seconds())),
+			},
+		),
+	}
+}
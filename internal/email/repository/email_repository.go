@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+var (
+	ErrEmailNotFound = errors.New("email not found")
+	// ErrEmailExists means an email with the same key was already queued
+	ErrEmailExists = errors.New("email already exists")
+)
+
+// EmailRepository defines the interface for email data access
+type EmailRepository interface {
+	Create(ctx context.Context, email *entity.Email) error
+	FindByID(ctx context.Context, id string) (*entity.Email, error)
+	FindByKey(ctx context.Context, key string) (*entity.Email, error)
+	// FindAll returns a page of the emails matching filter, newest first
+	FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.Email, int64, error)
+	// SaveAttempt stores the outcome of a delivery attempt
+	SaveAttempt(ctx context.Context, email *entity.Email) error
+	// Requeue makes a failed email queued again with a fresh attempt count
+	Requeue(ctx context.Context, id string) (*entity.Email, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type emailRepositoryMongo struct {
+	collection *mongo.Collection
+}
+
+// NewEmailRepository creates a new MongoDB email repository
+func NewEmailRepository(db *database.MongoDB) EmailRepository {
+	return &emailRepositoryMongo{
+		collection: db.Collection("emails"),
+	}
+}
+
+func (r *emailRepositoryMongo) Create(ctx context.Context, email *entity.Email) error {
+	now := time.Now()
+	email.ID = bson.NewObjectID()
+	email.CreatedAt = now
+	email.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, email)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrEmailExists
+	}
+	return err
+}
+
+func (r *emailRepositoryMongo) FindByID(ctx context.Context, id string) (*entity.Email, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrEmailNotFound
+	}
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+func (r *emailRepositoryMongo) FindByKey(ctx context.Context, key string) (*entity.Email, error) {
+	return r.findOne(ctx, bson.M{"key": key})
+}
+
+func (r *emailRepositoryMongo) findOne(ctx context.Context, filter bson.M) (*entity.Email, error) {
+	var email entity.Email
+	err := r.collection.FindOne(ctx, filter).Decode(&email)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEmailNotFound
+		}
+		return nil, err
+	}
+
+	return &email, nil
+}
+
+func (r *emailRepositoryMongo) FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.Email, int64, error) {
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		// Bodies can be large; the listing only needs the delivery status
+		SetProjection(bson.M{"text": 0, "html": 0})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var emails []*entity.Email
+	if err := cursor.All(ctx, &emails); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return emails, total, nil
+}
+
+func (r *emailRepositoryMongo) SaveAttempt(ctx context.Context, email *entity.Email) error {
+	email.UpdatedAt = time.Now()
+
+	set := bson.M{
+		"status":    email.Status,
+		"attempts":  email.Attempts,
+		"lastError": email.LastError,
+		"updatedAt": email.UpdatedAt,
+	}
+	if email.SentAt != nil {
+		set["sentAt"] = email.SentAt
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": email.ID}, bson.M{"$set": set})
+	return err
+}
+
+func (r *emailRepositoryMongo) Requeue(ctx context.Context, id string) (*entity.Email, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrEmailNotFound
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":    entity.EmailQueued,
+		"attempts":  0,
+		"updatedAt": time.Now(),
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var email entity.Email
+	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objectID, "status": entity.EmailFailed}, update, opts).Decode(&email)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEmailNotFound
+		}
+		return nil, err
+	}
+
+	return &email, nil
+}
+
+func (r *emailRepositoryMongo) Delete(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrEmailNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrEmailNotFound
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package mock
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// MockEmailRepository is a mock implementation of repository.EmailRepository
+type MockEmailRepository struct {
+	CreateFunc      func(ctx context.Context, email *entity.Email) error
+	FindByIDFunc    func(ctx context.Context, id string) (*entity.Email, error)
+	FindByKeyFunc   func(ctx context.Context, key string) (*entity.Email, error)
+	FindAllFunc     func(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.Email, int64, error)
+	SaveAttemptFunc func(ctx context.Context, email *entity.Email) error
+	RequeueFunc     func(ctx context.Context, id string) (*entity.Email, error)
+	DeleteFunc      func(ctx context.Context, id string) error
+}
+
+func (m *MockEmailRepository) Create(ctx context.Context, email *entity.Email) error {
+	return m.CreateFunc(ctx, email)
+}
+
+func (m *MockEmailRepository) FindByID(ctx context.Context, id string) (*entity.Email, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockEmailRepository) FindByKey(ctx context.Context, key string) (*entity.Email, error) {
+	return m.FindByKeyFunc(ctx, key)
+}
+
+func (m *MockEmailRepository) FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.Email, int64, error) {
+	return m.FindAllFunc(ctx, filter, page, pageSize)
+}
+
+func (m *MockEmailRepository) SaveAttempt(ctx context.Context, email *entity.Email) error {
+	return m.SaveAttemptFunc(ctx, email)
+}
+
+func (m *MockEmailRepository) Requeue(ctx context.Context, id string) (*entity.Email, error) {
+	return m.RequeueFunc(ctx, id)
+}
+
+func (m *MockEmailRepository) Delete(ctx context.Context, id string) error {
+	return m.DeleteFunc(ctx, id)
+}
@@ -0,0 +1,18 @@
+package email
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email/handler"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+)
+
+// RegisterRoutes registers all email routes (ADMIN only)
+func RegisterRoutes(router fiber.Router, h *handler.EmailHandler, tokenMaker *token.PasetoMaker) {
+	emails := router.Group("/emails", middleware.AuthMiddleware(tokenMaker), middleware.RequireAdmin())
+
+	emails.Get("/", h.GetEmails)
+	emails.Get("/:id", h.GetEmailByID)
+	emails.Post("/:id/resend", h.Resend)
+}
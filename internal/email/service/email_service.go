@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/notifier"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// JobType is the queue job that delivers a queued email
+const JobType = "email.send"
+
+var (
+	ErrEmailNotFound    = errors.New("email not found")
+	ErrTemplateNotFound = errors.New("email template not found")
+)
+
+// JobPayload is the queue job payload of an email delivery
+type JobPayload struct {
+	EmailID string `json:"emailId"`
+}
+
+// EmailService defines the interface for email operations
+type EmailService interface {
+	// Send renders the template and queues the email for the worker
+	Send(ctx context.Context, req *dto.SendEmailRequest) (*dto.EmailResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.EmailResponse, error)
+	GetAll(ctx context.Context, filter bson.M, page, pageSize int) ([]dto.EmailResponse, int64, error)
+	// Resend queues a failed email again with a fresh retry budget
+	Resend(ctx context.Context, id string) (*dto.EmailResponse, error)
+}
+
+type emailServiceImpl struct {
+	emailRepo   repository.EmailRepository
+	templates   *notifier.Templates
+	queue       *queue.Queue
+	from        string
+	maxAttempts int
+}
+
+// NewEmailService creates a new email service sending from the given address
+func NewEmailService(emailRepo repository.EmailRepository, templates *notifier.Templates, jobQueue *queue.Queue, from string, maxAttempts int) EmailService {
+	return &emailServiceImpl{
+		emailRepo:   emailRepo,
+		templates:   templates,
+		queue:       jobQueue,
+		from:        from,
+		maxAttempts: maxAttempts,
+	}
+}
+
+func (s *emailServiceImpl) Send(ctx context.Context, req *dto.SendEmailRequest) (*dto.EmailResponse, error) {
+	content, err := s.templates.Render(req.Template, req.Locale, req.Data)
+	if err != nil {
+		if errors.Is(err, notifier.ErrTemplateNotFound) {
+			return nil, ErrTemplateNotFound
+		}
+		logger.Error("failed to render email", zap.Error(err), zap.String("template", req.Template))
+		return nil, err
+	}
+
+	email := &entity.Email{
+		Key:      req.Key,
+		From:     s.from,
+		To:       req.To,
+		Template: req.Template,
+		Locale:   content.Locale,
+		Subject:  content.Subject,
+		Text:     content.Text,
+		HTML:     content.HTML,
+		Status:   entity.EmailQueued,
+	}
+	if err := s.emailRepo.Create(ctx, email); err != nil {
+		if errors.Is(err, repository.ErrEmailExists) {
+			// Already queued by an earlier delivery of the same event
+			existing, err := s.emailRepo.FindByKey(ctx, req.Key)
+			if err != nil {
+				return nil, err
+			}
+			response := dto.ToEmailResponse(existing)
+			return &response, nil
+		}
+		logger.Error("failed to create email", zap.Error(err), zap.String("template", req.Template))
+		return nil, err
+	}
+
+	if err := s.enqueue(ctx, email); err != nil {
+		// Drop the record so a retry of the caller can queue it again
+		if delErr := s.emailRepo.Delete(ctx, email.ID.Hex()); delErr != nil {
+			logger.Error("failed to delete unqueued email", zap.Error(delErr), zap.String("email_id", email.ID.Hex()))
+		}
+		return nil, err
+	}
+
+	logger.Info("email queued",
+		zap.String("email_id", email.ID.Hex()),
+		zap.String("template", email.Template),
+		zap.String("locale", email.Locale),
+	)
+
+	response := dto.ToEmailResponse(email)
+	return &response, nil
+}
+
+func (s *emailServiceImpl) GetByID(ctx context.Context, id string) (*dto.EmailResponse, error) {
+	email, err := s.emailRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailNotFound) {
+			return nil, ErrEmailNotFound
+		}
+		logger.Error("failed to get email", zap.Error(err), zap.String("email_id", id))
+		return nil, err
+	}
+
+	response := dto.ToEmailResponse(email)
+	return &response, nil
+}
+
+func (s *emailServiceImpl) GetAll(ctx context.Context, filter bson.M, page, pageSize int) ([]dto.EmailResponse, int64, error) {
+	emails, total, err := s.emailRepo.FindAll(ctx, filter, page, pageSize)
+	if err != nil {
+		logger.Error("failed to get emails", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return dto.ToEmailResponses(emails), total, nil
+}
+
+func (s *emailServiceImpl) Resend(ctx context.Context, id string) (*dto.EmailResponse, error) {
+	email, err := s.emailRepo.Requeue(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailNotFound) {
+			return nil, ErrEmailNotFound
+		}
+		logger.Error("failed to requeue email", zap.Error(err), zap.String("email_id", id))
+		return nil, err
+	}
+
+	if err := s.enqueue(ctx, email); err != nil {
+		// Leave it failed so it can be resent again
+		email.Status = entity.EmailFailed
+		email.LastError = err.Error()
+		if saveErr := s.emailRepo.SaveAttempt(ctx, email); saveErr != nil {
+			logger.Error("failed to save email", zap.Error(saveErr), zap.String("email_id", id))
+		}
+		return nil, err
+	}
+
+	logger.Info("email resend queued", zap.String("email_id", id))
+
+	response := dto.ToEmailResponse(email)
+	return &response, nil
+}
+
+func (s *emailServiceImpl) enqueue(ctx context.Context, email *entity.Email) error {
+	payload := JobPayload{EmailID: email.ID.Hex()}
+	if _, err := s.queue.Enqueue(ctx, JobType, payload, queue.EnqueueOptions{MaxAttempts: s.maxAttempts}); err != nil {
+		logger.Error("failed to queue email", zap.Error(err), zap.String("email_id", email.ID.Hex()))
+		return err
+	}
+	return nil
+}
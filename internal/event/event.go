@@ -0,0 +1,62 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Event is something that happened in a module that other parts of the system
+// may react to. Delivery is at least once, so consumers must deduplicate on ID.
+type Event struct {
+	ID          string         `bson:"_id" json:"id"`
+	Type        string         `bson:"type" json:"type"`
+	AggregateID string         `bson:"aggregateId" json:"aggregateId"`
+	OccurredAt  time.Time      `bson:"occurredAt" json:"occurredAt"`
+	Data        map[string]any `bson:"data" json:"data"`
+}
+
+// New creates an event with a fresh ID. data is converted through its JSON
+// form, so the payload looks the same in the outbox and to every sink.
+func New(eventType, aggregateID string, data any) (Event, error) {
+	payload := map[string]any{}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return Event{}, err
+		}
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return Event{}, err
+		}
+	}
+
+	return Event{
+		ID:          bson.NewObjectID().Hex(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		OccurredAt:  time.Now(),
+		Data:        payload,
+	}, nil
+}
+
+// Decode converts the event data into v, typically the payload type the
+// event was recorded with
+func (e Event) Decode(v any) error {
+	raw, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Record creates an event and adds it to the outbox. Call it with the
+// transaction context of the change the event describes.
+func Record(ctx context.Context, outbox Outbox, eventType, aggregateID string, data any) error {
+	evt, err := New(eventType, aggregateID, data)
+	if err != nil {
+		return err
+	}
+	return outbox.Add(ctx, evt)
+}
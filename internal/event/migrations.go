@@ -0,0 +1,31 @@
+package event
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/database/migration"
+)
+
+// publishedRetention is how long published events stay in the outbox
+const publishedRetention = 7 * 24 * time.Hour
+
+// Migrations returns the event module's database migrations
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		migration.CreateIndexes(2026101907, "outbox_indexes", outboxCollection,
+			mongo.IndexModel{
+				// Claiming pending events: null publishedAt, oldest first, lease expired
+				Keys: bson.D{{Key: "publishedAt", Value: 1}, {Key: "_id", Value: 1}, {Key: "claimedUntil", Value: 1}},
+			},
+			mongo.IndexModel{
+				// Removes published events; pending ones have no date and are kept
+				Keys:    bson.D{{Key: "publishedAt", Value: 1}},
+				Options: options.Index().SetName("outbox_published_ttl").SetExpireAfterSeconds(int32(publishedRetention.Seconds())),
+			},
+		),
+	}
+}
@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+)
+
+// MockOutbox is a mock implementation of event.Outbox. Without AddFunc,
+// added events are kept in Events.
+type MockOutbox struct {
+	AddFunc           func(ctx context.Context, events ...event.Event) error
+	ClaimFunc         func(ctx context.Context, owner string, limit int, lease time.Duration) ([]event.Pending, error)
+	MarkPublishedFunc func(ctx context.Context, id string) error
+	MarkFailedFunc    func(ctx context.Context, id string, publishErr error, retryAt time.Time) error
+
+	Events []event.Event
+}
+
+func (m *MockOutbox) Add(ctx context.Context, events ...event.Event) error {
+	if m.AddFunc != nil {
+		return m.AddFunc(ctx, events...)
+	}
+	m.Events = append(m.Events, events...)
+	return nil
+}
+
+func (m *MockOutbox) Claim(ctx context.Context, owner string, limit int, lease time.Duration) ([]event.Pending, error) {
+	return m.ClaimFunc(ctx, owner, limit, lease)
+}
+
+func (m *MockOutbox) MarkPublished(ctx context.Context, id string) error {
+	return m.MarkPublishedFunc(ctx, id)
+}
+
+func (m *MockOutbox) MarkFailed(ctx context.Context, id string, publishErr error, retryAt time.Time) error {
+	return m.MarkFailedFunc(ctx, id, publishErr, retryAt)
+}
@@ -0,0 +1,121 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+)
+
+const outboxCollection = "outbox"
+
+// Outbox stores events in MongoDB until the relay has published them. Adding
+// events with a transaction context commits them together with the change
+// they describe.
+type Outbox interface {
+	Add(ctx context.Context, events ...Event) error
+	// Claim leases up to limit unpublished events, oldest first, so that
+	// concurrent relays don't publish the same events at the same time
+	Claim(ctx context.Context, owner string, limit int, lease time.Duration) ([]Pending, error)
+	MarkPublished(ctx context.Context, id string) error
+	// MarkFailed records a failed publish and keeps the event from being
+	// claimed again until retryAt
+	MarkFailed(ctx context.Context, id string, publishErr error, retryAt time.Time) error
+}
+
+// Pending is a claimed event and the number of times it has been claimed,
+// including this one
+type Pending struct {
+	Event
+	Attempts int
+}
+
+// outboxRecord is an event plus its delivery state
+type outboxRecord struct {
+	Event        `bson:",inline"`
+	PublishedAt  *time.Time `bson:"publishedAt"`
+	ClaimedUntil time.Time  `bson:"claimedUntil"`
+	ClaimedBy    string     `bson:"claimedBy,omitempty"`
+	Attempts     int        `bson:"attempts"`
+	LastError    string     `bson:"lastError,omitempty"`
+}
+
+type outboxMongo struct {
+	collection *mongo.Collection
+}
+
+// NewOutbox creates a MongoDB outbox
+func NewOutbox(db *database.MongoDB) Outbox {
+	return &outboxMongo{
+		collection: db.Collection(outboxCollection),
+	}
+}
+
+func (o *outboxMongo) Add(ctx context.Context, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	docs := make([]any, len(events))
+	for i, evt := range events {
+		// publishedAt is stored as null so pending events can use its index
+		docs[i] = outboxRecord{Event: evt}
+	}
+
+	_, err := o.collection.InsertMany(ctx, docs)
+	return err
+}
+
+func (o *outboxMongo) Claim(ctx context.Context, owner string, limit int, lease time.Duration) ([]Pending, error) {
+	claimed := make([]Pending, 0, limit)
+	for len(claimed) < limit {
+		now := time.Now()
+		filter := bson.M{
+			"publishedAt":  nil,
+			"claimedUntil": bson.M{"$lte": now},
+		}
+		update := bson.M{
+			"$set": bson.M{"claimedUntil": now.Add(lease), "claimedBy": owner},
+			"$inc": bson.M{"attempts": 1},
+		}
+		opts := options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "_id", Value: 1}}).
+			SetReturnDocument(options.After)
+
+		var record outboxRecord
+		err := o.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&record)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, Pending{Event: record.Event, Attempts: record.Attempts})
+	}
+
+	return claimed, nil
+}
+
+func (o *outboxMongo) MarkPublished(ctx context.Context, id string) error {
+	_, err := o.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"publishedAt": time.Now()},
+			"$unset": bson.M{"lastError": ""},
+		},
+	)
+	return err
+}
+
+func (o *outboxMongo) MarkFailed(ctx context.Context, id string, publishErr error, retryAt time.Time) error {
+	_, err := o.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"lastError": publishErr.Error(), "claimedUntil": retryAt}},
+	)
+	return err
+}
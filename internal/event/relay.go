@@ -0,0 +1,114 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
+)
+
+const (
+	// relayLease is how long a claimed event is reserved for one relay
+	relayLease = 30 * time.Second
+
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 10 * time.Minute
+)
+
+// Relay moves events from the outbox to the sinks. An event is marked
+// published only after every sink accepted it, so delivery is at least once:
+// a crash or a failing sink means the event is published again later.
+type Relay struct {
+	outbox    Outbox
+	sinks     []Sink
+	batchSize int
+	owner     string
+}
+
+// NewRelay creates a Relay publishing up to batchSize events per run
+func NewRelay(outbox Outbox, batchSize int, sinks ...Sink) *Relay {
+	return &Relay{
+		outbox:    outbox,
+		sinks:     sinks,
+		batchSize: batchSize,
+		owner:     utils.NewInstanceID(),
+	}
+}
+
+// RelayOnce publishes one batch of pending events and returns how many were
+// published
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	pending, err := r.outbox.Claim(ctx, r.owner, r.batchSize, relayLease)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, p := range pending {
+		if err := r.publish(ctx, p.Event); err != nil {
+			retryAt := time.Now().Add(utils.Backoff(p.Attempts, retryBaseDelay, retryMaxDelay))
+			logger.Warn("failed to publish event",
+				zap.Error(err),
+				zap.String("event_id", p.ID),
+				zap.String("type", p.Type),
+				zap.Int("attempts", p.Attempts),
+				zap.Time("retry_at", retryAt),
+			)
+			if err := r.outbox.MarkFailed(ctx, p.ID, err, retryAt); err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		if err := r.outbox.MarkPublished(ctx, p.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+func (r *Relay) publish(ctx context.Context, evt Event) error {
+	var errs []error
+	for _, sink := range r.sinks {
+		if err := sink.Publish(ctx, evt); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StartRelay publishes pending events every interval until ctx is cancelled.
+// Batches run back to back while the outbox has a backlog.
+func StartRelay(ctx context.Context, relay *Relay, interval time.Duration) {
+	logger.Info("starting event relay", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("event relay stopped")
+			return
+		case <-ticker.C:
+			for {
+				published, err := relay.RelayOnce(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						logger.Error("event relay failed", zap.Error(err))
+					}
+					break
+				}
+				if published < relay.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
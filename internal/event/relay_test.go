@@ -0,0 +1,97 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event/mock"
+)
+
+func TestNew_PayloadUsesJSONNames(t *testing.T) {
+	type payload struct {
+		FirstName string `json:"firstName"`
+	}
+
+	evt, err := event.New("user.created", "42", payload{FirstName: "Jane"})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, evt.ID)
+	assert.Equal(t, map[string]any{"firstName": "Jane"}, evt.Data)
+}
+
+func TestRelayOnce_PublishesToEverySink(t *testing.T) {
+	// 1. Setup Mock with two pending events
+	var published []string
+	outbox := &mock.MockOutbox{
+		ClaimFunc: func(ctx context.Context, owner string, limit int, lease time.Duration) ([]event.Pending, error) {
+			return []event.Pending{
+				{Event: event.Event{ID: "1", Type: "user.created"}, Attempts: 1},
+				{Event: event.Event{ID: "2", Type: "user.deleted"}, Attempts: 1},
+			}, nil
+		},
+		MarkPublishedFunc: func(ctx context.Context, id string) error {
+			published = append(published, id)
+			return nil
+		},
+	}
+
+	var created, all int
+	dispatcher := event.NewDispatcher()
+	dispatcher.Subscribe("user.created", func(ctx context.Context, evt event.Event) error {
+		created++
+		return nil
+	})
+	dispatcher.Subscribe(event.AllEvents, func(ctx context.Context, evt event.Event) error {
+		all++
+		return nil
+	})
+
+	// 2. Call Method
+	n, err := event.NewRelay(outbox, 10, dispatcher).RelayOnce(context.Background())
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"1", "2"}, published)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 2, all)
+}
+
+func TestRelayOnce_FailedEventIsRetriedLater(t *testing.T) {
+	// 1. Setup Mock with an event on its third attempt and a failing handler
+	var failedID string
+	var retryAt time.Time
+	outbox := &mock.MockOutbox{
+		ClaimFunc: func(ctx context.Context, owner string, limit int, lease time.Duration) ([]event.Pending, error) {
+			return []event.Pending{{Event: event.Event{ID: "1", Type: "user.created"}, Attempts: 3}}, nil
+		},
+		MarkPublishedFunc: func(ctx context.Context, id string) error {
+			t.Fatal("event must not be marked published")
+			return nil
+		},
+		MarkFailedFunc: func(ctx context.Context, id string, publishErr error, at time.Time) error {
+			failedID, retryAt = id, at
+			return nil
+		},
+	}
+
+	dispatcher := event.NewDispatcher()
+	dispatcher.Subscribe("user.created", func(ctx context.Context, evt event.Event) error {
+		return errors.New("crm unavailable")
+	})
+
+	// 2. Call Method
+	n, err := event.NewRelay(outbox, 10, dispatcher).RelayOnce(context.Background())
+
+	// 3. Assertions: backoff doubles per attempt, 4s on the third
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, "1", failedID)
+	assert.WithinDuration(t, time.Now().Add(4*time.Second), retryAt, time.Second)
+}
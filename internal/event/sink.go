@@ -0,0 +1,101 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+)
+
+// Sink is a destination the relay publishes events to
+type Sink interface {
+	Publish(ctx context.Context, evt Event) error
+}
+
+type redisStreamSink struct {
+	redis  *database.Redis
+	stream string
+	maxLen int64
+}
+
+// NewRedisStreamSink publishes events to a Redis Stream, trimmed to roughly
+// maxLen entries (0 keeps everything). Each entry carries the event fields,
+// with data as JSON; consumers deduplicate on the id field.
+func NewRedisStreamSink(redis *database.Redis, stream string, maxLen int64) Sink {
+	return &redisStreamSink{
+		redis:  redis,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+func (s *redisStreamSink) Publish(ctx context.Context, evt Event) error {
+	data, err := json.Marshal(evt.Data)
+	if err != nil {
+		return err
+	}
+
+	return s.redis.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		MaxLen: s.maxLen,
+		Approx: s.maxLen > 0,
+		Values: map[string]any{
+			"id":          evt.ID,
+			"type":        evt.Type,
+			"aggregateId": evt.AggregateID,
+			"occurredAt":  evt.OccurredAt.Format(time.RFC3339Nano),
+			"data":        string(data),
+		},
+	}).Err()
+}
+
+// Handler reacts to an event in process. Events can be delivered more than
+// once, so handlers must be idempotent on evt.ID.
+type Handler func(ctx context.Context, evt Event) error
+
+// AllEvents subscribes a handler to every event type
+const AllEvents = "*"
+
+// Dispatcher is a Sink that calls in-process handlers subscribed by event
+// type. Modules subscribe at startup; tests can use it to observe events.
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewDispatcher creates a Dispatcher without handlers
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers handler for eventType, or for every type with AllEvents
+func (d *Dispatcher) Subscribe(eventType string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[eventType] = append(d.handlers[eventType], handler)
+}
+
+// Publish calls every matching handler and returns their errors joined. A
+// failure means the event is published again, to all handlers.
+func (d *Dispatcher) Publish(ctx context.Context, evt Event) error {
+	d.mu.RLock()
+	handlers := append(append([]Handler{}, d.handlers[evt.Type]...), d.handlers[AllEvents]...)
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, evt); err != nil {
+			errs = append(errs, fmt.Errorf("%s handler: %w", evt.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// DeleteFailedJob godoc
+// @Summary      Delete failed job
+// @Description  Discard a dead-lettered job (ADMIN only)
+// @Tags         jobs
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Job ID"
+// @Success      200 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /jobs/failed/{id} [delete]
+func (h *JobHandler) DeleteFailedJob(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.queue.DeleteDead(c.Context(), id); err != nil {
+		if errors.Is(err, queue.ErrJobNotFound) {
+			return response.NotFound(c, "failed job not found")
+		}
+		logger.Error("failed to delete job", zap.Error(err), zap.String("job_id", id))
+		return response.InternalServerError(c, "failed to delete job")
+	}
+
+	logger.Info("failed job deleted", zap.String("job_id", id))
+	return response.Success(c, fiber.StatusOK, "job deleted", nil)
+}
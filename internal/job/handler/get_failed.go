@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetFailedJob godoc
+// @Summary      Get failed job
+// @Description  Get a dead-lettered job with its payload and last error (ADMIN only)
+// @Tags         jobs
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Job ID"
+// @Success      200 {object} response.Response{data=queue.Job}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /jobs/failed/{id} [get]
+func (h *JobHandler) GetFailedJob(c *fiber.Ctx) error {
+	job, err := h.queue.GetDead(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, queue.ErrJobNotFound) {
+			return response.NotFound(c, "failed job not found")
+		}
+		logger.Error("failed to get failed job", zap.Error(err))
+		return response.InternalServerError(c, "failed to get failed job")
+	}
+
+	return response.Success(c, fiber.StatusOK, "failed job retrieved successfully", job)
+}
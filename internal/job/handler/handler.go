@@ -0,0 +1,15 @@
+package handler
+
+import "github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+
+// JobHandler handles background job administration HTTP requests
+type JobHandler struct {
+	queue *queue.Queue
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(jobQueue *queue.Queue) *JobHandler {
+	return &JobHandler{
+		queue: jobQueue,
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetFailedJobs godoc
+// @Summary      Get failed jobs
+// @Description  Get the dead-letter queue: jobs that used all their attempts or failed permanently, most recent first (ADMIN only)
+// @Tags         jobs
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page query int false "Page number" default(1)
+// @Param        per-page query int false "Items per page" default(10)
+// @Success      200 {object} response.PaginatedResponse{data=[]queue.Job}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /jobs/failed [get]
+func (h *JobHandler) GetFailedJobs(c *fiber.Ctx) error {
+	page, perPage := pagination.ParsePage(c)
+
+	jobs, total, err := h.queue.Dead(c.Context(), page, perPage)
+	if err != nil {
+		logger.Error("failed to get failed jobs", zap.Error(err))
+		return response.InternalServerError(c, "failed to get failed jobs")
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "failed jobs retrieved successfully", jobs, page, perPage, total)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// RetryFailedJob godoc
+// @Summary      Retry failed job
+// @Description  Move a dead-lettered job back to the queue with a fresh attempt budget (ADMIN only)
+// @Tags         jobs
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Job ID"
+// @Success      202 {object} response.Response{data=queue.Job}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /jobs/failed/{id}/retry [post]
+func (h *JobHandler) RetryFailedJob(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	job, err := h.queue.Retry(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, queue.ErrJobNotFound) {
+			return response.NotFound(c, "failed job not found")
+		}
+		logger.Error("failed to retry job", zap.Error(err), zap.String("job_id", id))
+		return response.InternalServerError(c, "failed to retry job")
+	}
+
+	logger.Info("failed job requeued", zap.String("job_id", id), zap.String("job_type", job.Type))
+	return response.Success(c, fiber.StatusAccepted, "job requeued", job)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetStats godoc
+// @Summary      Get job queue stats
+// @Description  Count the ready, scheduled, running and dead-lettered jobs (ADMIN only)
+// @Tags         jobs
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} response.Response{data=queue.Stats}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /jobs/stats [get]
+func (h *JobHandler) GetStats(c *fiber.Ctx) error {
+	stats, err := h.queue.Stats(c.Context())
+	if err != nil {
+		logger.Error("failed to get job queue stats", zap.Error(err))
+		return response.InternalServerError(c, "failed to get job queue stats")
+	}
+
+	return response.Success(c, fiber.StatusOK, "job queue stats retrieved successfully", stats)
+}
@@ -0,0 +1,20 @@
+package job
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/job/handler"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+)
+
+// RegisterRoutes registers the background job administration routes (ADMIN only)
+func RegisterRoutes(router fiber.Router, h *handler.JobHandler, tokenMaker *token.PasetoMaker) {
+	jobs := router.Group("/jobs", middleware.AuthMiddleware(tokenMaker), middleware.RequireAdmin())
+
+	jobs.Get("/stats", h.GetStats)
+	jobs.Get("/failed", h.GetFailedJobs)
+	jobs.Get("/failed/:id", h.GetFailedJob)
+	jobs.Post("/failed/:id/retry", h.RetryFailedJob)
+	jobs.Delete("/failed/:id", h.DeleteFailedJob)
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// LoginEventResponse represents a login attempt
+type LoginEventResponse struct {
+	ID              string              `json:"id"`
+	UserID          string              `json:"userId,omitempty"`
+	Email           string              `json:"email"`
+	Outcome         entity.LoginOutcome `json:"outcome"`
+	Reason          string              `json:"reason,omitempty"`
+	IP              string              `json:"ip,omitempty"`
+	UserAgent       string              `json:"userAgent,omitempty"`
+	Device          string              `json:"device"`
+	Country         string              `json:"country,omitempty"`
+	CountryName     string              `json:"countryName,omitempty"`
+	City            string              `json:"city,omitempty"`
+	NewDevice       bool                `json:"newDevice"`
+	UnusualLocation bool                `json:"unusualLocation"`
+	CreatedAt       time.Time           `json:"createdAt"`
+}
+
+// ToLoginEventResponse converts a login event entity to a response
+func ToLoginEventResponse(event *entity.LoginEvent) LoginEventResponse {
+	response := LoginEventResponse{
+		ID:              event.ID.Hex(),
+		Email:           event.Email,
+		Outcome:         event.Outcome,
+		Reason:          event.Reason,
+		IP:              event.IP,
+		UserAgent:       event.UserAgent,
+		Device:          event.Device,
+		Country:         event.Country,
+		CountryName:     event.CountryName,
+		City:            event.City,
+		NewDevice:       event.NewDevice,
+		UnusualLocation: event.UnusualLocation,
+		CreatedAt:       event.CreatedAt,
+	}
+	if !event.UserID.IsZero() {
+		response.UserID = event.UserID.Hex()
+	}
+	return response
+}
+
+// ToLoginEventResponses converts login event entities to responses
+func ToLoginEventResponses(events []*entity.LoginEvent) []LoginEventResponse {
+	responses := make([]LoginEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = ToLoginEventResponse(event)
+	}
+	return responses
+}
@@ -0,0 +1,15 @@
+package handler
+
+import "github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/service"
+
+// LoginHistoryHandler handles login history HTTP requests
+type LoginHistoryHandler struct {
+	loginHistoryService service.LoginHistoryService
+}
+
+// NewLoginHistoryHandler creates a new login history handler
+func NewLoginHistoryHandler(loginHistoryService service.LoginHistoryService) *LoginHistoryHandler {
+	return &LoginHistoryHandler{
+		loginHistoryService: loginHistoryService,
+	}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/query"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// GetMyLoginHistory godoc
+// @Summary      Get my login history
+// @Description  Get the current user's successful and failed login attempts, newest first
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        outcome query string false "Only attempts with this outcome" Enums(success, failure)
+// @Param        new-device query bool false "Only attempts from a new device, or only from known ones"
+// @Param        unusual-location query bool false "Only attempts from an unusual location, or only from usual ones"
+// @Param        created-at[gte] query string false "Attempted on or after (RFC 3339 or YYYY-MM-DD); also gt, lt, lte"
+// @Param        page query int false "Page number" default(1)
+// @Param        per-page query int false "Items per page" default(10)
+// @Success      200 {object} response.PaginatedResponse{data=[]dto.LoginEventResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/me/login-history [get]
+func (h *LoginHistoryHandler) GetMyLoginHistory(c *fiber.Ctx) error {
+	payload := middleware.GetAuthPayload(c)
+	if payload == nil {
+		return response.Unauthorized(c, "authentication required")
+	}
+
+	return h.list(c, payload.UserID)
+}
+
+// GetUserLoginHistory godoc
+// @Summary      Get a user's login history
+// @Description  Get a user's successful and failed login attempts, newest first (ADMIN only)
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "User ID"
+// @Param        outcome query string false "Only attempts with this outcome" Enums(success, failure)
+// @Param        new-device query bool false "Only attempts from a new device, or only from known ones"
+// @Param        unusual-location query bool false "Only attempts from an unusual location, or only from usual ones"
+// @Param        created-at[gte] query string false "Attempted on or after (RFC 3339 or YYYY-MM-DD); also gt, lt, lte"
+// @Param        page query int false "Page number" default(1)
+// @Param        per-page query int false "Items per page" default(10)
+// @Success      200 {object} response.PaginatedResponse{data=[]dto.LoginEventResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/{id}/login-history [get]
+func (h *LoginHistoryHandler) GetUserLoginHistory(c *fiber.Ctx) error {
+	return h.list(c, c.Params("id"))
+}
+
+func (h *LoginHistoryHandler) list(c *fiber.Ctx, userID string) error {
+	page, perPage := pagination.ParsePage(c)
+
+	q, err := loginEventQuerySchema.Parse(c.Queries())
+	if err != nil {
+		return response.BadRequest(c, "invalid query", err.Error())
+	}
+
+	events, total, err := h.loginHistoryService.GetByUser(c.Context(), userID, q.Filter, page, perPage)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get login history")
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "login history retrieved successfully", events, page, perPage, total)
+}
+
+// loginEventQuerySchema whitelists the fields clients may filter login
+// attempts by
+var loginEventQuerySchema = query.Schema{
+	Fields: map[string]query.Field{
+		"outcome": {
+			Name:      "outcome",
+			Type:      query.String,
+			Operators: []query.Operator{query.OpEq},
+			Values:    []string{string(entity.LoginSucceeded), string(entity.LoginFailed)},
+		},
+		"new-device":       {Name: "newDevice", Type: query.Bool},
+		"unusual-location": {Name: "unusualLocation", Type: query.Bool},
+		"created-at":       {Name: "createdAt", Type: query.Time},
+	},
+}
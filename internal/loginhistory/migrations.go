@@ -0,0 +1,38 @@
+package loginhistory
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/database/migration"
+)
+
+// loginEventRetention is how long login attempts are kept
+const loginEventRetention = 180 * 24 * time.Hour
+
+// Migrations returns the login history module's database migrations
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		migration.CreateIndexes(2026101913, "login_events_indexes", "login_events",
+			mongo.IndexModel{
+				// A user's history, newest first
+				Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+			},
+			mongo.IndexModel{
+				// Known devices and locations of a user
+				Keys: bson.D{{Key: "userId", Value: 1}, {Key: "outcome", Value: 1}, {Key: "createdAt", Value: -1}},
+			},
+			mongo.IndexModel{
+				// Failed attempts against one email, including unknown ones
+				Keys: bson.D{{Key: "email", Value: 1}, {Key: "createdAt", Value: -1}},
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "createdAt", Value: 1}},
+				Options: options.Index().SetName("login_events_ttl").SetExpireAfterSeconds(int32(loginEventRetention.Seconds())),
+			},
+		),
+	}
+}
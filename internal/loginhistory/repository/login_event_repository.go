@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// LoginEventRepository defines the interface for login event data access
+type LoginEventRepository interface {
+	// Create stores event. Creating an event whose ID is already stored is a
+	// no-op, so a rerun queue job doesn't record the attempt twice.
+	Create(ctx context.Context, event *entity.LoginEvent) error
+	// FindByUser returns a page of a user's login attempts matching filter,
+	// newest first
+	FindByUser(ctx context.Context, userID string, filter bson.M, page, pageSize int) ([]*entity.LoginEvent, int64, error)
+	// FindRecentSuccesses returns up to limit of a user's latest successful
+	// logins, newest first
+	FindRecentSuccesses(ctx context.Context, userID string, limit int) ([]*entity.LoginEvent, error)
+}
+
+type loginEventRepositoryMongo struct {
+	collection *mongo.Collection
+}
+
+// NewLoginEventRepository creates a new MongoDB login event repository
+func NewLoginEventRepository(db *database.MongoDB) LoginEventRepository {
+	return &loginEventRepositoryMongo{
+		collection: db.Collection("login_events"),
+	}
+}
+
+func (r *loginEventRepositoryMongo) Create(ctx context.Context, event *entity.LoginEvent) error {
+	if event.ID.IsZero() {
+		event.ID = bson.NewObjectID()
+	}
+	// Attempts are stored from the job queue; keep the time they happened
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	_, err := r.collection.InsertOne(ctx, event)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *loginEventRepositoryMongo) FindByUser(ctx context.Context, userID string, filter bson.M, page, pageSize int) ([]*entity.LoginEvent, int64, error) {
+	objectID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return []*entity.LoginEvent{}, 0, nil
+	}
+
+	scoped := bson.M{}
+	maps.Copy(scoped, filter)
+	scoped["userId"] = objectID
+	filter = scoped
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	events, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+func (r *loginEventRepositoryMongo) FindRecentSuccesses(ctx context.Context, userID string, limit int) ([]*entity.LoginEvent, error) {
+	objectID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return []*entity.LoginEvent{}, nil
+	}
+
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetProjection(bson.M{"device": 1, "country": 1, "createdAt": 1})
+
+	return r.find(ctx, bson.M{"userId": objectID, "outcome": entity.LoginSucceeded}, opts)
+}
+
+func (r *loginEventRepositoryMongo) find(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]*entity.LoginEvent, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []*entity.LoginEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
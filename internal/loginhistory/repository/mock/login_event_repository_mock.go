@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// MockLoginEventRepository is a mock implementation of repository.LoginEventRepository
+type MockLoginEventRepository struct {
+	CreateFunc              func(ctx context.Context, event *entity.LoginEvent) error
+	FindByUserFunc          func(ctx context.Context, userID string, filter bson.M, page, pageSize int) ([]*entity.LoginEvent, int64, error)
+	FindRecentSuccessesFunc func(ctx context.Context, userID string, limit int) ([]*entity.LoginEvent, error)
+}
+
+func (m *MockLoginEventRepository) Create(ctx context.Context, event *entity.LoginEvent) error {
+	return m.CreateFunc(ctx, event)
+}
+
+func (m *MockLoginEventRepository) FindByUser(ctx context.Context, userID string, filter bson.M, page, pageSize int) ([]*entity.LoginEvent, int64, error) {
+	return m.FindByUserFunc(ctx, userID, filter, page, pageSize)
+}
+
+func (m *MockLoginEventRepository) FindRecentSuccesses(ctx context.Context, userID string, limit int) ([]*entity.LoginEvent, error) {
+	return m.FindRecentSuccessesFunc(ctx, userID, limit)
+}
@@ -0,0 +1,18 @@
+package loginhistory
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/handler"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// RegisterRoutes registers the login history routes
+func RegisterRoutes(router fiber.Router, h *handler.LoginHistoryHandler, tokenMaker *token.PasetoMaker) {
+	auth := middleware.AuthMiddleware(tokenMaker)
+
+	router.Get("/users/me/login-history", auth, h.GetMyLoginHistory)
+	router.Get("/users/:id/login-history", auth, middleware.RequireRoles(entity.RoleAdmin), h.GetUserLoginHistory)
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+
+	auditService "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/repository"
+	notificationDto "github.com/itsahyarr/gofiber-boilerplate/internal/notification/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/geoip"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/useragent"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// NotificationSuspiciousLogin is the notification type users get for a
+// login from a new device or an unusual location
+const NotificationSuspiciousLogin = "auth.suspicious_login"
+
+// JobType is the queue job that records a login attempt
+const JobType = "login.record"
+
+// knownLogins is how many of a user's latest successful logins a new login
+// is compared with
+const knownLogins = 50
+
+// Attempt describes a login attempt
+type Attempt struct {
+	Email string
+	// User is nil when the email matched no account
+	User *entity.User
+	// Reason is why the attempt failed; empty for successful logins
+	Reason string
+}
+
+// Recorder records login attempts
+type Recorder interface {
+	// RecordLogin queues an attempt; RunJob stores it and flags suspicious
+	// successful logins off the login path. Failures are logged rather than
+	// returned so they don't affect the login.
+	RecordLogin(ctx context.Context, attempt Attempt)
+}
+
+// Notifier adds a notification to a user's inbox; the notification service
+// implements it
+type Notifier interface {
+	Notify(ctx context.Context, userID string, req *notificationDto.CreateNotificationRequest) error
+}
+
+// LoginHistoryService defines the interface for login history operations
+type LoginHistoryService interface {
+	Recorder
+	// RunJob is the queue handler of JobType
+	RunJob(ctx context.Context, job *queue.Job) error
+	GetByUser(ctx context.Context, userID string, filter bson.M, page, pageSize int) ([]dto.LoginEventResponse, int64, error)
+}
+
+type loginHistoryServiceImpl struct {
+	loginEventRepo repository.LoginEventRepository
+	locator        geoip.Locator
+	notifier       Notifier
+	queue          *queue.Queue
+}
+
+// NewLoginHistoryService creates a new login history service. The API only
+// queues attempts and needs jobQueue; the worker runs RunJob and needs
+// locator and notifier.
+func NewLoginHistoryService(loginEventRepo repository.LoginEventRepository, locator geoip.Locator, notifier Notifier, jobQueue *queue.Queue) LoginHistoryService {
+	return &loginHistoryServiceImpl{
+		loginEventRepo: loginEventRepo,
+		locator:        locator,
+		notifier:       notifier,
+		queue:          jobQueue,
+	}
+}
+
+func (s *loginHistoryServiceImpl) RecordLogin(ctx context.Context, attempt Attempt) {
+	// Recorded after the login was decided; a client disconnecting must not
+	// lose the record
+	ctx = context.WithoutCancel(ctx)
+
+	event := newLoginEvent(ctx, attempt)
+	if _, err := s.queue.Enqueue(ctx, JobType, event, queue.EnqueueOptions{}); err != nil {
+		logger.Error("failed to queue login record", zap.Error(err), zap.String("email", event.Email))
+	}
+}
+
+// newLoginEvent describes an attempt with the request it came from. The ID is
+// assigned here so a rerun of the job stores and notifies about the same event.
+func newLoginEvent(ctx context.Context, attempt Attempt) *entity.LoginEvent {
+	md := auditService.MetadataFrom(ctx)
+
+	event := &entity.LoginEvent{
+		ID:        bson.NewObjectID(),
+		Email:     strings.ToLower(attempt.Email),
+		Outcome:   entity.LoginSucceeded,
+		Reason:    attempt.Reason,
+		IP:        md.IP,
+		UserAgent: md.UserAgent,
+		Device:    useragent.Parse(md.UserAgent).String(),
+		CreatedAt: time.Now(),
+	}
+	if attempt.Reason != "" {
+		event.Outcome = entity.LoginFailed
+	}
+	if attempt.User != nil {
+		event.UserID = attempt.User.ID
+	}
+	return event
+}
+
+// RunJob locates a queued login, compares it with the user's history, stores
+// it and notifies the user of a suspicious one. Only a failed insert is
+// retried.
+func (s *loginHistoryServiceImpl) RunJob(ctx context.Context, job *queue.Job) error {
+	event := &entity.LoginEvent{}
+	if err := job.Decode(event); err != nil {
+		return err
+	}
+
+	location, err := s.locator.Lookup(event.IP)
+	if err != nil {
+		logger.Warn("failed to locate login ip", zap.Error(err), zap.String("ip", event.IP))
+	}
+	if location != nil {
+		event.Country, event.CountryName, event.City = location.Country, location.CountryName, location.City
+	}
+
+	if event.Outcome == entity.LoginSucceeded && !event.UserID.IsZero() {
+		if err := s.flag(ctx, event); err != nil {
+			logger.Warn("failed to compare login with history", zap.Error(err), zap.String("user_id", event.UserID.Hex()))
+		}
+	}
+
+	if err := s.loginEventRepo.Create(ctx, event); err != nil {
+		logger.Error("failed to record login", zap.Error(err), zap.String("email", event.Email))
+		return err
+	}
+
+	if event.IsSuspicious() {
+		logger.Info("suspicious login",
+			zap.String("user_id", event.UserID.Hex()),
+			zap.String("device", event.Device),
+			zap.String("country", event.Country),
+			zap.Bool("new_device", event.NewDevice),
+			zap.Bool("unusual_location", event.UnusualLocation),
+		)
+		s.notify(ctx, event)
+	}
+	return nil
+}
+
+// flag compares a successful login with the user's earlier ones. A first
+// login is never flagged, and locations are only compared when both the new
+// and an earlier login could be located.
+func (s *loginHistoryServiceImpl) flag(ctx context.Context, event *entity.LoginEvent) error {
+	known, err := s.loginEventRepo.FindRecentSuccesses(ctx, event.UserID.Hex(), knownLogins)
+	if err != nil {
+		return err
+	}
+
+	// Skip the event itself when an earlier run of the same job stored it
+	known = slices.DeleteFunc(known, func(login *entity.LoginEvent) bool { return login.ID == event.ID })
+	if len(known) == 0 {
+		return nil
+	}
+
+	deviceSeen, countrySeen, located := false, false, false
+	for _, login := range known {
+		deviceSeen = deviceSeen || login.Device == event.Device
+		if login.Country != "" {
+			located = true
+			countrySeen = countrySeen || login.Country == event.Country
+		}
+	}
+
+	event.NewDevice = !deviceSeen
+	event.UnusualLocation = event.Country != "" && located && !countrySeen
+	return nil
+}
+
+// notify tells the user about a suspicious login; best effort
+func (s *loginHistoryServiceImpl) notify(ctx context.Context, event *entity.LoginEvent) {
+	where := event.IP
+	if place := placeName(event); place != "" {
+		where = place + " (" + event.IP + ")"
+	}
+
+	err := s.notifier.Notify(ctx, event.UserID.Hex(), &notificationDto.CreateNotificationRequest{
+		Type:  NotificationSuspiciousLogin,
+		Title: "New sign-in to your account",
+		Body:  event.Device + " signed in from " + where + ". If this wasn't you, change your password.",
+		Data: map[string]any{
+			"loginEventId":    event.ID.Hex(),
+			"device":          event.Device,
+			"ip":              event.IP,
+			"country":         event.Country,
+			"city":            event.City,
+			"newDevice":       event.NewDevice,
+			"unusualLocation": event.UnusualLocation,
+		},
+		Key: "login:" + event.ID.Hex(),
+	})
+	if err != nil {
+		logger.Warn("failed to notify user of suspicious login", zap.Error(err), zap.String("user_id", event.UserID.Hex()))
+	}
+}
+
+// placeName describes the location as "City, Country"
+func placeName(event *entity.LoginEvent) string {
+	country := event.CountryName
+	if country == "" {
+		country = event.Country
+	}
+	if event.City != "" && country != "" {
+		return event.City + ", " + country
+	}
+	return country
+}
+
+func (s *loginHistoryServiceImpl) GetByUser(ctx context.Context, userID string, filter bson.M, page, pageSize int) ([]dto.LoginEventResponse, int64, error) {
+	events, total, err := s.loginEventRepo.FindByUser(ctx, userID, filter, page, pageSize)
+	if err != nil {
+		logger.Error("failed to get login history", zap.Error(err), zap.String("user_id", userID))
+		return nil, 0, err
+	}
+
+	return dto.ToLoginEventResponses(events), total, nil
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	auditService "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/repository/mock"
+	notificationDto "github.com/itsahyarr/gofiber-boilerplate/internal/notification/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/geoip"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+const (
+	chromeOnWindows = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+	firefoxOnLinux  = "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0"
+)
+
+// fakeLocator locates IPs from a fixed table
+type fakeLocator map[string]*geoip.Location
+
+func (l fakeLocator) Lookup(ip string) (*geoip.Location, error) {
+	return l[ip], nil
+}
+
+type fakeNotifier struct {
+	notifications []*notificationDto.CreateNotificationRequest
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, userID string, req *notificationDto.CreateNotificationRequest) error {
+	n.notifications = append(n.notifications, req)
+	return nil
+}
+
+// memoryHistory is an in-memory login history
+func memoryHistory() (*mock.MockLoginEventRepository, *[]*entity.LoginEvent) {
+	var events []*entity.LoginEvent
+	repo := &mock.MockLoginEventRepository{
+		CreateFunc: func(ctx context.Context, event *entity.LoginEvent) error {
+			for _, stored := range events {
+				if stored.ID == event.ID {
+					return nil
+				}
+			}
+			events = append(events, event)
+			return nil
+		},
+		FindRecentSuccessesFunc: func(ctx context.Context, userID string, limit int) ([]*entity.LoginEvent, error) {
+			var found []*entity.LoginEvent
+			for _, event := range events {
+				if event.UserID.Hex() == userID && event.Outcome == entity.LoginSucceeded {
+					found = append(found, event)
+				}
+			}
+			return found, nil
+		},
+	}
+	return repo, &events
+}
+
+// queued returns a func recording attempts through a job queue, the way the
+// API queues them and the worker stores them
+func queued(t *testing.T, repo *mock.MockLoginEventRepository, locator geoip.Locator, notifier Notifier) func(ctx context.Context, attempt Attempt) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	jobQueue := queue.NewQueue(&database.Redis{Client: client}, "test", time.Minute, 3)
+	service := NewLoginHistoryService(repo, locator, notifier, jobQueue)
+
+	return func(ctx context.Context, attempt Attempt) {
+		service.RecordLogin(ctx, attempt)
+		job, err := jobQueue.Dequeue(context.Background())
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, JobType, job.Type)
+		require.NoError(t, service.RunJob(context.Background(), job))
+	}
+}
+
+func loginFrom(ip, userAgent string) context.Context {
+	return auditService.WithMetadata(context.Background(), auditService.Metadata{IP: ip, UserAgent: userAgent})
+}
+
+func TestRecordLogin_FlagsNewDevice(t *testing.T) {
+	// 1. Setup Mocks
+	repo, events := memoryHistory()
+	notifier := &fakeNotifier{}
+	record := queued(t, repo, geoip.Disabled(), notifier)
+	user := &entity.User{ID: bson.NewObjectID(), Email: "jane@example.com"}
+
+	// 2. Call Method: a first login, the same device again, then a new one
+	record(loginFrom("203.0.113.7", chromeOnWindows), Attempt{Email: "Jane@example.com", User: user})
+	record(loginFrom("203.0.113.7", chromeOnWindows), Attempt{Email: "jane@example.com", User: user})
+	record(loginFrom("203.0.113.7", firefoxOnLinux), Attempt{Email: "jane@example.com", User: user})
+
+	// 3. Assertions
+	if assert.Len(t, *events, 3) {
+		assert.Equal(t, "jane@example.com", (*events)[0].Email)
+		assert.Equal(t, "Chrome on Windows", (*events)[0].Device)
+		assert.WithinDuration(t, time.Now(), (*events)[0].CreatedAt, time.Minute)
+		assert.False(t, (*events)[0].IsSuspicious())
+		assert.False(t, (*events)[1].IsSuspicious())
+		assert.True(t, (*events)[2].NewDevice)
+		assert.False(t, (*events)[2].UnusualLocation)
+	}
+	if assert.Len(t, notifier.notifications, 1) {
+		assert.Equal(t, NotificationSuspiciousLogin, notifier.notifications[0].Type)
+		assert.Equal(t, "login:"+(*events)[2].ID.Hex(), notifier.notifications[0].Key)
+	}
+}
+
+func TestRecordLogin_FlagsUnusualLocation(t *testing.T) {
+	// 1. Setup Mocks
+	repo, events := memoryHistory()
+	notifier := &fakeNotifier{}
+	locator := fakeLocator{
+		"203.0.113.7":  {Country: "ID", CountryName: "Indonesia", City: "Jakarta"},
+		"198.51.100.9": {Country: "BR", CountryName: "Brazil", City: "São Paulo"},
+	}
+	record := queued(t, repo, locator, notifier)
+	user := &entity.User{ID: bson.NewObjectID()}
+
+	// 2. Call Method: a known device signs in from another country, then from
+	// an address that can't be located
+	record(loginFrom("203.0.113.7", chromeOnWindows), Attempt{User: user})
+	record(loginFrom("198.51.100.9", chromeOnWindows), Attempt{User: user})
+	record(loginFrom("10.0.0.1", chromeOnWindows), Attempt{User: user})
+
+	// 3. Assertions
+	if assert.Len(t, *events, 3) {
+		assert.Equal(t, "Jakarta", (*events)[0].City)
+		assert.False(t, (*events)[1].NewDevice)
+		assert.True(t, (*events)[1].UnusualLocation)
+		assert.False(t, (*events)[2].IsSuspicious())
+	}
+	if assert.Len(t, notifier.notifications, 1) {
+		assert.Contains(t, notifier.notifications[0].Body, "São Paulo, Brazil")
+	}
+}
+
+func TestRecordLogin_FailedAttemptIsNotFlagged(t *testing.T) {
+	// 1. Setup Mocks with an earlier login from another device
+	repo, events := memoryHistory()
+	notifier := &fakeNotifier{}
+	record := queued(t, repo, geoip.Disabled(), notifier)
+	user := &entity.User{ID: bson.NewObjectID()}
+	record(loginFrom("203.0.113.7", chromeOnWindows), Attempt{User: user})
+
+	// 2. Call Method
+	record(loginFrom("198.51.100.9", firefoxOnLinux), Attempt{Email: "jane@example.com", User: user, Reason: "invalid email or password"})
+	record(loginFrom("198.51.100.9", firefoxOnLinux), Attempt{Email: "nobody@example.com", Reason: "invalid email or password"})
+
+	// 3. Assertions
+	if assert.Len(t, *events, 3) {
+		assert.Equal(t, entity.LoginFailed, (*events)[1].Outcome)
+		assert.Equal(t, "invalid email or password", (*events)[1].Reason)
+		assert.False(t, (*events)[1].IsSuspicious())
+		assert.True(t, (*events)[2].UserID.IsZero())
+	}
+	assert.Empty(t, notifier.notifications)
+}
+
+func TestRunJob_RerunIsIdempotent(t *testing.T) {
+	// 1. Setup Mocks
+	repo, events := memoryHistory()
+	notifier := &fakeNotifier{}
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	jobQueue := queue.NewQueue(&database.Redis{Client: client}, "test", time.Minute, 3)
+	service := NewLoginHistoryService(repo, geoip.Disabled(), notifier, jobQueue)
+	user := &entity.User{ID: bson.NewObjectID(), Email: "jane@example.com"}
+
+	next := func() *queue.Job {
+		job, err := jobQueue.Dequeue(context.Background())
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		return job
+	}
+	service.RecordLogin(loginFrom("203.0.113.7", chromeOnWindows), Attempt{Email: "jane@example.com", User: user})
+	require.NoError(t, service.RunJob(context.Background(), next()))
+	service.RecordLogin(loginFrom("203.0.113.7", firefoxOnLinux), Attempt{Email: "jane@example.com", User: user})
+	job := next()
+
+	// 2. Call Method: the job runs again, e.g. after a visibility timeout
+	require.NoError(t, service.RunJob(context.Background(), job))
+	require.NoError(t, service.RunJob(context.Background(), job))
+
+	// 3. Assertions
+	if assert.Len(t, *events, 2) {
+		assert.True(t, (*events)[1].NewDevice)
+	}
+	if assert.Len(t, notifier.notifications, 2) {
+		assert.Equal(t, "login:"+(*events)[1].ID.Hex(), notifier.notifications[0].Key)
+		assert.Equal(t, notifier.notifications[0].Key, notifier.notifications[1].Key)
+	}
+}
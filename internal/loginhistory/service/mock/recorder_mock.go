@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory/service"
+)
+
+// MockRecorder is a mock implementation of service.Recorder that keeps
+// recorded attempts in Attempts
+type MockRecorder struct {
+	Attempts []service.Attempt
+}
+
+func (m *MockRecorder) RecordLogin(ctx context.Context, attempt service.Attempt) {
+	m.Attempts = append(m.Attempts, attempt)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import "github.com/gofiber/fiber/v2"
+
+// ActivityRecorder records that an authenticated user made a request
+type ActivityRecorder interface {
+	Seen(userID string)
+}
+
+// TrackActivity reports the user of every request authenticated by
+// AuthMiddleware to recorder. Register it globally: it looks for the auth
+// payload after the route's handlers ran, so routes keep using AuthMiddleware
+// as before.
+func TrackActivity(recorder ActivityRecorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if payload := GetAuthPayload(c); payload != nil {
+			recorder.Seen(payload.UserID)
+		}
+		return err
+	}
+}
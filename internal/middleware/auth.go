@@ -13,27 +13,50 @@ const (
 	AuthorizationHeader     = "Authorization"
 	AuthorizationTypeBearer = "Bearer"
 	AuthPayloadKey          = "auth_payload"
+
+	// AccessTokenQuery carries the access token of streaming requests from
+	// browsers, whose EventSource and WebSocket APIs can't set headers
+	AccessTokenQuery = "access_token"
 )
 
 // AuthMiddleware creates an authentication middleware
 func AuthMiddleware(tokenMaker *token.PasetoMaker) fiber.Handler {
+	return authenticate(tokenMaker, false)
+}
+
+// StreamAuthMiddleware authenticates like AuthMiddleware but also accepts the
+// access token as the access_token query parameter. Use it only on SSE and
+// WebSocket routes; elsewhere tokens would end up in access logs and browser
+// history.
+func StreamAuthMiddleware(tokenMaker *token.PasetoMaker) fiber.Handler {
+	return authenticate(tokenMaker, true)
+}
+
+func authenticate(tokenMaker *token.PasetoMaker, allowQuery bool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get(AuthorizationHeader)
-		if authHeader == "" {
+		accessToken := ""
+		if allowQuery {
+			accessToken = c.Query(AccessTokenQuery)
+		}
+		if authHeader == "" && accessToken == "" {
 			return response.Unauthorized(c, "authorization header is required")
 		}
 
-		fields := strings.Fields(authHeader)
-		if len(fields) < 2 {
-			return response.Unauthorized(c, "invalid authorization header format")
-		}
+		if authHeader != "" {
+			fields := strings.Fields(authHeader)
+			if len(fields) < 2 {
+				return response.Unauthorized(c, "invalid authorization header format")
+			}
+
+			authType := fields[0]
+			if !strings.EqualFold(authType, AuthorizationTypeBearer) {
+				return response.Unauthorized(c, "unsupported authorization type")
+			}
 
-		authType := fields[0]
-		if !strings.EqualFold(authType, AuthorizationTypeBearer) {
-			return response.Unauthorized(c, "unsupported authorization type")
+			accessToken = fields[1]
 		}
 
-		accessToken := fields[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
 			if err == token.ErrExpiredToken {
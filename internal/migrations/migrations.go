@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"github.com/itsahyarr/gofiber-boilerplate/internal/audit"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/database/migration"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/email"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/loginhistory"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/scheduler"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook"
+)
+
+// All returns the migrations of every module. The API and the management CLI
+// both run this list, so register new modules here.
+func All() []migration.Migration {
+	var all []migration.Migration
+	all = append(all, user.Migrations()...)
+	all = append(all, event.Migrations()...)
+	all = append(all, webhook.Migrations()...)
+	all = append(all, scheduler.Migrations()...)
+	all = append(all, email.Migrations()...)
+	all = append(all, notification.Migrations()...)
+	all = append(all, audit.Migrations()...)
+	all = append(all, loginhistory.Migrations()...)
+	return all
+}
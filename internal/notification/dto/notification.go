@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// CreateNotificationRequest asks for a notification to be added to a user's
+// inbox. Other modules build it; it is not an API request body.
+type CreateNotificationRequest struct {
+	Type  string
+	Title string
+	Body  string
+	Data  map[string]any
+	// Key, when set, makes notifying idempotent: a notification with the same
+	// key is only created once. Use it when reacting to events.
+	Key string
+}
+
+// NotificationResponse represents a notification in API responses and in
+// the live stream
+type NotificationResponse struct {
+	ID        string         `json:"id"`
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Body      string         `json:"body,omitempty"`
+	Data      map[string]any `json:"data,omitempty"`
+	IsRead    bool           `json:"isRead"`
+	ReadAt    *time.Time     `json:"readAt,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// UnreadCountResponse carries a user's unread notification count
+type UnreadCountResponse struct {
+	Unread int64 `json:"unread"`
+}
+
+// MarkAllReadResponse reports how many notifications were marked read
+type MarkAllReadResponse struct {
+	Marked int64 `json:"marked"`
+}
+
+// ToNotificationResponse converts a notification entity to a response
+func ToNotificationResponse(notification *entity.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:        notification.ID.Hex(),
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Data:      notification.Data,
+		IsRead:    notification.IsRead(),
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+// ToNotificationResponses converts notification entities to responses
+func ToNotificationResponses(notifications []*entity.Notification) []NotificationResponse {
+	responses := make([]NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		responses[i] = ToNotificationResponse(notification)
+	}
+	return responses
+}
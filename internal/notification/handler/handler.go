@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/stream"
+)
+
+// NotificationHandler handles notification-related HTTP requests
+type NotificationHandler struct {
+	notificationService service.NotificationService
+	broker              *stream.Broker
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService service.NotificationService, broker *stream.Broker) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		broker:              broker,
+	}
+}
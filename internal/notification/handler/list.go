@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetNotifications godoc
+// @Summary      Get my notifications
+// @Description  Get the current user's notifications, newest first
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        unread query bool false "Only unread notifications"
+// @Param        page query int false "Page number" default(1)
+// @Param        per-page query int false "Items per page" default(10)
+// @Success      200 {object} response.PaginatedResponse{data=[]dto.NotificationResponse}
+// @Failure      401 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/me/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *fiber.Ctx) error {
+	payload := middleware.GetAuthPayload(c)
+	if payload == nil {
+		return response.Unauthorized(c, "authentication required")
+	}
+
+	page, perPage := pagination.ParsePage(c)
+	unreadOnly := c.QueryBool("unread", false)
+
+	notifications, total, err := h.notificationService.GetAll(c.Context(), payload.UserID, unreadOnly, page, perPage)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get notifications")
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "notifications retrieved successfully", notifications, page, perPage, total)
+}
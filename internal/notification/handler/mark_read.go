@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// MarkRead godoc
+// @Summary      Mark notification as read
+// @Description  Mark one of the current user's notifications as read
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Notification ID"
+// @Success      200 {object} response.Response{data=dto.NotificationResponse}
+// @Failure      401 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/me/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	payload := middleware.GetAuthPayload(c)
+	if payload == nil {
+		return response.Unauthorized(c, "authentication required")
+	}
+
+	notification, err := h.notificationService.MarkRead(c.Context(), payload.UserID, c.Params("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrNotificationNotFound) {
+			return response.NotFound(c, "notification not found")
+		}
+		return response.InternalServerError(c, "failed to mark notification as read")
+	}
+
+	return response.Success(c, fiber.StatusOK, "notification marked as read", notification)
+}
+
+// MarkAllRead godoc
+// @Summary      Mark all notifications as read
+// @Description  Mark every unread notification of the current user as read
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} response.Response{data=dto.MarkAllReadResponse}
+// @Failure      401 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/me/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *fiber.Ctx) error {
+	payload := middleware.GetAuthPayload(c)
+	if payload == nil {
+		return response.Unauthorized(c, "authentication required")
+	}
+
+	marked, err := h.notificationService.MarkAllRead(c.Context(), payload.UserID)
+	if err != nil {
+		return response.InternalServerError(c, "failed to mark notifications as read")
+	}
+
+	return response.Success(c, fiber.StatusOK, "notifications marked as read", dto.MarkAllReadResponse{Marked: marked})
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/stream"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// writeWait bounds a single write to a streaming client
+const writeWait = 10 * time.Second
+
+// Stream godoc
+// @Summary      Stream my notifications (SSE)
+// @Description  Server-Sent Events stream of the current user's new notifications ("notification" events) and unread count changes ("unread_count" events), starting with the current unread count. Browsers' EventSource cannot send headers, so the access token may be passed as access_token instead
+// @Tags         notifications
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        access_token query string false "Access token, for clients that cannot set the Authorization header"
+// @Success      200 {string} string "event stream"
+// @Failure      401 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/me/notifications/stream [get]
+func (h *NotificationHandler) Stream(c *fiber.Ctx) error {
+	payload := middleware.GetAuthPayload(c)
+	if payload == nil {
+		return response.Unauthorized(c, "authentication required")
+	}
+
+	// Subscribe before reading the count so no change falls in between
+	sub := h.broker.Subscribe(payload.UserID)
+	count, err := h.notificationService.UnreadCount(c.Context(), payload.UserID)
+	if err != nil {
+		sub.Close()
+		return response.InternalServerError(c, "failed to count unread notifications")
+	}
+	initial, err := encodeEvent(service.StreamUnreadCount, dto.UnreadCountResponse{Unread: count})
+	if err != nil {
+		sub.Close()
+		return response.InternalServerError(c, "failed to open notification stream")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// Keep reverse proxies such as nginx from buffering the stream
+	c.Set("X-Accel-Buffering", "no")
+
+	// The server's write timeout applies to the whole response; extend the
+	// deadline per write instead so the stream can stay open
+	conn := c.Context().Conn()
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		write := func(frame string) bool {
+			_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if _, err := w.WriteString(frame); err != nil {
+				return false
+			}
+			return w.Flush() == nil
+		}
+
+		if !write("retry: 5000\n" + sseFrame(initial)) {
+			return
+		}
+
+		heartbeat := time.NewTicker(stream.Heartbeat)
+		defer heartbeat.Stop()
+		expired := time.NewTimer(time.Until(payload.ExpiredAt))
+		defer expired.Stop()
+
+		for {
+			select {
+			case <-expired.C:
+				// Authentication only happens on connect; the client reconnects with a fresh token
+				return
+			case evt, ok := <-sub.C:
+				if !ok {
+					// Server shutting down; EventSource reconnects to another replica
+					return
+				}
+				if !write(sseFrame(evt)) {
+					return
+				}
+			case <-heartbeat.C:
+				// A comment line, ignored by EventSource; fails once the client is gone
+				if !write(": ping\n\n") {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+func encodeEvent(eventType string, data any) (stream.Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return stream.Event{}, err
+	}
+	return stream.Event{Type: eventType, Data: raw}, nil
+}
+
+// sseFrame formats an event for the SSE wire format. Encoded JSON has no raw
+// newlines, so the data fits on one line.
+func sseFrame(evt stream.Event) string {
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", evt.Type, evt.Data)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetUnreadCount godoc
+// @Summary      Get my unread notification count
+// @Description  Get how many of the current user's notifications are unread
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} response.Response{data=dto.UnreadCountResponse}
+// @Failure      401 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/me/notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *fiber.Ctx) error {
+	payload := middleware.GetAuthPayload(c)
+	if payload == nil {
+		return response.Unauthorized(c, "authentication required")
+	}
+
+	count, err := h.notificationService.UnreadCount(c.Context(), payload.UserID)
+	if err != nil {
+		return response.InternalServerError(c, "failed to count unread notifications")
+	}
+
+	return response.Success(c, fiber.StatusOK, "unread count retrieved successfully", dto.UnreadCountResponse{Unread: count})
+}
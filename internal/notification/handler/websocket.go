@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/stream"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+)
+
+// pongWait is how long a WebSocket client may stay silent, including pong
+// replies to heartbeats, before it is considered gone
+const pongWait = stream.Heartbeat + writeWait
+
+// RequireWebSocket rejects requests that are not WebSocket upgrades
+func RequireWebSocket() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return response.Error(c, fiber.StatusUpgradeRequired, "websocket upgrade required", "UPGRADE_REQUIRED", "")
+		}
+		return c.Next()
+	}
+}
+
+// WebSocket godoc
+// @Summary      Stream my notifications (WebSocket)
+// @Description  WebSocket carrying the same events as the SSE stream as JSON text messages {"type": ..., "data": ...}, starting with the current unread count. Browsers cannot set headers on WebSockets, so the access token may be passed as access_token instead
+// @Tags         notifications
+// @Security     BearerAuth
+// @Param        access_token query string false "Access token, for clients that cannot set the Authorization header"
+// @Success      101 {string} string "switching protocols"
+// @Failure      401 {object} response.Response
+// @Failure      426 {object} response.Response
+// @Router       /users/me/notifications/ws [get]
+func (h *NotificationHandler) WebSocket() fiber.Handler {
+	return websocket.New(func(conn *websocket.Conn) {
+		payload, ok := conn.Locals(middleware.AuthPayloadKey).(*token.Payload)
+		if !ok {
+			return
+		}
+
+		sub := h.broker.Subscribe(payload.UserID)
+		defer sub.Close()
+
+		// The client only sends pongs and close frames; reading them is what
+		// notices a closed connection
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+			conn.SetPongHandler(func(string) error {
+				return conn.SetReadDeadline(time.Now().Add(pongWait))
+			})
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		write := func(evt stream.Event) bool {
+			_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+			return conn.WriteJSON(evt) == nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+		count, err := h.notificationService.UnreadCount(ctx, payload.UserID)
+		cancel()
+		if err != nil {
+			return
+		}
+		initial, err := encodeEvent(service.StreamUnreadCount, dto.UnreadCountResponse{Unread: count})
+		if err != nil || !write(initial) {
+			return
+		}
+
+		heartbeat := time.NewTicker(stream.Heartbeat)
+		defer heartbeat.Stop()
+		expired := time.NewTimer(time.Until(payload.ExpiredAt))
+		defer expired.Stop()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case <-expired.C:
+				// Authentication only happens on connect; the client reconnects with a fresh token
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "access token expired"),
+					time.Now().Add(writeWait))
+				return
+			case evt, ok := <-sub.C:
+				if !ok {
+					// Server shutting down; the client reconnects to another replica
+					_ = conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+						time.Now().Add(writeWait))
+					return
+				}
+				if !write(evt) {
+					return
+				}
+			case <-heartbeat.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package notification
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/database/migration"
+)
+
+// notificationRetention is how long notifications stay in the inbox
+const notificationRetention = 90 * 24 * time.Hour
+
+// Migrations returns the notification module's database migrations
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		migration.CreateIndexes(2026101911, "notifications_indexes", "notifications",
+			mongo.IndexModel{
+				// Inbox, newest first
+				Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+			},
+			mongo.IndexModel{
+				// Unread counts and the unread filter
+				Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "readAt", Value: 1}},
+				Options: options.Index().SetName("notifications_unread"),
+			},
+			mongo.IndexModel{
+				// An event triggers a notification at most once
+				Keys:    bson.D{{Key: "key", Value: 1}},
+				Options: options.Index().SetUnique(true).SetSparse(true),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "createdAt", Value: 1}},
+				Options: options.Index().SetName("notifications_ttl").SetExpireAfterSeconds(int32(notificationRetention.Seconds())),
+			},
+		),
+	}
+}
@@ -0,0 +1,36 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// MockNotificationRepository is a mock implementation of repository.NotificationRepository
+type MockNotificationRepository struct {
+	CreateFunc      func(ctx context.Context, notification *entity.Notification) error
+	FindByUserFunc  func(ctx context.Context, userID string, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int64, error)
+	CountUnreadFunc func(ctx context.Context, userID string) (int64, error)
+	MarkReadFunc    func(ctx context.Context, userID, id string) (*entity.Notification, error)
+	MarkAllReadFunc func(ctx context.Context, userID string) (int64, error)
+}
+
+func (m *MockNotificationRepository) Create(ctx context.Context, notification *entity.Notification) error {
+	return m.CreateFunc(ctx, notification)
+}
+
+func (m *MockNotificationRepository) FindByUser(ctx context.Context, userID string, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int64, error) {
+	return m.FindByUserFunc(ctx, userID, unreadOnly, page, pageSize)
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return m.CountUnreadFunc(ctx, userID)
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, id string) (*entity.Notification, error) {
+	return m.MarkReadFunc(ctx, userID, id)
+}
+
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	return m.MarkAllReadFunc(ctx, userID)
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	// ErrNotificationExists means a notification with the same key exists
+	ErrNotificationExists = errors.New("notification already exists")
+)
+
+// NotificationRepository defines the interface for notification data access.
+// Every lookup is scoped to the owning user.
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *entity.Notification) error
+	// FindByUser returns a page of a user's notifications, newest first,
+	// optionally only the unread ones
+	FindByUser(ctx context.Context, userID string, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int64, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	// MarkRead marks a notification read; marking it again keeps the first
+	// read time
+	MarkRead(ctx context.Context, userID, id string) (*entity.Notification, error)
+	// MarkAllRead marks every unread notification read and returns how many
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+}
+
+type notificationRepositoryMongo struct {
+	collection *mongo.Collection
+}
+
+// NewNotificationRepository creates a new MongoDB notification repository
+func NewNotificationRepository(db *database.MongoDB) NotificationRepository {
+	return &notificationRepositoryMongo{
+		collection: db.Collection("notifications"),
+	}
+}
+
+func (r *notificationRepositoryMongo) Create(ctx context.Context, notification *entity.Notification) error {
+	notification.ID = bson.NewObjectID()
+	notification.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, notification)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrNotificationExists
+	}
+	return err
+}
+
+func (r *notificationRepositoryMongo) FindByUser(ctx context.Context, userID string, unreadOnly bool, page, pageSize int) ([]*entity.Notification, int64, error) {
+	objectID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return []*entity.Notification{}, 0, nil
+	}
+
+	filter := bson.M{"userId": objectID}
+	if unreadOnly {
+		filter["readAt"] = nil
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []*entity.Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+func (r *notificationRepositoryMongo) CountUnread(ctx context.Context, userID string) (int64, error) {
+	objectID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, nil
+	}
+
+	return r.collection.CountDocuments(ctx, bson.M{"userId": objectID, "readAt": nil})
+}
+
+func (r *notificationRepositoryMongo) MarkRead(ctx context.Context, userID, id string) (*entity.Notification, error) {
+	userObjectID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrNotificationNotFound
+	}
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrNotificationNotFound
+	}
+
+	filter := bson.M{"_id": objectID, "userId": userObjectID}
+	// $ifNull keeps the first read time when it is marked again
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"readAt": bson.M{"$ifNull": bson.A{"$readAt", time.Now()}}}}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var notification entity.Notification
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&notification)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, err
+	}
+
+	return &notification, nil
+}
+
+func (r *notificationRepositoryMongo) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	objectID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, nil
+	}
+
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"userId": objectID, "readAt": nil},
+		bson.M{"$set": bson.M{"readAt": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
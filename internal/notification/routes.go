@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/handler"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+)
+
+// RegisterRoutes registers the current user's notification routes
+func RegisterRoutes(router fiber.Router, h *handler.NotificationHandler, tokenMaker *token.PasetoMaker) {
+	notifications := router.Group("/users/me/notifications")
+	auth := middleware.AuthMiddleware(tokenMaker)
+
+	notifications.Get("/", auth, h.GetNotifications)
+	notifications.Get("/unread-count", auth, h.GetUnreadCount)
+	notifications.Post("/read-all", auth, h.MarkAllRead)
+	notifications.Post("/:id/read", auth, h.MarkRead)
+
+	// Live delivery; only these routes take the token from the query
+	streamAuth := middleware.StreamAuthMiddleware(tokenMaker)
+	notifications.Get("/stream", streamAuth, h.Stream)
+	notifications.Get("/ws", streamAuth, handler.RequireWebSocket(), h.WebSocket())
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// Live stream event types
+const (
+	// StreamNotification carries a new dto.NotificationResponse
+	StreamNotification = "notification"
+	// StreamUnreadCount carries a dto.UnreadCountResponse after the unread
+	// count changed
+	StreamUnreadCount = "unread_count"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrInvalidUser          = errors.New("invalid user id")
+)
+
+// Publisher pushes an event to a user's connected clients; stream.Broker
+// implements it
+type Publisher interface {
+	Publish(ctx context.Context, userID, eventType string, data any) error
+}
+
+// NotificationService defines the interface for in-app notification operations
+type NotificationService interface {
+	// Notify adds a notification to the user's inbox and pushes it to their
+	// connected clients
+	Notify(ctx context.Context, userID string, req *dto.CreateNotificationRequest) error
+	GetAll(ctx context.Context, userID string, unreadOnly bool, page, pageSize int) ([]dto.NotificationResponse, int64, error)
+	UnreadCount(ctx context.Context, userID string) (int64, error)
+	MarkRead(ctx context.Context, userID, id string) (*dto.NotificationResponse, error)
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+}
+
+type notificationServiceImpl struct {
+	notificationRepo repository.NotificationRepository
+	publisher        Publisher
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo repository.NotificationRepository, publisher Publisher) NotificationService {
+	return &notificationServiceImpl{
+		notificationRepo: notificationRepo,
+		publisher:        publisher,
+	}
+}
+
+func (s *notificationServiceImpl) Notify(ctx context.Context, userID string, req *dto.CreateNotificationRequest) error {
+	objectID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return ErrInvalidUser
+	}
+
+	notification := &entity.Notification{
+		UserID: objectID,
+		Key:    req.Key,
+		Type:   req.Type,
+		Title:  req.Title,
+		Body:   req.Body,
+		Data:   req.Data,
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		if errors.Is(err, repository.ErrNotificationExists) {
+			// Already created by an earlier delivery of the same event
+			return nil
+		}
+		logger.Error("failed to create notification", zap.Error(err), zap.String("user_id", userID))
+		return err
+	}
+
+	logger.Debug("notification created",
+		zap.String("notification_id", notification.ID.Hex()),
+		zap.String("user_id", userID),
+		zap.String("type", notification.Type),
+	)
+
+	s.publish(ctx, userID, StreamNotification, dto.ToNotificationResponse(notification))
+	s.publishUnreadCount(ctx, userID)
+	return nil
+}
+
+func (s *notificationServiceImpl) GetAll(ctx context.Context, userID string, unreadOnly bool, page, pageSize int) ([]dto.NotificationResponse, int64, error) {
+	notifications, total, err := s.notificationRepo.FindByUser(ctx, userID, unreadOnly, page, pageSize)
+	if err != nil {
+		logger.Error("failed to get notifications", zap.Error(err), zap.String("user_id", userID))
+		return nil, 0, err
+	}
+
+	return dto.ToNotificationResponses(notifications), total, nil
+}
+
+func (s *notificationServiceImpl) UnreadCount(ctx context.Context, userID string) (int64, error) {
+	count, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		logger.Error("failed to count unread notifications", zap.Error(err), zap.String("user_id", userID))
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *notificationServiceImpl) MarkRead(ctx context.Context, userID, id string) (*dto.NotificationResponse, error) {
+	notification, err := s.notificationRepo.MarkRead(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotificationNotFound) {
+			return nil, ErrNotificationNotFound
+		}
+		logger.Error("failed to mark notification read", zap.Error(err), zap.String("notification_id", id))
+		return nil, err
+	}
+
+	// Other tabs and devices of the user update their badge
+	s.publishUnreadCount(ctx, userID)
+
+	response := dto.ToNotificationResponse(notification)
+	return &response, nil
+}
+
+func (s *notificationServiceImpl) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	marked, err := s.notificationRepo.MarkAllRead(ctx, userID)
+	if err != nil {
+		logger.Error("failed to mark notifications read", zap.Error(err), zap.String("user_id", userID))
+		return 0, err
+	}
+
+	if marked > 0 {
+		s.publishUnreadCount(ctx, userID)
+	}
+	return marked, nil
+}
+
+func (s *notificationServiceImpl) publishUnreadCount(ctx context.Context, userID string) {
+	count, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		logger.Warn("failed to count unread notifications", zap.Error(err), zap.String("user_id", userID))
+		return
+	}
+	s.publish(ctx, userID, StreamUnreadCount, dto.UnreadCountResponse{Unread: count})
+}
+
+// publish is best effort: the notification is already in the inbox, and
+// clients that miss the event see it when they refetch
+func (s *notificationServiceImpl) publish(ctx context.Context, userID, eventType string, data any) {
+	if err := s.publisher.Publish(ctx, userID, eventType, data); err != nil {
+		logger.Warn("failed to publish notification event", zap.Error(err), zap.String("user_id", userID), zap.String("type", eventType))
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/notification/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+type fakePublisher struct {
+	events []string
+	err    error
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, userID, eventType string, data any) error {
+	p.events = append(p.events, eventType)
+	return p.err
+}
+
+func TestNotify_PublishesNotificationAndUnreadCount(t *testing.T) {
+	// 1. Setup Mocks: publishing fails, which must not fail Notify
+	userID := bson.NewObjectID().Hex()
+	var created *entity.Notification
+	notificationRepo := &mock.MockNotificationRepository{
+		CreateFunc: func(ctx context.Context, notification *entity.Notification) error {
+			created = notification
+			return nil
+		},
+		CountUnreadFunc: func(ctx context.Context, userID string) (int64, error) {
+			return 3, nil
+		},
+	}
+	publisher := &fakePublisher{err: errors.New("redis down")}
+
+	service := NewNotificationService(notificationRepo, publisher)
+
+	// 2. Call Method
+	err := service.Notify(context.Background(), userID, &dto.CreateNotificationRequest{
+		Type:  "user.password_changed",
+		Title: "Your password was changed",
+		Key:   "evt-1",
+	})
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, userID, created.UserID.Hex())
+	assert.Equal(t, "evt-1", created.Key)
+	assert.Equal(t, []string{StreamNotification, StreamUnreadCount}, publisher.events)
+}
+
+func TestNotify_DuplicateKeyIsIgnored(t *testing.T) {
+	// 1. Setup Mocks: an earlier delivery of the event already created it
+	notificationRepo := &mock.MockNotificationRepository{
+		CreateFunc: func(ctx context.Context, notification *entity.Notification) error {
+			return repository.ErrNotificationExists
+		},
+	}
+	publisher := &fakePublisher{}
+
+	service := NewNotificationService(notificationRepo, publisher)
+
+	// 2. Call Method
+	err := service.Notify(context.Background(), bson.NewObjectID().Hex(), &dto.CreateNotificationRequest{Key: "evt-1"})
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Empty(t, publisher.events)
+}
+
+func TestMarkRead_NotFound(t *testing.T) {
+	// 1. Setup Mocks
+	notificationRepo := &mock.MockNotificationRepository{
+		MarkReadFunc: func(ctx context.Context, userID, id string) (*entity.Notification, error) {
+			return nil, repository.ErrNotificationNotFound
+		},
+	}
+	publisher := &fakePublisher{}
+
+	service := NewNotificationService(notificationRepo, publisher)
+
+	// 2. Call Method
+	result, err := service.MarkRead(context.Background(), bson.NewObjectID().Hex(), bson.NewObjectID().Hex())
+
+	// 3. Assertions
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrNotificationNotFound)
+	assert.Empty(t, publisher.events)
+}
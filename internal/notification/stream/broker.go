@@ -0,0 +1,173 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+)
+
+const (
+	channelPrefix = "notifications:"
+
+	// bufferSize is how many events a slow client may fall behind before
+	// further events are dropped for it
+	bufferSize = 16
+
+	// Heartbeat is how often streaming handlers ping idle clients, keeping
+	// proxies from closing the connection and detecting clients that are gone
+	Heartbeat = 25 * time.Second
+)
+
+// Event is pushed to a user's connected clients
+type Event struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Subscription receives the events of one user. C is closed when the
+// subscription is closed or the broker stops.
+type Subscription struct {
+	C      <-chan Event
+	c      chan Event
+	userID string
+	broker *Broker
+	once   sync.Once
+}
+
+// Close stops delivery to the subscription
+func (s *Subscription) Close() {
+	s.broker.remove(s)
+}
+
+// Broker fans events out to the clients connected to this instance. Events
+// are published through Redis pub/sub, so an event published on one replica
+// reaches clients connected to any replica. Delivery is best effort: clients
+// that were offline or fell behind refetch their inbox.
+type Broker struct {
+	redis *database.Redis
+
+	mu          sync.RWMutex
+	subscribers map[string]map[*Subscription]struct{}
+	stopped     bool
+}
+
+// NewBroker creates a Broker; start it with Run
+func NewBroker(redis *database.Redis) *Broker {
+	return &Broker{
+		redis:       redis,
+		subscribers: map[string]map[*Subscription]struct{}{},
+	}
+}
+
+// Publish sends an event to every client of the user, on any replica
+func (b *Broker) Publish(ctx context.Context, userID, eventType string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(Event{Type: eventType, Data: raw})
+	if err != nil {
+		return err
+	}
+	return b.redis.Client.Publish(ctx, channelPrefix+userID, payload).Err()
+}
+
+// Subscribe registers a client of the user
+func (b *Broker) Subscribe(userID string) *Subscription {
+	c := make(chan Event, bufferSize)
+	sub := &Subscription{C: c, c: c, userID: userID, broker: b}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stopped {
+		sub.once.Do(func() { close(c) })
+		return sub
+	}
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = map[*Subscription]struct{}{}
+	}
+	b.subscribers[userID][sub] = struct{}{}
+	return sub
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if subs, ok := b.subscribers[sub.userID]; ok {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(b.subscribers, sub.userID)
+		}
+	}
+	sub.once.Do(func() { close(sub.c) })
+}
+
+// Run relays events from Redis to local subscribers until ctx is cancelled,
+// then closes every subscription so streaming clients are disconnected
+func (b *Broker) Run(ctx context.Context) {
+	pubsub := b.redis.Client.PSubscribe(ctx, channelPrefix+"*")
+	defer pubsub.Close()
+
+	logger.Info("notification broker started")
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			b.stop()
+			logger.Info("notification broker stopped")
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				// go-redis reconnects on its own; only a closed client ends up here
+				b.stop()
+				return
+			}
+			b.deliver(msg.Channel, msg.Payload)
+		}
+	}
+}
+
+// deliver hands a published event to the subscribers of its user
+func (b *Broker) deliver(channel, payload string) {
+	userID := strings.TrimPrefix(channel, channelPrefix)
+
+	var evt Event
+	if err := json.Unmarshal([]byte(payload), &evt); err != nil {
+		logger.Warn("invalid notification event", zap.Error(err), zap.String("channel", channel))
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers[userID] {
+		select {
+		case sub.c <- evt:
+		default:
+			logger.Debug("notification client is too slow, event dropped", zap.String("user_id", userID))
+		}
+	}
+}
+
+func (b *Broker) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stopped = true
+	for userID, subs := range b.subscribers {
+		for sub := range subs {
+			sub.once.Do(func() { close(sub.c) })
+		}
+		delete(b.subscribers, userID)
+	}
+}
@@ -0,0 +1,59 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliver_FansOutToTheUsersSubscribers(t *testing.T) {
+	// 1. Setup two clients of one user and a client of another user
+	broker := NewBroker(nil)
+	first := broker.Subscribe("user-1")
+	second := broker.Subscribe("user-1")
+	other := broker.Subscribe("user-2")
+
+	// 2. Call Method
+	broker.deliver(channelPrefix+"user-1", `{"type":"notification","data":{"title":"Hi"}}`)
+
+	// 3. Assertions
+	for _, sub := range []*Subscription{first, second} {
+		select {
+		case evt := <-sub.C:
+			assert.Equal(t, "notification", evt.Type)
+			assert.JSONEq(t, `{"title":"Hi"}`, string(evt.Data))
+		default:
+			t.Fatal("expected an event")
+		}
+	}
+	assert.Empty(t, other.C)
+}
+
+func TestDeliver_DropsEventsForSlowClients(t *testing.T) {
+	broker := NewBroker(nil)
+	sub := broker.Subscribe("user-1")
+
+	for range bufferSize + 5 {
+		broker.deliver(channelPrefix+"user-1", `{"type":"notification","data":{}}`)
+	}
+
+	assert.Len(t, sub.C, bufferSize)
+}
+
+func TestClose_StopsDeliveryAndClosesChannel(t *testing.T) {
+	broker := NewBroker(nil)
+	sub := broker.Subscribe("user-1")
+
+	sub.Close()
+	broker.deliver(channelPrefix+"user-1", `{"type":"notification","data":{}}`)
+	broker.stop()
+	sub.Close()
+
+	_, open := <-sub.C
+	assert.False(t, open)
+
+	late := broker.Subscribe("user-1")
+	_, open = <-late.C
+	assert.False(t, open)
+	late.Close()
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// TaskResponse represents a registered periodic task
+type TaskResponse struct {
+	Name        string       `json:"name"`
+	Schedule    string       `json:"schedule"`
+	Description string       `json:"description,omitempty"`
+	Timeout     string       `json:"timeout"`
+	Running     bool         `json:"running"`
+	NextRunAt   *time.Time   `json:"nextRunAt,omitempty"`
+	LastRun     *RunResponse `json:"lastRun,omitempty"`
+}
+
+// RunResponse represents one run of a task in the run history
+type RunResponse struct {
+	ID          string               `json:"id"`
+	Task        string               `json:"task"`
+	Trigger     entity.TaskTrigger   `json:"trigger"`
+	TriggeredBy string               `json:"triggeredBy,omitempty"`
+	ScheduledAt *time.Time           `json:"scheduledAt,omitempty"`
+	Status      entity.TaskRunStatus `json:"status"`
+	Error       string               `json:"error,omitempty"`
+	Instance    string               `json:"instance"`
+	StartedAt   time.Time            `json:"startedAt"`
+	FinishedAt  *time.Time           `json:"finishedAt,omitempty"`
+	// Duration is set once the run has finished
+	Duration string `json:"duration,omitempty"`
+}
+
+// ToRunResponse converts a task run entity to a response
+func ToRunResponse(run *entity.TaskRun) RunResponse {
+	response := RunResponse{
+		ID:          run.ID.Hex(),
+		Task:        run.Task,
+		Trigger:     run.Trigger,
+		TriggeredBy: run.TriggeredBy,
+		ScheduledAt: run.ScheduledAt,
+		Status:      run.Status,
+		Error:       run.Error,
+		Instance:    run.Instance,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+	}
+	if run.FinishedAt != nil {
+		response.Duration = run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond).String()
+	}
+	return response
+}
+
+// ToRunResponses converts task run entities to responses
+func ToRunResponses(runs []*entity.TaskRun) []RunResponse {
+	responses := make([]RunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = ToRunResponse(run)
+	}
+	return responses
+}
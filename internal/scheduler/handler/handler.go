@@ -0,0 +1,15 @@
+package handler
+
+import "github.com/itsahyarr/gofiber-boilerplate/internal/scheduler/service"
+
+// SchedulerHandler handles scheduled task HTTP requests
+type SchedulerHandler struct {
+	scheduler service.Scheduler
+}
+
+// NewSchedulerHandler creates a new scheduler handler
+func NewSchedulerHandler(scheduler service.Scheduler) *SchedulerHandler {
+	return &SchedulerHandler{
+		scheduler: scheduler,
+	}
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/scheduler/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetTaskRuns godoc
+// @Summary      Get task run history
+// @Description  Get a scheduled task's runs, newest first (ADMIN only)
+// @Tags         scheduler
+// @Produce      json
+// @Security     BearerAuth
+// @Param        name path string true "Task name"
+// @Param        page query int false "Page number" default(1)
+// @Param        per-page query int false "Items per page" default(10)
+// @Success      200 {object} response.PaginatedResponse{data=[]dto.RunResponse}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /scheduler/tasks/{name}/runs [get]
+func (h *SchedulerHandler) GetTaskRuns(c *fiber.Ctx) error {
+	page, perPage := pagination.ParsePage(c)
+
+	runs, total, err := h.scheduler.GetRuns(c.Context(), c.Params("name"), page, perPage)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			return response.NotFound(c, "scheduled task not found")
+		}
+		return response.InternalServerError(c, "failed to get task runs")
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "task runs retrieved successfully", runs, page, perPage, total)
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetTasks godoc
+// @Summary      Get scheduled tasks
+// @Description  List the registered periodic tasks with their schedule, next run and last run (ADMIN only)
+// @Tags         scheduler
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} response.Response{data=[]dto.TaskResponse}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /scheduler/tasks [get]
+func (h *SchedulerHandler) GetTasks(c *fiber.Ctx) error {
+	tasks, err := h.scheduler.GetTasks(c.Context())
+	if err != nil {
+		return response.InternalServerError(c, "failed to get scheduled tasks")
+	}
+
+	return response.Success(c, fiber.StatusOK, "scheduled tasks retrieved successfully", tasks)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/scheduler/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// TriggerTask godoc
+// @Summary      Trigger scheduled task
+// @Description  Run a scheduled task now, outside its schedule; poll its run history for the outcome (ADMIN only)
+// @Tags         scheduler
+// @Produce      json
+// @Security     BearerAuth
+// @Param        name path string true "Task name"
+// @Success      202 {object} response.Response{data=dto.RunResponse}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      409 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /scheduler/tasks/{name}/trigger [post]
+func (h *SchedulerHandler) TriggerTask(c *fiber.Ctx) error {
+	payload := middleware.GetAuthPayload(c)
+
+	run, err := h.scheduler.Trigger(c.Context(), c.Params("name"), payload.UserID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			return response.NotFound(c, "scheduled task not found")
+		}
+		if errors.Is(err, service.ErrTaskRunning) {
+			return response.Conflict(c, "scheduled task is already running", "wait for the current run to finish")
+		}
+		return response.InternalServerError(c, "failed to trigger scheduled task")
+	}
+
+	return response.Success(c, fiber.StatusAccepted, "scheduled task triggered", run)
+}
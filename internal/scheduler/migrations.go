@@ -0,0 +1,30 @@
+package scheduler
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/database/migration"
+)
+
+// runRetention is how long the task run history is kept
+const runRetention = 30 * 24 * time.Hour
+
+// Migrations returns the scheduler module's database migrations
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		migration.CreateIndexes(2026101909, "scheduler_runs_indexes", "scheduler_runs",
+			mongo.IndexModel{
+				// Run history and latest run per task
+				Keys: bson.D{{Key: "task", Value: 1}, {Key: "startedAt", Value: -1}, {Key: "_id", Value: -1}},
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "startedAt", Value: 1}},
+				Options: options.Index().SetName("scheduler_runs_ttl").SetExpireAfterSeconds(int32(runRetention.Seconds())),
+			},
+		),
+	}
+}
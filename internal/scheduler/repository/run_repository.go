@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// runsCollection stores the task run history
+const runsCollection = "scheduler_runs"
+
+// RunRepository defines the interface for task run history data access
+type RunRepository interface {
+	Create(ctx context.Context, run *entity.TaskRun) error
+	// Finish stores the outcome of a run
+	Finish(ctx context.Context, run *entity.TaskRun) error
+	// FindByTask returns a page of a task's runs, newest first
+	FindByTask(ctx context.Context, task string, page, pageSize int) ([]*entity.TaskRun, int64, error)
+	// FindLatest returns a task's most recent run, or nil if it never ran
+	FindLatest(ctx context.Context, task string) (*entity.TaskRun, error)
+}
+
+type runRepositoryMongo struct {
+	collection *mongo.Collection
+}
+
+// NewRunRepository creates a new MongoDB task run repository
+func NewRunRepository(db *database.MongoDB) RunRepository {
+	return &runRepositoryMongo{
+		collection: db.Collection(runsCollection),
+	}
+}
+
+func (r *runRepositoryMongo) Create(ctx context.Context, run *entity.TaskRun) error {
+	run.ID = bson.NewObjectID()
+	_, err := r.collection.InsertOne(ctx, run)
+	return err
+}
+
+func (r *runRepositoryMongo) Finish(ctx context.Context, run *entity.TaskRun) error {
+	update := bson.M{"$set": bson.M{
+		"status":     run.Status,
+		"error":      run.Error,
+		"finishedAt": run.FinishedAt,
+	}}
+	_, err := r.collection.UpdateByID(ctx, run.ID, update)
+	return err
+}
+
+func (r *runRepositoryMongo) FindByTask(ctx context.Context, task string, page, pageSize int) ([]*entity.TaskRun, int64, error) {
+	filter := bson.M{"task": task}
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "startedAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var runs []*entity.TaskRun
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
+
+func (r *runRepositoryMongo) FindLatest(ctx context.Context, task string) (*entity.TaskRun, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "startedAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	var run entity.TaskRun
+	err := r.collection.FindOne(ctx, bson.M{"task": task}, opts).Decode(&run)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &run, nil
+}
@@ -0,0 +1,18 @@
+package scheduler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/scheduler/handler"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+)
+
+// RegisterRoutes registers the scheduled task routes (ADMIN only)
+func RegisterRoutes(router fiber.Router, h *handler.SchedulerHandler, tokenMaker *token.PasetoMaker) {
+	tasks := router.Group("/scheduler/tasks", middleware.AuthMiddleware(tokenMaker), middleware.RequireAdmin())
+
+	tasks.Get("/", h.GetTasks)
+	tasks.Get("/:name/runs", h.GetTaskRuns)
+	tasks.Post("/:name/trigger", h.TriggerTask)
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/scheduler/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/scheduler/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/cron"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+const (
+	leaderKey     = "scheduler:leader"
+	tickKeyPrefix = "scheduler:tick:"
+	lockKeyPrefix = "scheduler:running:"
+
+	// leaderLease is how long leadership outlives a leader that stopped
+	// renewing it, e.g. because it crashed
+	leaderLease  = 15 * time.Second
+	pollInterval = time.Second
+
+	defaultTimeout = 10 * time.Minute
+	// lockMargin keeps a run's lock a little past its timeout
+	lockMargin = time.Minute
+	// tickRetention is how long the last fired tick of a task is remembered
+	tickRetention = 30 * 24 * time.Hour
+)
+
+var (
+	ErrTaskNotFound = errors.New("scheduled task not found")
+	ErrTaskRunning  = errors.New("scheduled task is already running")
+	ErrTaskExists   = errors.New("scheduled task already registered")
+)
+
+// Task is a periodic job registered by a module
+type Task struct {
+	// Name identifies the task across replicas, e.g. "user.purge_deleted"
+	Name string
+	// Schedule is a cron expression, see cron.Parse
+	Schedule    string
+	Description string
+	// Timeout bounds a run; defaults to 10 minutes
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Scheduler runs registered tasks on their cron schedules. Every replica runs
+// a Scheduler, but only the elected leader fires ticks, and each tick of a
+// task is claimed in Redis so it runs exactly once even during a leader
+// change. A task never runs twice at the same time.
+type Scheduler interface {
+	// Register adds a task; register every task before calling Run
+	Register(task Task) error
+	// Run fires due tasks until ctx is cancelled, then waits for the runs
+	// this process started
+	Run(ctx context.Context)
+	// Trigger runs a task now, in the background, on this replica
+	Trigger(ctx context.Context, name, triggeredBy string) (*dto.RunResponse, error)
+	GetTasks(ctx context.Context) ([]dto.TaskResponse, error)
+	GetRuns(ctx context.Context, name string, page, pageSize int) ([]dto.RunResponse, int64, error)
+}
+
+type registeredTask struct {
+	Task
+	schedule cron.Schedule
+}
+
+type schedulerImpl struct {
+	redis    *database.Redis
+	runRepo  repository.RunRepository
+	instance string
+	tasks    []*registeredTask
+	byName   map[string]*registeredTask
+	running  sync.WaitGroup
+}
+
+// NewScheduler creates a new scheduler
+func NewScheduler(redis *database.Redis, runRepo repository.RunRepository) Scheduler {
+	return &schedulerImpl{
+		redis:    redis,
+		runRepo:  runRepo,
+		instance: utils.NewInstanceID(),
+		byName:   map[string]*registeredTask{},
+	}
+}
+
+func (s *schedulerImpl) Register(task Task) error {
+	if task.Name == "" || task.Run == nil {
+		return fmt.Errorf("scheduled task needs a name and a Run function")
+	}
+	if _, ok := s.byName[task.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrTaskExists, task.Name)
+	}
+
+	schedule, err := cron.Parse(task.Schedule)
+	if err != nil {
+		return fmt.Errorf("task %s: %w", task.Name, err)
+	}
+	if task.Timeout <= 0 {
+		task.Timeout = defaultTimeout
+	}
+
+	registered := &registeredTask{Task: task, schedule: schedule}
+	s.tasks = append(s.tasks, registered)
+	s.byName[task.Name] = registered
+	return nil
+}
+
+func (s *schedulerImpl) Run(ctx context.Context) {
+	logger.Info("starting scheduler", zap.String("instance", s.instance), zap.Int("tasks", len(s.tasks)))
+
+	next := make(map[string]time.Time, len(s.tasks))
+	now := time.Now()
+	for _, task := range s.tasks {
+		next[task.Name] = task.schedule.Next(now)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	leading := false
+	for {
+		select {
+		case <-ctx.Done():
+			if leading {
+				s.resign()
+			}
+			s.running.Wait()
+			logger.Info("scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+
+		isLeader := s.elect(ctx)
+		if isLeader != leading {
+			leading = isLeader
+			logger.Info("scheduler leadership changed", zap.String("instance", s.instance), zap.Bool("leader", leading))
+		}
+
+		// Followers advance their schedules too, so a new leader only fires
+		// ticks that are due from then on
+		now := time.Now()
+		for _, task := range s.tasks {
+			tick := next[task.Name]
+			if tick.IsZero() || now.Before(tick) {
+				continue
+			}
+			next[task.Name] = task.schedule.Next(now)
+			if leading {
+				s.fire(ctx, task, tick)
+			}
+		}
+	}
+}
+
+// electScript takes the leader lease if it is free and renews it if the
+// caller holds it
+var electScript = redis.NewScript(`
+local owner = redis.call('GET', KEYS[1])
+if owner == false then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+if owner == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// elect reports whether this instance holds the leader lease
+func (s *schedulerImpl) elect(ctx context.Context) bool {
+	leader, err := electScript.Run(ctx, s.redis.Client, []string{leaderKey}, s.instance, leaderLease.Milliseconds()).Int()
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("scheduler leader election failed", zap.Error(err))
+		}
+		return false
+	}
+	return leader == 1
+}
+
+// releaseScript deletes a key if the caller still owns it
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// resign hands leadership to another replica right away
+func (s *schedulerImpl) resign() {
+	if err := releaseScript.Run(context.Background(), s.redis.Client, []string{leaderKey}, s.instance).Err(); err != nil {
+		logger.Error("failed to resign scheduler leadership", zap.Error(err))
+	}
+}
+
+// claimTickScript records tick as the last fired tick of a task unless it
+// (or a later one) already fired
+var claimTickScript = redis.NewScript(`
+local last = tonumber(redis.call('GET', KEYS[1]) or '0')
+if tonumber(ARGV[1]) <= last then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+// fire starts a scheduled run of task for tick unless another replica
+// already did
+func (s *schedulerImpl) fire(ctx context.Context, task *registeredTask, tick time.Time) {
+	claimed, err := claimTickScript.Run(ctx, s.redis.Client,
+		[]string{tickKeyPrefix + task.Name}, tick.UnixMilli(), tickRetention.Milliseconds(),
+	).Int()
+	if err != nil {
+		logger.Error("failed to claim scheduled task tick", zap.Error(err), zap.String("task", task.Name))
+		return
+	}
+	if claimed == 0 {
+		return
+	}
+
+	if _, err := s.start(ctx, task, entity.TaskTriggerSchedule, "", &tick); err != nil {
+		if errors.Is(err, ErrTaskRunning) {
+			logger.Warn("scheduled task skipped, previous run still running", zap.String("task", task.Name))
+			return
+		}
+		logger.Error("failed to start scheduled task", zap.Error(err), zap.String("task", task.Name))
+	}
+}
+
+func (s *schedulerImpl) Trigger(ctx context.Context, name, triggeredBy string) (*dto.RunResponse, error) {
+	task, ok := s.byName[name]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	run, err := s.start(ctx, task, entity.TaskTriggerManual, triggeredBy, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("scheduled task triggered", zap.String("task", name), zap.String("triggered_by", triggeredBy))
+
+	response := dto.ToRunResponse(run)
+	return &response, nil
+}
+
+// start takes the task's run lock, records the run and executes it in the
+// background
+func (s *schedulerImpl) start(ctx context.Context, task *registeredTask, trigger entity.TaskTrigger, triggeredBy string, scheduledAt *time.Time) (*entity.TaskRun, error) {
+	lockKey := lockKeyPrefix + task.Name
+	lockToken := bson.NewObjectID().Hex()
+	acquired, err := s.redis.Client.SetNX(ctx, lockKey, lockToken, task.Timeout+lockMargin).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrTaskRunning
+	}
+
+	run := &entity.TaskRun{
+		Task:        task.Name,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		ScheduledAt: scheduledAt,
+		Status:      entity.TaskRunRunning,
+		Instance:    s.instance,
+		StartedAt:   time.Now(),
+	}
+	if err := s.runRepo.Create(ctx, run); err != nil {
+		s.release(lockKey, lockToken)
+		return nil, err
+	}
+
+	// The run is updated in the background; callers get it as started
+	started := *run
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		defer s.release(lockKey, lockToken)
+		s.execute(task, run)
+	}()
+
+	return &started, nil
+}
+
+// execute runs a task and records the outcome
+func (s *schedulerImpl) execute(task *registeredTask, run *entity.TaskRun) {
+	ctx, cancel := context.WithTimeout(context.Background(), task.Timeout)
+	defer cancel()
+
+	err := runTask(ctx, task)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	fields := []zap.Field{
+		zap.String("task", task.Name),
+		zap.String("run_id", run.ID.Hex()),
+		zap.String("trigger", string(run.Trigger)),
+		zap.Duration("duration", finishedAt.Sub(run.StartedAt)),
+	}
+	if err != nil {
+		run.Status = entity.TaskRunFailed
+		run.Error = err.Error()
+		logger.Error("scheduled task failed", append(fields, zap.Error(err))...)
+	} else {
+		run.Status = entity.TaskRunSucceeded
+		logger.Info("scheduled task completed", fields...)
+	}
+
+	if err := s.runRepo.Finish(context.Background(), run); err != nil {
+		logger.Error("failed to record scheduled task run", append(fields, zap.Error(err))...)
+	}
+}
+
+// runTask runs a task, turning panics into errors
+func runTask(ctx context.Context, task *registeredTask) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+	return task.Run(ctx)
+}
+
+// release frees a run lock unless it expired and was taken by another run
+func (s *schedulerImpl) release(key, token string) {
+	if err := releaseScript.Run(context.Background(), s.redis.Client, []string{key}, token).Err(); err != nil {
+		logger.Error("failed to release scheduled task lock", zap.Error(err), zap.String("key", key))
+	}
+}
+
+func (s *schedulerImpl) GetTasks(ctx context.Context) ([]dto.TaskResponse, error) {
+	now := time.Now()
+	responses := make([]dto.TaskResponse, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		response := dto.TaskResponse{
+			Name:        task.Name,
+			Schedule:    task.Schedule,
+			Description: task.Description,
+			Timeout:     task.Timeout.String(),
+		}
+		if next := task.schedule.Next(now); !next.IsZero() {
+			response.NextRunAt = &next
+		}
+
+		running, err := s.redis.Client.Exists(ctx, lockKeyPrefix+task.Name).Result()
+		if err != nil {
+			logger.Error("failed to get scheduled task state", zap.Error(err), zap.String("task", task.Name))
+			return nil, err
+		}
+		response.Running = running > 0
+
+		last, err := s.runRepo.FindLatest(ctx, task.Name)
+		if err != nil {
+			logger.Error("failed to get last task run", zap.Error(err), zap.String("task", task.Name))
+			return nil, err
+		}
+		if last != nil {
+			lastRun := dto.ToRunResponse(last)
+			response.LastRun = &lastRun
+		}
+
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (s *schedulerImpl) GetRuns(ctx context.Context, name string, page, pageSize int) ([]dto.RunResponse, int64, error) {
+	if _, ok := s.byName[name]; !ok {
+		return nil, 0, ErrTaskNotFound
+	}
+
+	runs, total, err := s.runRepo.FindByTask(ctx, name, page, pageSize)
+	if err != nil {
+		logger.Error("failed to get task runs", zap.Error(err), zap.String("task", name))
+		return nil, 0, err
+	}
+
+	return dto.ToRunResponses(runs), total, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/cron"
+)
+
+func noop(ctx context.Context) error { return nil }
+
+func TestRegister(t *testing.T) {
+	// 1. Setup
+	scheduler := NewScheduler(nil, nil)
+
+	// 2. Call Method
+	err := scheduler.Register(Task{Name: "user.purge_deleted", Schedule: "@hourly", Run: noop})
+	duplicateErr := scheduler.Register(Task{Name: "user.purge_deleted", Schedule: "@daily", Run: noop})
+	invalidErr := scheduler.Register(Task{Name: "bad", Schedule: "every hour", Run: noop})
+	missingRunErr := scheduler.Register(Task{Name: "no_run", Schedule: "@hourly"})
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.ErrorIs(t, duplicateErr, ErrTaskExists)
+	assert.ErrorIs(t, invalidErr, cron.ErrInvalidSchedule)
+	assert.Error(t, missingRunErr)
+
+	impl := scheduler.(*schedulerImpl)
+	assert.Len(t, impl.tasks, 1)
+	assert.Equal(t, defaultTimeout, impl.tasks[0].Timeout)
+}
+
+func TestTrigger_UnknownTask(t *testing.T) {
+	scheduler := NewScheduler(nil, nil)
+
+	_, err := scheduler.Trigger(context.Background(), "missing", "admin")
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+
+	_, _, err = scheduler.GetRuns(context.Background(), "missing", 1, 10)
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+}
+
+func TestRunTask_RecoversPanics(t *testing.T) {
+	task := &registeredTask{Task: Task{Name: "panics", Timeout: time.Second, Run: func(ctx context.Context) error {
+		panic("nil map")
+	}}}
+
+	err := runTask(context.Background(), task)
+	assert.ErrorContains(t, err, "nil map")
+}
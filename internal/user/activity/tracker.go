@@ -0,0 +1,97 @@
+package activity
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+)
+
+// queueSize bounds the writes waiting for the background writer
+const queueSize = 1024
+
+// sighting is a user seen at a point in time
+type sighting struct {
+	userID string
+	at     time.Time
+}
+
+// Tracker keeps users' lastSeenAt up to date. Seen is called on every
+// authenticated request, so it never blocks or touches the database: a user
+// is queued at most once per interval and written by Run in the background.
+// The repository skips users another replica wrote within the interval.
+type Tracker struct {
+	userRepo repository.UserRepository
+	interval time.Duration
+
+	mu      sync.Mutex
+	queued  map[string]time.Time
+	pending chan sighting
+}
+
+// NewTracker creates a tracker writing each user's lastSeenAt at most once
+// per interval
+func NewTracker(userRepo repository.UserRepository, interval time.Duration) *Tracker {
+	return &Tracker{
+		userRepo: userRepo,
+		interval: interval,
+		queued:   make(map[string]time.Time),
+		pending:  make(chan sighting, queueSize),
+	}
+}
+
+// Seen records that the user made a request
+func (t *Tracker) Seen(userID string) {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.queued[userID]; ok && now.Sub(last) < t.interval {
+		return
+	}
+
+	select {
+	case t.pending <- sighting{userID: userID, at: now}:
+		t.queued[userID] = now
+	default:
+		// The writer is behind; a later request queues the user again
+	}
+}
+
+// Run writes queued sightings until ctx is cancelled. Sightings still queued
+// then are dropped; lastSeenAt is approximate anyway.
+func (t *Tracker) Run(ctx context.Context) {
+	prune := time.NewTicker(t.interval)
+	defer prune.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s := <-t.pending:
+			if err := t.userRepo.TouchLastSeen(ctx, s.userID, s.at, t.interval); err != nil && ctx.Err() == nil {
+				logger.Warn("failed to update last seen", zap.Error(err), zap.String("user_id", s.userID))
+			}
+		case now := <-prune.C:
+			t.prune(now)
+		}
+	}
+}
+
+// prune forgets users whose interval has passed, so the map only holds
+// recently active users
+func (t *Tracker) prune(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for userID, at := range t.queued {
+		if now.Sub(at) >= t.interval {
+			delete(t.queued, userID)
+		}
+	}
+}
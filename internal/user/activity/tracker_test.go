@@ -0,0 +1,64 @@
+package activity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository/mock"
+)
+
+func TestSeen_QueuesUserOncePerInterval(t *testing.T) {
+	// 1. Setup Mocks
+	tracker := NewTracker(&mock.MockUserRepository{}, time.Hour)
+
+	// 2. Call Method
+	tracker.Seen("user-1")
+	tracker.Seen("user-1")
+	tracker.Seen("user-2")
+
+	// 3. Assertions
+	assert.Len(t, tracker.pending, 2)
+
+	// 4. Once the interval passed the user is queued again
+	tracker.prune(time.Now().Add(time.Hour))
+	assert.Empty(t, tracker.queued)
+	tracker.Seen("user-1")
+	assert.Len(t, tracker.pending, 3)
+}
+
+func TestRun_WritesQueuedUsers(t *testing.T) {
+	// 1. Setup Mocks
+	written := make(chan string, 1)
+	userRepo := &mock.MockUserRepository{
+		TouchLastSeenFunc: func(ctx context.Context, id string, at time.Time, interval time.Duration) error {
+			assert.Equal(t, time.Minute, interval)
+			written <- id
+			return nil
+		},
+	}
+	tracker := NewTracker(userRepo, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tracker.Run(ctx)
+	}()
+
+	// 2. Call Method
+	tracker.Seen("user-1")
+
+	// 3. Assertions
+	select {
+	case id := <-written:
+		assert.Equal(t, "user-1", id)
+	case <-time.After(time.Second):
+		t.Fatal("last seen was not written")
+	}
+
+	cancel()
+	<-done
+}
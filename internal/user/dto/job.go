@@ -0,0 +1,57 @@
+package dto
+
+import "github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+
+// Background job statuses (imports and exports)
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+// ImportUserRow is a single user record in a CSV or NDJSON import file.
+// Passwords are temporary and must be changed on first login.
+type ImportUserRow struct {
+	Email     string      `json:"email" validate:"required,email"`
+	Password  string      `json:"password" validate:"required,min=8"`
+	FirstName string      `json:"firstName" validate:"required,min=2"`
+	LastName  string      `json:"lastName" validate:"required,min=2"`
+	Role      entity.Role `json:"role,omitempty" validate:"omitempty,oneof=ADMIN USER"`
+}
+
+// ImportRowError lists the problems found in one row of an import file
+type ImportRowError struct {
+	Line   int      `json:"line"`
+	Email  string   `json:"email,omitempty"`
+	Errors []string `json:"errors"`
+}
+
+// ImportJob reports the progress and outcome of a user import
+type ImportJob struct {
+	ID         string           `json:"id"`
+	Status     string           `json:"status"`
+	Format     string           `json:"format"`
+	DryRun     bool             `json:"dryRun"`
+	Total      int              `json:"total"`
+	Processed  int              `json:"processed"`
+	Succeeded  int              `json:"succeeded"`
+	Failed     int              `json:"failed"`
+	Errors     []ImportRowError `json:"errors,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	CreatedAt  string           `json:"createdAt"`
+	FinishedAt string           `json:"finishedAt,omitempty"`
+}
+
+// ExportJob reports the progress and outcome of an asynchronous user export
+type ExportJob struct {
+	ID         string `json:"id"`
+	Status     string `json:"status"`
+	Format     string `json:"format"`
+	FileName   string `json:"fileName"`
+	Rows       int    `json:"rows"`
+	Size       int64  `json:"size"`
+	Error      string `json:"error,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+	FinishedAt string `json:"finishedAt,omitempty"`
+}
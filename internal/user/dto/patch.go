@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+const (
+	// ContentTypeMergePatch is the media type of an RFC 7386 JSON Merge Patch
+	ContentTypeMergePatch = "application/merge-patch+json"
+	// ContentTypeJSONPatch is the media type of an RFC 6902 JSON Patch
+	ContentTypeJSONPatch = "application/json-patch+json"
+)
+
+var (
+	ErrUnsupportedPatchType = errors.New("unsupported patch content type")
+	ErrInvalidPatch         = errors.New("invalid patch document")
+)
+
+// nonNullable are the patchable fields that have no empty value, so a patch
+// may change them but not remove them or set them to null
+var nonNullable = []string{"firstName", "role", "isActive"}
+
+// PatchableUser is the document PATCH requests are applied to. Only these
+// fields can be patched; patches touching anything else are rejected.
+type PatchableUser struct {
+	FirstName string      `json:"firstName" validate:"required,min=2"`
+	LastName  string      `json:"lastName" validate:"omitempty,min=2"`
+	Role      entity.Role `json:"role" validate:"required,oneof=ADMIN USER"`
+	Team      string      `json:"team" validate:"omitempty,max=64"`
+	IsActive  bool        `json:"isActive"`
+}
+
+// ToPatchableUser extracts the patchable fields from a user response
+func ToPatchableUser(user *UserResponse) PatchableUser {
+	return PatchableUser{
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Role:      user.Role,
+		Team:      user.Team,
+		IsActive:  user.IsActive,
+	}
+}
+
+// ApplyUserPatch applies a merge patch or JSON patch, selected by contentType,
+// to the given document and returns the patched result
+func ApplyUserPatch(contentType string, doc PatchableUser, patch []byte) (*PatchableUser, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrUnsupportedPatchType
+	}
+
+	original, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch mediaType {
+	case ContentTypeMergePatch:
+		patched, err = jsonpatch.MergePatch(original, patch)
+	case ContentTypeJSONPatch:
+		var ops jsonpatch.Patch
+		ops, err = jsonpatch.DecodePatch(patch)
+		if err == nil {
+			patched, err = ops.Apply(original)
+		}
+	default:
+		return nil, ErrUnsupportedPatchType
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	// Removed and null fields would otherwise decode to their zero value,
+	// e.g. {"isActive": null} deactivating the user
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patched, &fields); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	for _, name := range nonNullable {
+		if raw, ok := fields[name]; !ok || string(raw) == "null" {
+			return nil, fmt.Errorf("%w: %s cannot be null or removed", ErrInvalidPatch, name)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+
+	var result PatchableUser
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	return &result, nil
+}
+
+// Changes returns an update request containing only the fields that differ
+// from the original document
+func (p *PatchableUser) Changes(original PatchableUser) *UpdateUserRequest {
+	req := &UpdateUserRequest{}
+	if p.FirstName != original.FirstName {
+		req.FirstName = &p.FirstName
+	}
+	if p.LastName != original.LastName {
+		req.LastName = &p.LastName
+	}
+	if p.Role != original.Role {
+		req.Role = &p.Role
+	}
+	if p.Team != original.Team {
+		req.Team = &p.Team
+	}
+	if p.IsActive != original.IsActive {
+		req.IsActive = &p.IsActive
+	}
+	return req
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+func testPatchableUser() PatchableUser {
+	return PatchableUser{
+		FirstName: "John",
+		LastName:  "Doe",
+		Role:      entity.RoleUser,
+		IsActive:  true,
+	}
+}
+
+func TestApplyUserPatch_MergePatchClearsField(t *testing.T) {
+	original := testPatchableUser()
+
+	patched, err := ApplyUserPatch(ContentTypeMergePatch, original, []byte(`{"lastName": null, "firstName": "Jane"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane", patched.FirstName)
+	assert.Equal(t, "", patched.LastName)
+
+	changes := patched.Changes(original)
+	assert.NotNil(t, changes.FirstName)
+	assert.NotNil(t, changes.LastName)
+	assert.Nil(t, changes.Role)
+	assert.Nil(t, changes.IsActive)
+}
+
+func TestApplyUserPatch_JSONPatch(t *testing.T) {
+	patch := []byte(`[
+		{"op": "test", "path": "/isActive", "value": true},
+		{"op": "replace", "path": "/isActive", "value": false}
+	]`)
+
+	patched, err := ApplyUserPatch(ContentTypeJSONPatch+"; charset=utf-8", testPatchableUser(), patch)
+
+	assert.NoError(t, err)
+	assert.False(t, patched.IsActive)
+}
+
+func TestApplyUserPatch_RejectsNullNonNullableField(t *testing.T) {
+	_, err := ApplyUserPatch(ContentTypeMergePatch, testPatchableUser(), []byte(`{"isActive": null}`))
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+
+	_, err = ApplyUserPatch(ContentTypeJSONPatch, testPatchableUser(), []byte(`[{"op": "replace", "path": "/isActive", "value": null}]`))
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+
+	_, err = ApplyUserPatch(ContentTypeJSONPatch, testPatchableUser(), []byte(`[{"op": "remove", "path": "/role"}]`))
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+}
+
+func TestApplyUserPatch_RejectsUnknownFields(t *testing.T) {
+	_, err := ApplyUserPatch(ContentTypeMergePatch, testPatchableUser(), []byte(`{"email": "x@example.com"}`))
+
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+}
+
+func TestApplyUserPatch_UnsupportedType(t *testing.T) {
+	_, err := ApplyUserPatch("application/json", testPatchableUser(), []byte(`{}`))
+
+	assert.ErrorIs(t, err, ErrUnsupportedPatchType)
+}
@@ -1,33 +1,87 @@
 package dto
 
 import (
+	"strings"
+	"time"
+
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
 	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
 )
 
 // UserResponse represents the user response
 type UserResponse struct {
-	ID        string      `json:"id"`
-	Email     string      `json:"email"`
-	FirstName string      `json:"firstName"`
-	LastName  string      `json:"lastName"`
-	Role      entity.Role `json:"role"`
-	IsActive  bool        `json:"isActive"`
-	CreatedAt string      `json:"createdAt"`
-	UpdatedAt string      `json:"updatedAt"`
+	ID                 string      `json:"id"`
+	Email              string      `json:"email"`
+	FirstName          string      `json:"firstName"`
+	LastName           string      `json:"lastName"`
+	Role               entity.Role `json:"role"`
+	Team               string      `json:"team,omitempty"`
+	IsActive           bool        `json:"isActive"`
+	MustChangePassword bool        `json:"mustChangePassword"`
+	Version            int64       `json:"version"`
+	CreatedAt          string      `json:"createdAt"`
+	UpdatedAt          string      `json:"updatedAt"`
+	// Activity is only shown to admins
+	Activity *UserActivityResponse `json:"activity,omitempty"`
+	// Stats is only embedded with include=stats
+	Stats *UserStatsResponse `json:"stats,omitempty"`
+}
+
+// UserActivityResponse tells when a user last logged in and was last seen.
+// Times are null for users who never did.
+type UserActivityResponse struct {
+	LastLoginAt *string `json:"lastLoginAt"`
+	LastSeenAt  *string `json:"lastSeenAt"`
+	LoginCount  int64   `json:"loginCount"`
+}
+
+// UserLookupResponse is the compact user returned by typeahead lookups
+type UserLookupResponse struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// ToUserLookupResponse converts a UserResponse to the lookup shape
+func ToUserLookupResponse(user UserResponse) UserLookupResponse {
+	return UserLookupResponse{
+		ID:    user.ID,
+		Name:  strings.TrimSpace(user.FirstName + " " + user.LastName),
+		Email: user.Email,
+	}
+}
+
+// IncludeStats embeds the user's stats via include=stats
+const IncludeStats = "stats"
+
+// UserStatsResponse represents the stats embedded in a user response
+type UserStatsResponse struct {
+	LoginCount int64 `json:"loginCount"`
+	Points     int64 `json:"points"`
+}
+
+// ToUserStatsResponse converts a UserStats entity to UserStatsResponse DTO
+func ToUserStatsResponse(stats *entity.UserStats) *UserStatsResponse {
+	return &UserStatsResponse{
+		LoginCount: stats.LoginCount,
+		Points:     stats.Points,
+	}
 }
 
 // ToUserResponse converts a User entity to UserResponse DTO
 func ToUserResponse(user *entity.User) UserResponse {
 	return UserResponse{
-		ID:        user.ID.Hex(),
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Role:      user.Role,
-		IsActive:  user.IsActive,
-		CreatedAt: utils.FormatIndonesian(user.CreatedAt),
-		UpdatedAt: utils.FormatIndonesian(user.UpdatedAt),
+		ID:                 user.ID.Hex(),
+		Email:              user.Email,
+		FirstName:          user.FirstName,
+		LastName:           user.LastName,
+		Role:               user.Role,
+		Team:               user.Team,
+		IsActive:           user.IsActive,
+		MustChangePassword: user.MustChangePassword,
+		Version:            user.Version,
+		CreatedAt:          utils.FormatIndonesian(user.CreatedAt),
+		UpdatedAt:          utils.FormatIndonesian(user.UpdatedAt),
 	}
 }
 
@@ -40,11 +94,43 @@ func ToUserResponses(users []*entity.User) []UserResponse {
 	return responses
 }
 
+// ToAdminUserResponse converts a User entity to UserResponse DTO including
+// the user's activity
+func ToAdminUserResponse(user *entity.User) UserResponse {
+	response := ToUserResponse(user)
+	response.Activity = &UserActivityResponse{
+		LastLoginAt: formatOptional(user.LastLoginAt),
+		LastSeenAt:  formatOptional(user.LastSeenAt),
+		LoginCount:  user.LoginCount,
+	}
+	return response
+}
+
+// ToAdminUserResponses converts a slice of User entities to UserResponse DTOs
+// including their activity
+func ToAdminUserResponses(users []*entity.User) []UserResponse {
+	responses := make([]UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = ToAdminUserResponse(user)
+	}
+	return responses
+}
+
+// formatOptional formats t, keeping nil as null
+func formatOptional(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := utils.FormatIndonesian(*t)
+	return &formatted
+}
+
 // UpdateUserRequest represents the update user request body
 type UpdateUserRequest struct {
 	FirstName *string      `json:"firstName,omitempty" validate:"omitempty,min=2"`
 	LastName  *string      `json:"lastName,omitempty" validate:"omitempty,min=2"`
 	Role      *entity.Role `json:"role,omitempty"`
+	Team      *string      `json:"team,omitempty" validate:"omitempty,max=64"`
 	IsActive  *bool        `json:"isActive,omitempty"`
 }
 
@@ -53,3 +139,60 @@ type ChangePasswordRequest struct {
 	OldPassword string `json:"oldPassword" validate:"required"`
 	NewPassword string `json:"newPassword" validate:"required,min=8"`
 }
+
+// CreateUserRequest represents the admin create user request body
+type CreateUserRequest struct {
+	Email     string      `json:"email" validate:"required,email"`
+	Password  string      `json:"password,omitempty" validate:"omitempty,min=8"`
+	FirstName string      `json:"firstName" validate:"required,min=2"`
+	LastName  string      `json:"lastName" validate:"required,min=2"`
+	Role      entity.Role `json:"role" validate:"required,oneof=ADMIN USER"`
+	Team      string      `json:"team,omitempty" validate:"omitempty,max=64"`
+	IsActive  *bool       `json:"isActive,omitempty"`
+}
+
+// CreateUserResponse represents the created user. TemporaryPassword is only
+// set when the password was generated and is never returned again.
+type CreateUserResponse struct {
+	User              UserResponse `json:"user"`
+	TemporaryPassword string       `json:"temporaryPassword,omitempty"`
+}
+
+// Bulk user actions
+const (
+	BulkActionActivate   = "activate"
+	BulkActionDeactivate = "deactivate"
+	BulkActionDelete     = "delete"
+	BulkActionChangeRole = "change-role"
+)
+
+// BulkUserFilter selects users for a bulk action when no IDs are given
+type BulkUserFilter struct {
+	Role     *entity.Role `json:"role,omitempty" validate:"omitempty,oneof=ADMIN USER"`
+	IsActive *bool        `json:"isActive,omitempty"`
+}
+
+// BulkUserRequest represents the bulk user action request body
+type BulkUserRequest struct {
+	Action string          `json:"action" validate:"required,oneof=activate deactivate delete change-role"`
+	IDs    []string        `json:"ids,omitempty" validate:"required_without=Filter,omitempty,max=1000,dive,mongodb"`
+	Filter *BulkUserFilter `json:"filter,omitempty"`
+	Role   *entity.Role    `json:"role,omitempty" validate:"required_if=Action change-role,omitempty,oneof=ADMIN USER"`
+}
+
+// BulkItemResult is the outcome of a bulk action on a single user
+type BulkItemResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkUserResponse reports the outcome of a bulk action per user
+type BulkUserResponse struct {
+	Action        string           `json:"action"`
+	Total         int              `json:"total"`
+	Succeeded     int              `json:"succeeded"`
+	Failed        int              `json:"failed"`
+	Transactional bool             `json:"transactional"`
+	Items         []BulkItemResult `json:"items"`
+}
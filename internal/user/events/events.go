@@ -0,0 +1,25 @@
+package events
+
+import "github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+
+// User event types. The aggregate ID of every user event is the user ID.
+const (
+	TypeRegistered      = "user.registered"
+	TypeCreated         = "user.created"
+	TypeUpdated         = "user.updated"
+	TypePasswordChanged = "user.password_changed"
+	TypeDeleted         = "user.deleted"
+	TypeRestored        = "user.restored"
+)
+
+// UserPayload is the data of events that carry the user's state after the
+// change. Changed lists the updated fields of user.updated events.
+type UserPayload struct {
+	User    dto.UserResponse `json:"user"`
+	Changed []string         `json:"changed,omitempty"`
+}
+
+// UserRefPayload is the data of events that only identify the user
+type UserRefPayload struct {
+	UserID string `json:"userId"`
+}
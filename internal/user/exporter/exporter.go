@@ -0,0 +1,283 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/export"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
+)
+
+const (
+	// exportBucket is the GridFS bucket asynchronous exports are stored in, so
+	// they can be downloaded from any replica
+	exportBucket = "user_exports"
+
+	// JobType is the queue job that generates an asynchronous export
+	JobType = "user.export"
+
+	exportMaxAttempts = 3
+)
+
+var ErrExportNotReady = errors.New("export is not ready for download")
+
+// userColumns defines the CSV/XLSX layout of a user export
+var userColumns = []export.Column[dto.UserResponse]{
+	{Header: "ID", Value: func(u dto.UserResponse) string { return u.ID }},
+	{Header: "Email", Value: func(u dto.UserResponse) string { return u.Email }},
+	{Header: "First Name", Value: func(u dto.UserResponse) string { return u.FirstName }},
+	{Header: "Last Name", Value: func(u dto.UserResponse) string { return u.LastName }},
+	{Header: "Role", Value: func(u dto.UserResponse) string { return string(u.Role) }},
+	{Header: "Active", Value: func(u dto.UserResponse) string { return strconv.FormatBool(u.IsActive) }},
+	{Header: "Created At", Value: func(u dto.UserResponse) string { return u.CreatedAt }},
+	{Header: "Updated At", Value: func(u dto.UserResponse) string { return u.UpdatedAt }},
+}
+
+// Exporter writes users to CSV, NDJSON or XLSX, either streamed directly to
+// the caller or generated in the background for later download
+type Exporter interface {
+	// Write streams every user matching filter to w and returns the row count
+	Write(ctx context.Context, w io.Writer, filter bson.M, format export.Format) (int, error)
+	// Start queues the export to be generated by the worker, returning the
+	// job to poll
+	Start(ctx context.Context, filter bson.M, format export.Format) (*dto.ExportJob, error)
+	// Run generates a queued export; it is the queue handler for JobType
+	Run(ctx context.Context, job *queue.Job) error
+	// DeleteExpired removes generated files whose job has expired
+	DeleteExpired(ctx context.Context) error
+	// Get returns an asynchronous export job
+	Get(ctx context.Context, id string) (*dto.ExportJob, error)
+	// Open returns the generated file of a completed export job
+	Open(ctx context.Context, id string) (io.ReadCloser, *dto.ExportJob, error)
+}
+
+type exporterImpl struct {
+	userService service.UserService
+	bucket      *mongo.GridFSBucket
+	store       JobStore
+	queue       *queue.Queue
+}
+
+// exportPayload is the queue job payload of an asynchronous export
+type exportPayload struct {
+	JobID  string        `json:"jobId"`
+	Format export.Format `json:"format"`
+	// Filter is MongoDB extended JSON, which keeps types such as ObjectIDs
+	// and dates
+	Filter string `json:"filter"`
+}
+
+// NewExporter creates a user exporter
+func NewExporter(userService service.UserService, db *database.MongoDB, store JobStore, jobQueue *queue.Queue) Exporter {
+	return &exporterImpl{
+		userService: userService,
+		bucket:      db.Database.GridFSBucket(options.GridFSBucket().SetName(exportBucket)),
+		store:       store,
+		queue:       jobQueue,
+	}
+}
+
+// FileName builds the download file name for an export created at t
+func FileName(format export.Format, t time.Time) string {
+	return fmt.Sprintf("users-%s.%s", t.Format("20060102-150405"), format)
+}
+
+func (e *exporterImpl) Write(ctx context.Context, w io.Writer, filter bson.M, format export.Format) (int, error) {
+	writer, err := export.NewWriter(w, format, userColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	err = e.userService.Export(ctx, filter, func(user dto.UserResponse) error {
+		rows++
+		return writer.Write(user)
+	})
+	if err != nil {
+		return rows, err
+	}
+
+	return rows, writer.Close()
+}
+
+func (e *exporterImpl) Start(ctx context.Context, filter bson.M, format export.Format) (*dto.ExportJob, error) {
+	now := time.Now()
+	job := &dto.ExportJob{
+		ID:        bson.NewObjectID().Hex(),
+		Status:    dto.JobStatusQueued,
+		Format:    string(format),
+		FileName:  FileName(format, now),
+		CreatedAt: utils.FormatIndonesian(now),
+	}
+	if err := e.store.Save(ctx, job); err != nil {
+		logger.Error("failed to save export job", zap.Error(err))
+		return nil, err
+	}
+
+	rawFilter, err := bson.MarshalExtJSON(filter, true, false)
+	if err != nil {
+		return nil, err
+	}
+	payload := exportPayload{JobID: job.ID, Format: format, Filter: string(rawFilter)}
+	if _, err := e.queue.Enqueue(ctx, JobType, payload, queue.EnqueueOptions{MaxAttempts: exportMaxAttempts}); err != nil {
+		logger.Error("failed to queue export job", zap.Error(err), zap.String("job_id", job.ID))
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (e *exporterImpl) Run(ctx context.Context, qj *queue.Job) error {
+	var payload exportPayload
+	if err := qj.Decode(&payload); err != nil {
+		return queue.Permanent(err)
+	}
+	var filter bson.M
+	if err := bson.UnmarshalExtJSON([]byte(payload.Filter), true, &filter); err != nil {
+		return queue.Permanent(err)
+	}
+
+	job, err := e.store.Get(ctx, payload.JobID)
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			// Expired before the worker got to it; nobody is polling anymore
+			return queue.Permanent(err)
+		}
+		return err
+	}
+
+	job.Status = dto.JobStatusRunning
+	job.Error = ""
+	e.save(ctx, job)
+
+	err = e.upload(ctx, job, filter, payload.Format)
+	if err != nil {
+		logger.Error("user export failed", zap.Error(err), zap.String("job_id", job.ID), zap.Int("attempt", qj.Attempts))
+		// Pollers see the job queued again until its last attempt fails
+		job.Status = dto.JobStatusQueued
+		job.Error = err.Error()
+		if qj.Attempts >= qj.MaxAttempts {
+			job.Status = dto.JobStatusFailed
+			job.FinishedAt = utils.FormatIndonesian(time.Now())
+		}
+		e.save(ctx, job)
+		return err
+	}
+
+	logger.Info("user export completed", zap.String("job_id", job.ID), zap.Int("rows", job.Rows))
+	job.Status = dto.JobStatusCompleted
+	job.FinishedAt = utils.FormatIndonesian(time.Now())
+	e.save(ctx, job)
+	return nil
+}
+
+func (e *exporterImpl) upload(ctx context.Context, job *dto.ExportJob, filter bson.M, format export.Format) error {
+	// Drop the chunks of an earlier attempt that did not finish
+	if err := e.bucket.Delete(ctx, job.ID); err != nil && !errors.Is(err, mongo.ErrFileNotFound) {
+		return err
+	}
+
+	stream, err := e.bucket.OpenUploadStreamWithID(ctx, job.ID, job.FileName)
+	if err != nil {
+		return err
+	}
+
+	counter := &countingWriter{w: stream}
+	rows, err := e.Write(ctx, counter, filter, format)
+	if err != nil {
+		_ = stream.Abort()
+		return err
+	}
+	if err := stream.Close(); err != nil {
+		return err
+	}
+
+	job.Rows = rows
+	job.Size = counter.n
+	return nil
+}
+
+func (e *exporterImpl) save(ctx context.Context, job *dto.ExportJob) {
+	if err := e.store.Save(ctx, job); err != nil {
+		logger.Error("failed to save export job", zap.Error(err), zap.String("job_id", job.ID))
+	}
+}
+
+func (e *exporterImpl) DeleteExpired(ctx context.Context) error {
+	cursor, err := e.bucket.Find(ctx, bson.M{"uploadDate": bson.M{"$lt": time.Now().Add(-exportRetention)}})
+	if err != nil {
+		logger.Error("failed to find expired exports", zap.Error(err))
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	deleted := 0
+	for cursor.Next(ctx) {
+		var file struct {
+			ID any `bson:"_id"`
+		}
+		if err := cursor.Decode(&file); err != nil {
+			continue
+		}
+		if err := e.bucket.Delete(ctx, file.ID); err != nil {
+			logger.Error("failed to delete expired export", zap.Error(err))
+			continue
+		}
+		deleted++
+	}
+
+	if deleted > 0 {
+		logger.Info("expired exports deleted", zap.Int("count", deleted))
+	}
+	return cursor.Err()
+}
+
+func (e *exporterImpl) Get(ctx context.Context, id string) (*dto.ExportJob, error) {
+	return e.store.Get(ctx, id)
+}
+
+func (e *exporterImpl) Open(ctx context.Context, id string) (io.ReadCloser, *dto.ExportJob, error) {
+	job, err := e.store.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != dto.JobStatusCompleted {
+		return nil, job, ErrExportNotReady
+	}
+
+	stream, err := e.bucket.OpenDownloadStream(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrFileNotFound) {
+			return nil, nil, ErrJobNotFound
+		}
+		return nil, nil, err
+	}
+
+	return stream, job, nil
+}
+
+// countingWriter tracks how many bytes were written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,62 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+)
+
+const (
+	exportJobPrefix = "user_export:"
+	// exportRetention is how long finished exports can be downloaded
+	exportRetention = 24 * time.Hour
+)
+
+var ErrJobNotFound = errors.New("export job not found")
+
+// JobStore persists export job status so any replica can answer polls
+type JobStore interface {
+	Save(ctx context.Context, job *dto.ExportJob) error
+	Get(ctx context.Context, id string) (*dto.ExportJob, error)
+}
+
+type jobStoreRedis struct {
+	redis *database.Redis
+}
+
+// NewJobStore creates a Redis-backed export job store
+func NewJobStore(redis *database.Redis) JobStore {
+	return &jobStoreRedis{
+		redis: redis,
+	}
+}
+
+func (s *jobStoreRedis) Save(ctx context.Context, job *dto.ExportJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.redis.Client.Set(ctx, exportJobPrefix+job.ID, data, exportRetention).Err()
+}
+
+func (s *jobStoreRedis) Get(ctx context.Context, id string) (*dto.ExportJob, error) {
+	data, err := s.redis.Client.Get(ctx, exportJobPrefix+id).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	var job dto.ExportJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/validator"
+)
+
+// BulkUsers godoc
+// @Summary      Bulk user action
+// @Description  Activate, deactivate, delete or change the role of many users at once, selected by IDs or by a filter (ADMIN only). Runs in a transaction where the deployment supports it and reports the result per user
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body dto.BulkUserRequest true "Bulk action request"
+// @Success      200 {object} response.Response{data=dto.BulkUserResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/bulk [post]
+func (h *UserHandler) BulkUsers(c *fiber.Ctx) error {
+	payload := middleware.GetAuthPayload(c)
+	if payload == nil {
+		return response.Unauthorized(c, "authentication required")
+	}
+
+	var req dto.BulkUserRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.BadRequest(c, "invalid request body", validator.FormatValidationErrors(err))
+	}
+
+	result, err := h.userService.Bulk(c.Context(), payload.UserID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrEmptyBulkFilter) || errors.Is(err, service.ErrTooManyBulkUsers) {
+			return response.BadRequest(c, err.Error(), "")
+		}
+		return response.InternalServerError(c, "failed to apply bulk action")
+	}
+
+	return response.Success(c, fiber.StatusOK, "bulk action completed", result)
+}
@@ -22,6 +22,7 @@ import (
 // @Success      200 {object} response.Response
 // @Failure      400 {object} response.Response
 // @Failure      401 {object} response.Response
+// @Failure      409 {object} response.Response
 // @Failure      500 {object} response.Response
 // @Router       /users/me/password [put]
 func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
@@ -42,6 +43,9 @@ func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
 		if errors.Is(err, service.ErrInvalidOldPassword) {
 			return response.BadRequest(c, "invalid old password", "")
 		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			return response.Conflict(c, "user was modified concurrently, please retry", "")
+		}
 		return response.InternalServerError(c, "failed to change password")
 	}
 
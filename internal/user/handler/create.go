@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/validator"
+)
+
+// CreateUser godoc
+// @Summary      Create user
+// @Description  Create a user with a role (ADMIN only). The password is temporary and must be changed on first login; if omitted, one is generated and returned once
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body dto.CreateUserRequest true "Create user request"
+// @Success      201 {object} response.Response{data=dto.CreateUserResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      409 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users [post]
+func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
+	var req dto.CreateUserRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.BadRequest(c, "invalid request body", validator.FormatValidationErrors(err))
+	}
+
+	result, err := h.userService.Create(c.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyExists) {
+			return response.Conflict(c, "email already exists", "")
+		}
+		return response.InternalServerError(c, "failed to create user")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "user created successfully", result)
+}
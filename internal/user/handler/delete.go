@@ -10,7 +10,7 @@ import (
 
 // DeleteUser godoc
 // @Summary      Delete user
-// @Description  Delete a user (ADMIN only)
+// @Description  Soft-delete a user; it can be restored until the retention period expires (ADMIN only)
 // @Tags         users
 // @Produce      json
 // @Security     BearerAuth
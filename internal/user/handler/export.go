@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/exporter"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/export"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// writeWait is how long a single write of an export stream may take
+const writeWait = 10 * time.Second
+
+// ExportUsers godoc
+// @Summary      Export users
+// @Description  Export users matching the same filters as the user list as CSV, NDJSON or XLSX (ADMIN only). The file is streamed from the database cursor; with async=true it is generated in the background and downloaded later
+// @Tags         users
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce      json
+// @Security     BearerAuth
+// @Param        format query string false "Export format (csv, ndjson or xlsx)" default(csv)
+// @Param        async query bool false "Generate in the background and return a job" default(false)
+// @Param        first-name query string false "Filter by first name; also first-name[ne|in|nin|contains]"
+// @Param        last-name query string false "Filter by last name; also last-name[ne|in|nin|contains]"
+// @Param        email query string false "Filter by email; also email[ne|in|nin|contains]"
+// @Param        role query string false "Filter by role; also role[ne|in]"
+// @Param        is-active query bool false "Filter by status"
+// @Param        created-at[gte] query string false "Created on or after (RFC 3339 or YYYY-MM-DD); also gt, lt, lte"
+// @Param        search query string false "Full-text search across name and email"
+// @Success      200 {file} file
+// @Success      202 {object} response.Response{data=dto.ExportJob}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/export [get]
+func (h *UserHandler) ExportUsers(c *fiber.Ctx) error {
+	format, err := export.ParseFormat(c.Query("format", string(export.FormatCSV)))
+	if err != nil {
+		return response.BadRequest(c, "invalid export format", err.Error())
+	}
+
+	q, err := parseUserQuery(c)
+	if err != nil {
+		return response.BadRequest(c, "invalid query", err.Error())
+	}
+	filter := q.Filter
+	if text := c.Query("search"); text != "" {
+		filter = h.userService.SearchFilter(filter, strings.Clone(text))
+	}
+
+	if c.QueryBool("async", false) {
+		job, err := h.userExporter.Start(c.Context(), filter, format)
+		if err != nil {
+			return response.InternalServerError(c, "failed to start export")
+		}
+		return response.Success(c, fiber.StatusAccepted, "export started", job)
+	}
+
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Attachment(exporter.FileName(format, time.Now()))
+
+	conn := c.Context().Conn()
+
+	// The body is written after the handler returns, so errors past this
+	// point can only be logged
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		out := export.NewDeadlineWriter(w, conn, writeWait)
+		rows, err := h.userExporter.Write(context.Background(), out, filter, format)
+		if err != nil {
+			logger.Error("user export stream aborted", zap.Error(err), zap.Int("rows", rows))
+			return
+		}
+		if err := out.Flush(); err != nil {
+			logger.Error("failed to flush user export", zap.Error(err))
+		}
+	})
+
+	return nil
+}
+
+// GetExportJob godoc
+// @Summary      Get export job
+// @Description  Get the status of an asynchronous user export (ADMIN only)
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        jobId path string true "Export job ID"
+// @Success      200 {object} response.Response{data=dto.ExportJob}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/export/{jobId} [get]
+func (h *UserHandler) GetExportJob(c *fiber.Ctx) error {
+	job, err := h.userExporter.Get(c.Context(), c.Params("jobId"))
+	if err != nil {
+		if errors.Is(err, exporter.ErrJobNotFound) {
+			return response.NotFound(c, "export job not found")
+		}
+		return response.InternalServerError(c, "failed to get export job")
+	}
+
+	return response.Success(c, fiber.StatusOK, "export job retrieved successfully", job)
+}
+
+// DownloadExport godoc
+// @Summary      Download export
+// @Description  Download the file of a completed asynchronous user export (ADMIN only)
+// @Tags         users
+// @Produce      octet-stream
+// @Security     BearerAuth
+// @Param        jobId path string true "Export job ID"
+// @Success      200 {file} file
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      409 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/export/{jobId}/download [get]
+func (h *UserHandler) DownloadExport(c *fiber.Ctx) error {
+	// The file is read after the handler returns, so it must not use the request context
+	file, job, err := h.userExporter.Open(context.Background(), c.Params("jobId"))
+	if err != nil {
+		if errors.Is(err, exporter.ErrJobNotFound) {
+			return response.NotFound(c, "export job not found")
+		}
+		if errors.Is(err, exporter.ErrExportNotReady) {
+			return response.Conflict(c, "export is not ready for download", "status: "+job.Status)
+		}
+		return response.InternalServerError(c, "failed to download export")
+	}
+
+	format, _ := export.ParseFormat(job.Format)
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Attachment(job.FileName)
+
+	return c.SendStream(file, int(job.Size))
+}
@@ -4,8 +4,11 @@ import (
 	"errors"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/query"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
 )
 
 // GetUserByID godoc
@@ -15,7 +18,11 @@ import (
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path string true "User ID"
+// @Param        fields query string false "Comma separated response fields to return, e.g. id,firstName,lastName"
+// @Param        include query string false "Comma separated related resources to embed (stats)"
 // @Success      200 {object} response.Response{data=dto.UserResponse}
+// @Header       200 {string} ETag "Current user version, usable in If-Match"
+// @Failure      400 {object} response.Response
 // @Failure      401 {object} response.Response
 // @Failure      403 {object} response.Response
 // @Failure      404 {object} response.Response
@@ -24,6 +31,15 @@ import (
 func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
+	fields, _, err := userQuerySchema.ParseFields(c.Query("fields"))
+	if err != nil {
+		return response.BadRequest(c, "invalid query", err.Error())
+	}
+	include, err := query.ParseInclude(c.Query("include"), dto.IncludeStats)
+	if err != nil {
+		return response.BadRequest(c, "invalid query", err.Error())
+	}
+
 	user, err := h.userService.GetByID(c.Context(), id)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
@@ -32,5 +48,20 @@ func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
 		return response.InternalServerError(c, "failed to get user")
 	}
 
-	return response.Success(c, fiber.StatusOK, "user retrieved successfully", user)
+	c.Set(fiber.HeaderETag, utils.FormatETag(user.Version))
+
+	if len(include) > 0 {
+		users := []dto.UserResponse{*user}
+		if err := h.userService.Embed(c.Context(), users, include); err != nil {
+			return response.InternalServerError(c, "failed to get user")
+		}
+		user = &users[0]
+	}
+
+	data, err := selectFields(user, fields, include)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get user")
+	}
+
+	return response.Success(c, fiber.StatusOK, "user retrieved successfully", data)
 }
@@ -1,17 +1,37 @@
 package handler
 
 import (
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/exporter"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/importer"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/lookup"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
 )
 
 // UserHandler handles user-related HTTP requests
 type UserHandler struct {
-	userService service.UserService
+	userService  service.UserService
+	userImporter importer.Importer
+	userExporter exporter.Exporter
+	userLookup   lookup.Lookup
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userService service.UserService) *UserHandler {
+func NewUserHandler(userService service.UserService, userImporter importer.Importer, userExporter exporter.Exporter, userLookup lookup.Lookup) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:  userService,
+		userImporter: userImporter,
+		userExporter: userExporter,
+		userLookup:   userLookup,
 	}
 }
+
+// forViewer removes the admin-only activity from a user shown to a non-admin
+func forViewer(payload *token.Payload, user *dto.UserResponse) *dto.UserResponse {
+	if entity.Role(payload.Role) != entity.RoleAdmin {
+		user.Activity = nil
+	}
+	return user
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/importer"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// ImportUsers godoc
+// @Summary      Import users
+// @Description  Upload a CSV or NDJSON file of users (email, password, firstName, lastName, optional role) and import it in the background (ADMIN only). Imported passwords are temporary. Poll the returned job for progress and the per-row error report
+// @Tags         users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file formData file true "CSV or NDJSON file"
+// @Param        format query string false "File format (csv or ndjson), detected from the file name when omitted"
+// @Param        dry-run query bool false "Only validate, do not create users" default(false)
+// @Success      202 {object} response.Response{data=dto.ImportJob}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/import [post]
+func (h *UserHandler) ImportUsers(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return response.BadRequest(c, "file is required", err.Error())
+	}
+
+	format, err := importer.FormatFromFilename(fileHeader.Filename)
+	if name := c.Query("format"); name != "" {
+		format, err = importer.ParseFormat(name)
+	}
+	if err != nil {
+		return response.BadRequest(c, "invalid import format", err.Error())
+	}
+
+	// Copy the upload, the request buffers are released once the handler returns
+	file, err := fileHeader.Open()
+	if err != nil {
+		return response.BadRequest(c, "failed to read file", err.Error())
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return response.BadRequest(c, "failed to read file", err.Error())
+	}
+
+	job, err := h.userImporter.Start(c.Context(), data, importer.Options{
+		Format: format,
+		DryRun: c.QueryBool("dry-run", false),
+	})
+	if err != nil {
+		if errors.Is(err, importer.ErrMissingColumns) || errors.Is(err, importer.ErrUnsupportedFormat) {
+			return response.BadRequest(c, "invalid import file", err.Error())
+		}
+		return response.InternalServerError(c, "failed to start import")
+	}
+
+	return response.Success(c, fiber.StatusAccepted, "import started", job)
+}
+
+// GetImportJob godoc
+// @Summary      Get import job
+// @Description  Get the progress and per-row error report of a user import (ADMIN only)
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        jobId path string true "Import job ID"
+// @Success      200 {object} response.Response{data=dto.ImportJob}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/import/{jobId} [get]
+func (h *UserHandler) GetImportJob(c *fiber.Ctx) error {
+	job, err := h.userImporter.Get(c.Context(), c.Params("jobId"))
+	if err != nil {
+		if errors.Is(err, importer.ErrJobNotFound) {
+			return response.NotFound(c, "import job not found")
+		}
+		return response.InternalServerError(c, "failed to get import job")
+	}
+
+	return response.Success(c, fiber.StatusOK, "import job retrieved successfully", job)
+}
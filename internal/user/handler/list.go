@@ -1,86 +1,214 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/query"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 // GetAllUsers godoc
 // @Summary      Get all users
-// @Description  Get a paginated list of all users (ADMIN only)
+// @Description  Get a paginated list of all users (ADMIN only). Pass paginate=cursor, after or before to use cursor pagination, which skips the total count unless with-total=true
 // @Tags         users
 // @Produce      json
 // @Security     BearerAuth
 // @Param        page query int false "Page number" default(1)
 // @Param        per-page query int false "Items per page" default(10)
+// @Param        first-name query string false "Filter by first name; also first-name[ne|in|nin|contains]"
+// @Param        last-name query string false "Filter by last name; also last-name[ne|in|nin|contains]"
+// @Param        email query string false "Filter by email; also email[ne|in|nin|contains]"
+// @Param        role query string false "Filter by role; also role[ne|in]"
+// @Param        team query string false "Filter by team; also team[ne|in]"
+// @Param        is-active query bool false "Filter by status"
+// @Param        created-at[gte] query string false "Created on or after (RFC 3339 or YYYY-MM-DD); also gt, lt, lte"
+// @Param        updated-at[gte] query string false "Updated on or after (RFC 3339 or YYYY-MM-DD); also gt, lt, lte"
+// @Param        last-login-at[lt] query string false "Last logged in before (RFC 3339 or YYYY-MM-DD); also gt, gte, lte"
+// @Param        last-seen-at[lt] query string false "Last seen before (RFC 3339 or YYYY-MM-DD); also gt, gte, lte"
+// @Param        login-count[lte] query int false "Filter by number of logins; also eq, ne, gt, gte, lt, in, nin"
+// @Param        inactive-days query int false "Only dormant users: not seen for this many days, or never seen and created before then"
+// @Param        search query string false "Full-text search across name and email, ranked by relevance"
+// @Param        fields query string false "Comma separated response fields to return, e.g. id,firstName,lastName"
+// @Param        include query string false "Comma separated related resources to embed (stats)"
+// @Param        sort query string false "Comma separated sort fields, '-' for descending; last-seen-at lists the least recently seen first" default(-createdAt)
+// @Param        paginate query string false "Pagination mode (page or cursor)" default(page)
+// @Param        after query string false "Cursor of the page to continue after"
+// @Param        before query string false "Cursor of the page to continue before"
+// @Param        with-total query bool false "Include the total count in cursor mode" default(false)
 // @Success      200 {object} response.PaginatedResponse{data=[]dto.UserResponse}
+// @Failure      400 {object} response.Response
 // @Failure      401 {object} response.Response
 // @Failure      403 {object} response.Response
 // @Failure      500 {object} response.Response
 // @Router       /users [get]
 func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
-	page, _ := strconv.Atoi(c.Query("page", "1"))
-	perPage, _ := strconv.Atoi(c.Query("per-page", "10"))
+	page, perPage := pagination.ParsePage(c)
 
-	if page < 1 {
-		page = 1
+	q, err := parseUserQuery(c)
+	if err != nil {
+		return response.BadRequest(c, "invalid query", err.Error())
 	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 10
+	include, err := query.ParseInclude(c.Query("include"), dto.IncludeStats)
+	if err != nil {
+		return response.BadRequest(c, "invalid query", err.Error())
 	}
 
-	// 1. Build Filter with Whitelist (kebab-case to camelCase mapping)
-	filter := bson.M{}
-	whitelistMap := map[string]string{
-		"first-name": "firstName",
-		"last-name":  "lastName",
-		"email":      "email",
-		"role":       "role",
-		"is-active":  "isActive",
-	}
+	cursorQuery, cursorMode := parseCursorQuery(c, perPage)
 
-	for queryParam, internalField := range whitelistMap {
-		if val := c.Query(queryParam); val != "" {
-			if queryParam == "is-active" {
-				isActive, err := strconv.ParseBool(val)
-				if err == nil {
-					filter[internalField] = isActive
-				}
-				continue
-			}
-			filter[internalField] = val
+	if text := c.Query("search"); text != "" {
+		if cursorMode {
+			return response.BadRequest(c, "invalid query", "search is not supported with cursor pagination")
+		}
+		// Search results are ranked by relevance unless a sort was asked for
+		if c.Query("sort") == "" {
+			q.Sort = nil
 		}
+		users, total, err := h.userService.Search(c.Context(), q, text, page, perPage)
+		if err != nil {
+			return response.InternalServerError(c, "failed to search users")
+		}
+		data, err := h.shapeUsers(c, users, q.Fields, include)
+		if err != nil {
+			return response.InternalServerError(c, "failed to search users")
+		}
+		return response.Paginated(c, fiber.StatusOK, "users retrieved successfully", data, page, perPage, total)
 	}
 
-	// 2. Build Search (Global regex search across camelCase fields)
-	if search := c.Query("search"); search != "" {
-		searchFilter := bson.A{
-			bson.M{"firstName": bson.M{"$regex": search, "$options": "i"}},
-			bson.M{"lastName": bson.M{"$regex": search, "$options": "i"}},
-			bson.M{"email": bson.M{"$regex": search, "$options": "i"}},
+	if cursorMode {
+		// Cursors encode the createdAt position, so they only work with the default order
+		if c.Query("sort") != "" {
+			return response.BadRequest(c, "invalid query", "sort is not supported with cursor pagination")
 		}
-
-		// If there are already filters, use $and
-		if len(filter) > 0 {
-			filter = bson.M{
-				"$and": bson.A{
-					filter,
-					bson.M{"$or": searchFilter},
-				},
+		users, cursorPage, err := h.userService.GetAllCursor(c.Context(), q, cursorQuery)
+		if err != nil {
+			if errors.Is(err, pagination.ErrInvalidCursor) {
+				return response.BadRequest(c, "invalid cursor", err.Error())
 			}
-		} else {
-			filter = bson.M{"$or": searchFilter}
+			return response.InternalServerError(c, "failed to get users")
+		}
+		data, err := h.shapeUsers(c, users, q.Fields, include)
+		if err != nil {
+			return response.InternalServerError(c, "failed to get users")
 		}
+		return response.CursorPaginated(c, fiber.StatusOK, "users retrieved successfully", data, cursorPage)
 	}
 
-	users, total, err := h.userService.GetAll(c.Context(), filter, page, perPage)
+	users, total, err := h.userService.GetAll(c.Context(), q, page, perPage)
 	if err != nil {
 		return response.InternalServerError(c, "failed to get users")
 	}
 
-	return response.Paginated(c, fiber.StatusOK, "users retrieved successfully", users, page, perPage, total)
+	data, err := h.shapeUsers(c, users, q.Fields, include)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get users")
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "users retrieved successfully", data, page, perPage, total)
+}
+
+// parseCursorQuery reports whether the request asks for cursor pagination
+// and, if so, the cursor query to run
+func parseCursorQuery(c *fiber.Ctx, perPage int) (pagination.CursorQuery, bool) {
+	query := pagination.CursorQuery{
+		After:     c.Query("after"),
+		Before:    c.Query("before"),
+		Limit:     perPage,
+		WithTotal: c.QueryBool("with-total", false),
+	}
+
+	cursorMode := c.Query("paginate") == "cursor" || query.After != "" || query.Before != ""
+	return query, cursorMode
+}
+
+// shapeUsers embeds the requested relations and trims the users down to the
+// requested sparse fieldset. Embedded relations are kept even when they are
+// not listed in fields.
+func (h *UserHandler) shapeUsers(c *fiber.Ctx, users []dto.UserResponse, fields, include []string) (any, error) {
+	if len(include) > 0 {
+		if err := h.userService.Embed(c.Context(), users, include); err != nil {
+			return nil, err
+		}
+	}
+	return selectFields(users, fields, include)
+}
+
+// selectFields trims a response to the sparse fieldset, keeping embedded
+// relations. Without a fieldset the response is returned whole.
+func selectFields(v any, fields, include []string) (any, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+	return query.Select(v, append(append([]string{}, fields...), include...))
+}
+
+// userQuerySchema whitelists the fields clients may filter and sort users by
+var userQuerySchema = query.Schema{
+	Fields: map[string]query.Field{
+		"first-name": {Name: "firstName", Type: query.String, Sortable: true},
+		"last-name":  {Name: "lastName", Type: query.String, Sortable: true},
+		"email":      {Name: "email", Type: query.String, Sortable: true},
+		"role":       {Name: "role", Type: query.String, Operators: []query.Operator{query.OpEq, query.OpNe, query.OpIn}, Sortable: true},
+		"team":       {Name: "team", Type: query.String, Operators: []query.Operator{query.OpEq, query.OpNe, query.OpIn}, Sortable: true},
+		"is-active":  {Name: "isActive", Type: query.Bool},
+		"created-at": {Name: "createdAt", Type: query.Time, Sortable: true},
+		"updated-at": {Name: "updatedAt", Type: query.Time, Sortable: true},
+		// Users who never logged in have no lastLoginAt or lastSeenAt; they
+		// sort first in ascending order
+		"last-login-at": {Name: "lastLoginAt", Type: query.Time, Sortable: true},
+		"last-seen-at":  {Name: "lastSeenAt", Type: query.Time, Sortable: true},
+		"login-count":   {Name: "loginCount", Type: query.Int, Sortable: true},
+	},
+	DefaultSort: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+	Selectable: map[string][]string{
+		"id":                 {"_id"},
+		"email":              {"email"},
+		"firstName":          {"firstName"},
+		"lastName":           {"lastName"},
+		"role":               {"role"},
+		"team":               {"team"},
+		"isActive":           {"isActive"},
+		"mustChangePassword": {"mustChangePassword"},
+		"version":            {"version"},
+		"createdAt":          {"createdAt"},
+		"updatedAt":          {"updatedAt"},
+		"activity":           {"lastLoginAt", "lastSeenAt", "loginCount"},
+	},
+}
+
+// parseUserQuery compiles the list query parameters into a Mongo filter and
+// sort. The result does not reference the request's buffers, so it stays
+// valid after the handler returns.
+func parseUserQuery(c *fiber.Ctx) (*query.Query, error) {
+	q, err := userQuerySchema.Parse(c.Queries())
+	if err != nil {
+		return nil, err
+	}
+
+	if raw := c.Query("inactive-days"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("%w: inactive-days must be a positive number of days", query.ErrInvalidQuery)
+		}
+		inactiveSince(q, time.Now().AddDate(0, 0, -days))
+	}
+
+	return q, nil
+}
+
+// inactiveSince restricts q to dormant users: users not seen since cutoff,
+// and users never seen who were created before it
+func inactiveSince(q *query.Query, cutoff time.Time) {
+	and, _ := q.Filter["$and"].(bson.A)
+	q.Filter["$and"] = append(and, bson.M{"$or": bson.A{
+		bson.M{"lastSeenAt": bson.M{"$lt": cutoff}},
+		bson.M{"lastSeenAt": nil, "createdAt": bson.M{"$lt": cutoff}},
+	}})
 }
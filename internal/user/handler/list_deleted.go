@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetDeletedUsers godoc
+// @Summary      Get deleted users
+// @Description  Get a paginated list of soft-deleted users awaiting purge (ADMIN only). Supports the same cursor pagination as the user list
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page query int false "Page number" default(1)
+// @Param        per-page query int false "Items per page" default(10)
+// @Param        paginate query string false "Pagination mode (page or cursor)" default(page)
+// @Param        after query string false "Cursor of the page to continue after"
+// @Param        before query string false "Cursor of the page to continue before"
+// @Param        with-total query bool false "Include the total count in cursor mode" default(false)
+// @Success      200 {object} response.PaginatedResponse{data=[]dto.UserResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/deleted [get]
+func (h *UserHandler) GetDeletedUsers(c *fiber.Ctx) error {
+	page, perPage := pagination.ParsePage(c)
+
+	if query, ok := parseCursorQuery(c, perPage); ok {
+		users, cursorPage, err := h.userService.GetDeletedCursor(c.Context(), query)
+		if err != nil {
+			if errors.Is(err, pagination.ErrInvalidCursor) {
+				return response.BadRequest(c, "invalid cursor", err.Error())
+			}
+			return response.InternalServerError(c, "failed to get deleted users")
+		}
+		return response.CursorPaginated(c, fiber.StatusOK, "deleted users retrieved successfully", users, cursorPage)
+	}
+
+	users, total, err := h.userService.GetDeleted(c.Context(), page, perPage)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get deleted users")
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "deleted users retrieved successfully", users, page, perPage, total)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// maxLookupPrefix bounds the prefix accepted by lookups
+const maxLookupPrefix = 64
+
+// LookupUsers godoc
+// @Summary      Look up users
+// @Description  Lightweight typeahead for user pickers returning id, name and email. Admins see all users; other users only see active members of their own team
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q query string true "Name or email prefix"
+// @Param        limit query int false "Maximum number of results" default(10)
+// @Success      200 {object} response.Response{data=[]dto.UserLookupResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/lookup [get]
+func (h *UserHandler) LookupUsers(c *fiber.Ctx) error {
+	payload := middleware.GetAuthPayload(c)
+	if payload == nil {
+		return response.Unauthorized(c, "authentication required")
+	}
+
+	prefix := c.Query("q")
+	if prefix == "" || len(prefix) > maxLookupPrefix {
+		return response.BadRequest(c, fmt.Sprintf("q is required and must be at most %d characters", maxLookupPrefix), "")
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 || limit > maxSuggestions {
+		limit = 10
+	}
+
+	users, err := h.userLookup.Find(c.Context(), payload.UserID, entity.Role(payload.Role), prefix, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.Unauthorized(c, "user no longer exists")
+		}
+		return response.InternalServerError(c, "failed to look up users")
+	}
+
+	return response.Success(c, fiber.StatusOK, "users retrieved successfully", users)
+}
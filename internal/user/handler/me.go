@@ -8,6 +8,7 @@ import (
 	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
 )
 
 // GetCurrentUser godoc
@@ -17,6 +18,7 @@ import (
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200 {object} response.Response{data=dto.UserResponse}
+// @Header       200 {string} ETag "Current user version, usable in If-Match"
 // @Failure      401 {object} response.Response
 // @Failure      404 {object} response.Response
 // @Failure      500 {object} response.Response
@@ -35,5 +37,6 @@ func (h *UserHandler) GetCurrentUser(c *fiber.Ctx) error {
 		return response.InternalServerError(c, "failed to get user")
 	}
 
-	return response.Success(c, fiber.StatusOK, "user retrieved successfully", user)
+	c.Set(fiber.HeaderETag, utils.FormatETag(user.Version))
+	return response.Success(c, fiber.StatusOK, "user retrieved successfully", forViewer(payload, user))
 }
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/validator"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// PatchUser godoc
+// @Summary      Patch user
+// @Description  Partially update a user with a JSON Merge Patch (RFC 7386) or JSON Patch (RFC 6902). Patchable fields are firstName, lastName, role and isActive; a null in a merge patch clears the field (ADMIN can patch any user, users can patch themselves)
+// @Tags         users
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "User ID"
+// @Param        If-Match header string false "ETag from a previous GET; the patch fails with 412 if the user has changed since"
+// @Param        request body dto.PatchableUser true "Merge patch or JSON patch document"
+// @Success      200 {object} response.Response{data=dto.UserResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      409 {object} response.Response
+// @Failure      412 {object} response.Response
+// @Failure      415 {object} response.Response
+// @Failure      422 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/{id} [patch]
+func (h *UserHandler) PatchUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+	payload := middleware.GetAuthPayload(c)
+	if payload == nil {
+		return response.Unauthorized(c, "authentication required")
+	}
+
+	// Check if user is updating themselves or is an admin
+	if payload.UserID != id && entity.Role(payload.Role) != entity.RoleAdmin {
+		return response.Forbidden(c, "you can only update your own profile")
+	}
+
+	versions, ok := parseIfMatch(c)
+	if !ok {
+		return response.PreconditionFailed(c, "If-Match does not match the current user version")
+	}
+
+	current, err := h.userService.GetByID(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.NotFound(c, "user not found")
+		}
+		return response.InternalServerError(c, "failed to get user")
+	}
+
+	if versions != nil && !slices.Contains(versions, current.Version) {
+		return response.PreconditionFailed(c, "user has been modified since it was retrieved")
+	}
+
+	original := dto.ToPatchableUser(current)
+	patched, err := dto.ApplyUserPatch(c.Get(fiber.HeaderContentType), original, c.Body())
+	if err != nil {
+		if errors.Is(err, dto.ErrUnsupportedPatchType) {
+			return response.UnsupportedMediaType(c, "content type must be "+dto.ContentTypeMergePatch+" or "+dto.ContentTypeJSONPatch)
+		}
+		return response.BadRequest(c, "invalid patch document", err.Error())
+	}
+
+	if err := validator.ValidateStruct(patched); err != nil {
+		return response.UnprocessableEntity(c, "patched user is invalid", validator.FormatValidationErrors(err))
+	}
+
+	req := patched.Changes(original)
+
+	// Only ADMIN can update role
+	if req.Role != nil && entity.Role(payload.Role) != entity.RoleAdmin {
+		return response.Forbidden(c, "only admins can update roles")
+	}
+
+	// Teams scope what users can look up, so only ADMIN can assign them
+	if req.Team != nil && entity.Role(payload.Role) != entity.RoleAdmin {
+		return response.Forbidden(c, "only admins can update teams")
+	}
+
+	// Update against the version the patch was computed from
+	user, err := h.userService.Update(c.Context(), id, req, current.Version)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.NotFound(c, "user not found")
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			if versions != nil {
+				return response.PreconditionFailed(c, "user has been modified since it was retrieved")
+			}
+			return response.Conflict(c, "user was modified concurrently, please retry", "")
+		}
+		return response.InternalServerError(c, "failed to update user")
+	}
+
+	c.Set(fiber.HeaderETag, utils.FormatETag(user.Version))
+	return response.Success(c, fiber.StatusOK, "user updated successfully", forViewer(payload, user))
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// RestoreUser godoc
+// @Summary      Restore user
+// @Description  Restore a soft-deleted user (ADMIN only)
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "User ID"
+// @Success      200 {object} response.Response{data=dto.UserResponse}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/{id}/restore [post]
+func (h *UserHandler) RestoreUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	user, err := h.userService.Restore(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.NotFound(c, "deleted user not found")
+		}
+		return response.InternalServerError(c, "failed to restore user")
+	}
+
+	return response.Success(c, fiber.StatusOK, "user restored successfully", user)
+}
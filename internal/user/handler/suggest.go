@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// maxSuggestions caps how many users a typeahead request can return
+const maxSuggestions = 20
+
+// SuggestUsers godoc
+// @Summary      Suggest users
+// @Description  Typeahead search returning users whose first name, last name or email starts with every word of q, ignoring case (ADMIN only)
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q query string true "Prefix to match"
+// @Param        limit query int false "Maximum number of suggestions" default(10)
+// @Success      200 {object} response.Response{data=[]dto.UserResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /users/suggest [get]
+func (h *UserHandler) SuggestUsers(c *fiber.Ctx) error {
+	prefix := c.Query("q")
+	if prefix == "" {
+		return response.BadRequest(c, "q is required", "")
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 || limit > maxSuggestions {
+		limit = 10
+	}
+
+	users, err := h.userService.Suggest(c.Context(), prefix, nil, limit)
+	if err != nil {
+		return response.InternalServerError(c, "failed to suggest users")
+	}
+
+	return response.Success(c, fiber.StatusOK, "users retrieved successfully", users)
+}
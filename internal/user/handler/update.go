@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"slices"
 
 	"github.com/gofiber/fiber/v2"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/validator"
 	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
 )
@@ -21,12 +23,14 @@ import (
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path string true "User ID"
+// @Param        If-Match header string false "ETag from a previous GET; the update fails with 412 if the user has changed since"
 // @Param        request body dto.UpdateUserRequest true "Update request"
 // @Success      200 {object} response.Response{data=dto.UserResponse}
 // @Failure      400 {object} response.Response
 // @Failure      401 {object} response.Response
 // @Failure      403 {object} response.Response
 // @Failure      404 {object} response.Response
+// @Failure      412 {object} response.Response
 // @Failure      500 {object} response.Response
 // @Router       /users/{id} [put]
 func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
@@ -51,13 +55,51 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 		return response.Forbidden(c, "only admins can update roles")
 	}
 
-	user, err := h.userService.Update(c.Context(), id, &req)
+	// Teams scope what users can look up, so only ADMIN can assign them
+	if req.Team != nil && entity.Role(payload.Role) != entity.RoleAdmin {
+		return response.Forbidden(c, "only admins can update teams")
+	}
+
+	versions, ok := parseIfMatch(c)
+	if !ok {
+		return response.PreconditionFailed(c, "If-Match does not match the current user version")
+	}
+
+	// The write is conditioned on the current version once it is known to be
+	// one of the listed ones
+	expectedVersion := service.AnyVersion
+	if versions != nil {
+		current, err := h.userService.GetByID(c.Context(), id)
+		if err != nil {
+			if errors.Is(err, service.ErrUserNotFound) {
+				return response.NotFound(c, "user not found")
+			}
+			return response.InternalServerError(c, "failed to get user")
+		}
+		if !slices.Contains(versions, current.Version) {
+			return response.PreconditionFailed(c, "user has been modified since it was retrieved")
+		}
+		expectedVersion = current.Version
+	}
+
+	user, err := h.userService.Update(c.Context(), id, &req, expectedVersion)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
 			return response.NotFound(c, "user not found")
 		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			return response.PreconditionFailed(c, "user has been modified since it was retrieved")
+		}
 		return response.InternalServerError(c, "failed to update user")
 	}
 
-	return response.Success(c, fiber.StatusOK, "user updated successfully", user)
+	c.Set(fiber.HeaderETag, utils.FormatETag(user.Version))
+	return response.Success(c, fiber.StatusOK, "user updated successfully", forViewer(payload, user))
+}
+
+// parseIfMatch reads the optional If-Match header. It returns the listed
+// versions (nil when absent or "*") and false if the header cannot match any
+// version this API issues.
+func parseIfMatch(c *fiber.Ctx) ([]int64, bool) {
+	return utils.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
 }
@@ -0,0 +1,314 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/queue"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/validator"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+const (
+	// progressInterval is how many rows are processed between progress saves
+	progressInterval = 25
+
+	// uploadBucket is the GridFS bucket uploads wait in until the worker
+	// imports them
+	uploadBucket = "user_imports"
+
+	// JobType is the queue job that runs a background import
+	JobType = "user.import"
+
+	// importMaxAttempts is 1 because a retry would report the users the
+	// failed attempt already created as duplicates
+	importMaxAttempts = 1
+)
+
+// Options controls an import run
+type Options struct {
+	Format Format
+	DryRun bool
+}
+
+// Importer validates import files and creates the users they contain
+type Importer interface {
+	// Run imports synchronously, calling onProgress (if set) as rows are processed
+	Run(ctx context.Context, r io.Reader, opts Options, onProgress func(*dto.ImportJob)) (*dto.ImportJob, error)
+	// Start parses data and queues it to be imported by the worker,
+	// returning the job to poll
+	Start(ctx context.Context, data []byte, opts Options) (*dto.ImportJob, error)
+	// RunJob imports a queued upload; it is the queue handler for JobType
+	RunJob(ctx context.Context, job *queue.Job) error
+	// Get returns a background import job
+	Get(ctx context.Context, id string) (*dto.ImportJob, error)
+}
+
+type importerImpl struct {
+	userService service.UserService
+	userRepo    repository.UserRepository
+	bucket      *mongo.GridFSBucket
+	store       JobStore
+	queue       *queue.Queue
+}
+
+// importPayload is the queue job payload of a background import
+type importPayload struct {
+	JobID  string `json:"jobId"`
+	Format Format `json:"format"`
+	DryRun bool   `json:"dryRun"`
+}
+
+// NewImporter creates a user importer. db, store and jobQueue may be nil when
+// only Run is used.
+func NewImporter(userService service.UserService, userRepo repository.UserRepository, db *database.MongoDB, store JobStore, jobQueue *queue.Queue) Importer {
+	importer := &importerImpl{
+		userService: userService,
+		userRepo:    userRepo,
+		store:       store,
+		queue:       jobQueue,
+	}
+	if db != nil {
+		importer.bucket = db.Database.GridFSBucket(options.GridFSBucket().SetName(uploadBucket))
+	}
+	return importer
+}
+
+func (i *importerImpl) Run(ctx context.Context, r io.Reader, opts Options, onProgress func(*dto.ImportJob)) (*dto.ImportJob, error) {
+	rows, err := ParseRows(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	job := newJob(opts, len(rows))
+	err = i.process(ctx, job, rows, opts, onProgress)
+	return job, err
+}
+
+func (i *importerImpl) Start(ctx context.Context, data []byte, opts Options) (*dto.ImportJob, error) {
+	// Parse up front so broken files are rejected before a job is created
+	rows, err := ParseRows(bytes.NewReader(data), opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	job := newJob(opts, len(rows))
+	if err := i.bucket.UploadFromStreamWithID(ctx, job.ID, job.ID, bytes.NewReader(data)); err != nil {
+		logger.Error("failed to store import upload", zap.Error(err))
+		return nil, err
+	}
+	if err := i.store.Save(ctx, job); err != nil {
+		logger.Error("failed to save import job", zap.Error(err))
+		i.deleteUpload(ctx, job.ID)
+		return nil, err
+	}
+
+	payload := importPayload{JobID: job.ID, Format: opts.Format, DryRun: opts.DryRun}
+	if _, err := i.queue.Enqueue(ctx, JobType, payload, queue.EnqueueOptions{MaxAttempts: importMaxAttempts}); err != nil {
+		logger.Error("failed to queue import job", zap.Error(err), zap.String("job_id", job.ID))
+		i.deleteUpload(ctx, job.ID)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (i *importerImpl) RunJob(ctx context.Context, qj *queue.Job) error {
+	var payload importPayload
+	if err := qj.Decode(&payload); err != nil {
+		return queue.Permanent(err)
+	}
+
+	job, err := i.store.Get(ctx, payload.JobID)
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			// Expired before the worker got to it; nobody is polling anymore
+			i.deleteUpload(ctx, payload.JobID)
+			return queue.Permanent(err)
+		}
+		return err
+	}
+	defer i.deleteUpload(ctx, job.ID)
+
+	save := func(job *dto.ImportJob) {
+		if err := i.store.Save(ctx, job); err != nil {
+			logger.Error("failed to save import job progress", zap.Error(err), zap.String("job_id", job.ID))
+		}
+	}
+
+	rows, err := i.download(ctx, job.ID, payload.Format)
+	if err != nil {
+		job.Status = dto.JobStatusFailed
+		job.Error = err.Error()
+		job.FinishedAt = utils.FormatIndonesian(time.Now())
+		save(job)
+		return err
+	}
+
+	err = i.process(ctx, job, rows, Options{Format: payload.Format, DryRun: payload.DryRun}, save)
+	save(job)
+	return err
+}
+
+// download reads back and parses an upload stored by Start
+func (i *importerImpl) download(ctx context.Context, id string, format Format) ([]Row, error) {
+	stream, err := i.bucket.OpenDownloadStream(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrFileNotFound) {
+			return nil, queue.Permanent(fmt.Errorf("import upload is gone: %w", err))
+		}
+		return nil, err
+	}
+	defer stream.Close()
+
+	return ParseRows(stream, format)
+}
+
+// deleteUpload removes an upload once its import has run or can't run anymore
+func (i *importerImpl) deleteUpload(ctx context.Context, id string) {
+	if err := i.bucket.Delete(ctx, id); err != nil && !errors.Is(err, mongo.ErrFileNotFound) {
+		logger.Error("failed to delete import upload", zap.Error(err), zap.String("job_id", id))
+	}
+}
+
+func (i *importerImpl) Get(ctx context.Context, id string) (*dto.ImportJob, error) {
+	return i.store.Get(ctx, id)
+}
+
+func newJob(opts Options, total int) *dto.ImportJob {
+	return &dto.ImportJob{
+		ID:        bson.NewObjectID().Hex(),
+		Status:    dto.JobStatusQueued,
+		Format:    string(opts.Format),
+		DryRun:    opts.DryRun,
+		Total:     total,
+		CreatedAt: utils.FormatIndonesian(time.Now()),
+	}
+}
+
+// process validates and imports rows, updating job as it goes. Row problems
+// are recorded in the job; only infrastructure errors abort the import.
+func (i *importerImpl) process(ctx context.Context, job *dto.ImportJob, rows []Row, opts Options, onProgress func(*dto.ImportJob)) error {
+	job.Status = dto.JobStatusRunning
+	if onProgress != nil {
+		onProgress(job)
+	}
+
+	logger.Info("user import started",
+		zap.String("job_id", job.ID),
+		zap.Int("rows", job.Total),
+		zap.Bool("dry_run", opts.DryRun),
+	)
+
+	seen := map[string]int{}
+	for _, row := range rows {
+		problems, err := i.processRow(ctx, row, seen, opts.DryRun)
+		if err != nil {
+			job.Status = dto.JobStatusFailed
+			job.Error = fmt.Sprintf("import aborted at line %d: %v", row.Line, err)
+			job.FinishedAt = utils.FormatIndonesian(time.Now())
+			logger.Error("user import failed", zap.Error(err), zap.String("job_id", job.ID))
+			return err
+		}
+
+		job.Processed++
+		if len(problems) > 0 {
+			job.Failed++
+			job.Errors = append(job.Errors, dto.ImportRowError{
+				Line:   row.Line,
+				Email:  row.User.Email,
+				Errors: problems,
+			})
+		} else {
+			job.Succeeded++
+		}
+
+		if onProgress != nil && job.Processed%progressInterval == 0 {
+			onProgress(job)
+		}
+	}
+
+	job.Status = dto.JobStatusCompleted
+	job.FinishedAt = utils.FormatIndonesian(time.Now())
+	if onProgress != nil {
+		onProgress(job)
+	}
+
+	logger.Info("user import completed",
+		zap.String("job_id", job.ID),
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("failed", job.Failed),
+	)
+	return nil
+}
+
+// processRow returns the problems found in a row, or an error if the row
+// could not be checked at all
+func (i *importerImpl) processRow(ctx context.Context, row Row, seen map[string]int, dryRun bool) ([]string, error) {
+	if row.Err != nil {
+		return []string{"malformed record: " + row.Err.Error()}, nil
+	}
+
+	var problems []string
+	if err := validator.ValidateStruct(row.User); err != nil {
+		problems = append(problems, validator.ValidationMessages(err)...)
+	}
+
+	if row.User.Email != "" {
+		key := strings.ToLower(row.User.Email)
+		if first, ok := seen[key]; ok {
+			problems = append(problems, fmt.Sprintf("duplicate email, first used on line %d", first))
+		} else {
+			seen[key] = row.Line
+
+			exists, err := i.userRepo.ExistsByEmail(ctx, row.User.Email)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				problems = append(problems, "email already exists")
+			}
+		}
+	}
+
+	if len(problems) > 0 || dryRun {
+		return problems, nil
+	}
+
+	role := row.User.Role
+	if role == "" {
+		role = entity.RoleUser
+	}
+
+	_, err := i.userService.Create(ctx, &dto.CreateUserRequest{
+		Email:     row.User.Email,
+		Password:  row.User.Password,
+		FirstName: row.User.FirstName,
+		LastName:  row.User.LastName,
+		Role:      role,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyExists) {
+			return []string{"email already exists"}, nil
+		}
+		return nil, err
+	}
+
+	return nil, nil
+}
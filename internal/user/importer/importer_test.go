@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	auditMock "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service/mock"
+	eventMock "github.com/itsahyarr/gofiber-boilerplate/internal/event/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+)
+
+func TestRun_DryRunReport(t *testing.T) {
+	// 1. Setup Mock where one email is already registered
+	mockRepo := &mock.MockUserRepository{
+		ExistsByEmailFunc: func(ctx context.Context, email string) (bool, error) {
+			return email == "taken@example.com", nil
+		},
+	}
+	userImporter := NewImporter(service.NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{}), mockRepo, nil, nil, nil)
+
+	file := strings.Join([]string{
+		"email,password,first_name,last_name,role",
+		"jane@example.com,password123,Jane,Doe,user",
+		"taken@example.com,password123,Taken,User,",
+		"JANE@example.com,password123,Jane,Again,",
+		"not-an-email,short,J,Doe,OWNER",
+	}, "\n")
+
+	// 2. Call Method
+	job, err := userImporter.Run(context.Background(), strings.NewReader(file), Options{Format: FormatCSV, DryRun: true}, nil)
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, dto.JobStatusCompleted, job.Status)
+	assert.Equal(t, 4, job.Total)
+	assert.Equal(t, 1, job.Succeeded)
+	assert.Equal(t, 3, job.Failed)
+
+	assert.Equal(t, 3, job.Errors[0].Line)
+	assert.Equal(t, []string{"email already exists"}, job.Errors[0].Errors)
+	assert.Equal(t, []string{"duplicate email, first used on line 2"}, job.Errors[1].Errors)
+	assert.Len(t, job.Errors[2].Errors, 4)
+}
+
+func TestParseRows_NDJSON(t *testing.T) {
+	file := `{"email":"jane@example.com","password":"password123","firstName":"Jane","lastName":"Doe"}
+
+{"email":"john@example.com","unknown":true}
+`
+
+	rows, err := ParseRows(strings.NewReader(file), FormatNDJSON)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.NoError(t, rows[0].Err)
+	assert.Equal(t, "Jane", rows[0].User.FirstName)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Error(t, rows[1].Err)
+}
+
+func TestParseRows_MissingColumns(t *testing.T) {
+	_, err := ParseRows(strings.NewReader("email,firstName\n"), FormatCSV)
+
+	assert.ErrorIs(t, err, ErrMissingColumns)
+}
+
+func TestParseRows_MalformedCSV(t *testing.T) {
+	file := strings.Join([]string{
+		"email,password,firstName,lastName",
+		`ja"ne@example.com,password123,Jane,Doe`,
+		"john@example.com,password123,John",
+		"joe@example.com,password123,Joe,Doe",
+	}, "\n")
+
+	rows, err := ParseRows(strings.NewReader(file), FormatCSV)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.ErrorIs(t, rows[0].Err, csv.ErrBareQuote)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.ErrorIs(t, rows[1].Err, csv.ErrFieldCount)
+	assert.Equal(t, 4, rows[2].Line)
+	assert.NoError(t, rows[2].Err)
+	assert.Equal(t, "Joe", rows[2].User.FirstName)
+}
@@ -0,0 +1,165 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// Format is the encoding of an import file
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported import format, use csv or ndjson")
+	ErrMissingColumns    = errors.New("csv header must contain email, password, firstName and lastName columns")
+)
+
+// Row is one parsed record of an import file. Err is set when the record
+// could not be decoded at all.
+type Row struct {
+	Line int
+	User dto.ImportUserRow
+	Err  error
+}
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON, "jsonl":
+		return FormatNDJSON, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// FormatFromFilename guesses the format from a file extension
+func FormatFromFilename(filename string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
+
+// ParseRows decodes every record of an import file. Malformed records are
+// returned with Err set so they show up in the report instead of aborting
+// the whole import.
+func ParseRows(r io.Reader, format Format) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatNDJSON:
+		return parseNDJSON(r)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// csvColumns maps normalized header names to ImportUserRow fields
+var csvColumns = map[string]func(row *dto.ImportUserRow, value string){
+	"email":     func(row *dto.ImportUserRow, v string) { row.Email = v },
+	"password":  func(row *dto.ImportUserRow, v string) { row.Password = v },
+	"firstname": func(row *dto.ImportUserRow, v string) { row.FirstName = v },
+	"lastname":  func(row *dto.ImportUserRow, v string) { row.LastName = v },
+	"role":      func(row *dto.ImportUserRow, v string) { row.Role = entity.Role(strings.ToUpper(v)) },
+}
+
+// normalizeColumn lets headers be written as firstName, first_name or first-name
+func normalizeColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(name)
+}
+
+func parseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	setters := make([]func(*dto.ImportUserRow, string), len(header))
+	seen := map[string]bool{}
+	for i, name := range header {
+		column := normalizeColumn(strings.TrimPrefix(name, "\ufeff"))
+		setters[i] = csvColumns[column]
+		seen[column] = true
+	}
+	for _, required := range []string{"email", "password", "firstname", "lastname"} {
+		if !seen[required] {
+			return nil, ErrMissingColumns
+		}
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			// FieldPos must not be called after a failed read
+			rows = append(rows, Row{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := Row{Line: line}
+		for i, value := range record {
+			if setters[i] != nil {
+				setters[i](&row.User, strings.TrimSpace(value))
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseNDJSON(r io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []Row
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := Row{Line: line}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row.User); err != nil {
+			row.Err = err
+		}
+		row.User.Role = entity.Role(strings.ToUpper(string(row.User.Role)))
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+)
+
+const (
+	importJobPrefix = "user_import:"
+	importJobTTL    = 24 * time.Hour
+)
+
+var ErrJobNotFound = errors.New("import job not found")
+
+// JobStore persists import job progress so any replica can answer polls
+type JobStore interface {
+	Save(ctx context.Context, job *dto.ImportJob) error
+	Get(ctx context.Context, id string) (*dto.ImportJob, error)
+}
+
+type jobStoreRedis struct {
+	redis *database.Redis
+}
+
+// NewJobStore creates a Redis-backed import job store
+func NewJobStore(redis *database.Redis) JobStore {
+	return &jobStoreRedis{
+		redis: redis,
+	}
+}
+
+func (s *jobStoreRedis) Save(ctx context.Context, job *dto.ImportJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.redis.Client.Set(ctx, importJobPrefix+job.ID, data, importJobTTL).Err()
+}
+
+func (s *jobStoreRedis) Get(ctx context.Context, id string) (*dto.ImportJob, error) {
+	data, err := s.redis.Client.Get(ctx, importJobPrefix+id).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	var job dto.ImportJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
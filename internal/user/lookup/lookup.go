@@ -0,0 +1,144 @@
+package lookup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+const lookupCachePrefix = "user_lookup:"
+
+// Lookup answers user picker queries. Results are cached briefly per scope
+// and prefix, since pickers query on every keystroke.
+type Lookup interface {
+	// Find returns up to limit users visible to the actor whose name or
+	// email starts with prefix. Admins see every user; other users only see
+	// active members of their own team, or just themselves without a team.
+	Find(ctx context.Context, actorID string, actorRole entity.Role, prefix string, limit int) ([]dto.UserLookupResponse, error)
+}
+
+type lookupImpl struct {
+	userService service.UserService
+	redis       *database.Redis
+	ttl         time.Duration
+}
+
+// NewLookup creates a user lookup cached in Redis for ttl
+func NewLookup(userService service.UserService, redis *database.Redis, ttl time.Duration) Lookup {
+	return &lookupImpl{
+		userService: userService,
+		redis:       redis,
+		ttl:         ttl,
+	}
+}
+
+func (l *lookupImpl) Find(ctx context.Context, actorID string, actorRole entity.Role, prefix string, limit int) ([]dto.UserLookupResponse, error) {
+	scopeKey, filter, err := l.scope(ctx, actorID, actorRole)
+	if err != nil {
+		return nil, err
+	}
+
+	// Case and spacing don't change the matches, so they share a cache entry
+	normalized := strings.ToLower(strings.Join(strings.Fields(prefix), " "))
+	key := cacheKey(scopeKey, normalized, limit)
+
+	if cached, ok := l.cached(ctx, key); ok {
+		return cached, nil
+	}
+
+	users, err := l.userService.Suggest(ctx, normalized, filter, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dto.UserLookupResponse, len(users))
+	for i, user := range users {
+		results[i] = dto.ToUserLookupResponse(user)
+	}
+	l.store(ctx, key, results)
+
+	return results, nil
+}
+
+// scope returns the filter limiting which users the actor may see, and a key
+// identifying it in the cache
+func (l *lookupImpl) scope(ctx context.Context, actorID string, actorRole entity.Role) (string, bson.M, error) {
+	if actorRole == entity.RoleAdmin {
+		return "all", nil, nil
+	}
+
+	team, err := l.actorTeam(ctx, actorID)
+	if err != nil {
+		return "", nil, err
+	}
+	if team != "" {
+		return "team:" + team, bson.M{"team": team, "isActive": true}, nil
+	}
+
+	id, err := bson.ObjectIDFromHex(actorID)
+	if err != nil {
+		return "", nil, service.ErrUserNotFound
+	}
+	return "user:" + actorID, bson.M{"_id": id}, nil
+}
+
+// actorTeam returns the actor's team. It is not cached here: the user cache
+// already serves repeated loads and is invalidated when an admin changes the
+// actor's team, which must take effect on the next keystroke.
+func (l *lookupImpl) actorTeam(ctx context.Context, actorID string) (string, error) {
+	actor, err := l.userService.GetByID(ctx, actorID)
+	if err != nil {
+		return "", err
+	}
+	return actor.Team, nil
+}
+
+// cacheKey hashes the lookup so team names and prefixes of any length or
+// content yield a fixed-size key
+func cacheKey(scopeKey, prefix string, limit int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s", scopeKey, limit, prefix)))
+	return lookupCachePrefix + hex.EncodeToString(sum[:])
+}
+
+// cached reads a cached result. Cache failures are logged and treated as a
+// miss so lookups keep working without Redis.
+func (l *lookupImpl) cached(ctx context.Context, key string) ([]dto.UserLookupResponse, bool) {
+	data, err := l.redis.Client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			logger.Warn("failed to read user lookup cache", zap.Error(err))
+		}
+		return nil, false
+	}
+
+	var results []dto.UserLookupResponse
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, false
+	}
+	return results, true
+}
+
+func (l *lookupImpl) store(ctx context.Context, key string, results []dto.UserLookupResponse) {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return
+	}
+	if err := l.redis.Client.Set(ctx, key, data, l.ttl).Err(); err != nil {
+		logger.Warn("failed to write user lookup cache", zap.Error(err))
+	}
+}
@@ -0,0 +1,138 @@
+package lookup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	auditMock "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service/mock"
+	eventMock "github.com/itsahyarr/gofiber-boilerplate/internal/event/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/search"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// scopeRecorder is a user repository that records the filter of every prefix
+// lookup and how often the actor was loaded
+type scopeRecorder struct {
+	actor   *entity.User
+	filters []bson.M
+	loads   int
+}
+
+func (r *scopeRecorder) repository() *mock.MockUserRepository {
+	return &mock.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.User, error) {
+			r.loads++
+			if r.actor == nil || r.actor.ID.Hex() != id {
+				return nil, repository.ErrUserNotFound
+			}
+			return r.actor, nil
+		},
+		FindByPrefixFunc: func(ctx context.Context, prefix string, filter bson.M, limit int) ([]*entity.User, error) {
+			r.filters = append(r.filters, filter)
+			return []*entity.User{{ID: bson.NewObjectID(), FirstName: "Jane", LastName: "Doe", Email: "jane@example.com"}}, nil
+		},
+	}
+}
+
+func newTestLookup(t *testing.T, repo repository.UserRepository) Lookup {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	redisClient := &database.Redis{Client: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+	userSvc := service.NewUserService(repo, nil, search.NewMongoProvider(repo), database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+	return NewLookup(userSvc, redisClient, time.Minute)
+}
+
+func TestFind_AdminSeesEveryone(t *testing.T) {
+	// 1. Setup Mocks
+	recorder := &scopeRecorder{}
+	userLookup := newTestLookup(t, recorder.repository())
+
+	// 2. Call Method
+	results, err := userLookup.Find(context.Background(), bson.NewObjectID().Hex(), entity.RoleAdmin, "ja", 10)
+
+	// 3. Assertions: admins are not loaded and not filtered
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", results[0].Name)
+	assert.Zero(t, recorder.loads)
+	assert.Equal(t, []bson.M{nil}, recorder.filters)
+}
+
+func TestFind_TeamMemberSeesActiveTeammates(t *testing.T) {
+	// 1. Setup Mocks
+	actor := &entity.User{ID: bson.NewObjectID(), Role: entity.RoleUser, Team: "platform"}
+	recorder := &scopeRecorder{actor: actor}
+	userLookup := newTestLookup(t, recorder.repository())
+
+	// 2. Call Method for two keystrokes
+	_, err := userLookup.Find(context.Background(), actor.ID.Hex(), entity.RoleUser, "j", 10)
+	require.NoError(t, err)
+	_, err = userLookup.Find(context.Background(), actor.ID.Hex(), entity.RoleUser, "ja", 10)
+	require.NoError(t, err)
+
+	// 3. Assertions: the actor's team scopes both lookups
+	want := bson.M{"team": "platform", "isActive": true}
+	assert.Equal(t, []bson.M{want, want}, recorder.filters)
+}
+
+func TestFind_TeamChangeTakesEffect(t *testing.T) {
+	// 1. Setup Mocks
+	actor := &entity.User{ID: bson.NewObjectID(), Role: entity.RoleUser, Team: "platform"}
+	recorder := &scopeRecorder{actor: actor}
+	userLookup := newTestLookup(t, recorder.repository())
+
+	// 2. Call Method before and after an admin moves the actor
+	_, err := userLookup.Find(context.Background(), actor.ID.Hex(), entity.RoleUser, "ja", 10)
+	require.NoError(t, err)
+	actor.Team = "data"
+	_, err = userLookup.Find(context.Background(), actor.ID.Hex(), entity.RoleUser, "ja", 10)
+	require.NoError(t, err)
+
+	// 3. Assertions: the second lookup is scoped to the new team
+	assert.Equal(t, 2, recorder.loads)
+	assert.Equal(t, []bson.M{
+		{"team": "platform", "isActive": true},
+		{"team": "data", "isActive": true},
+	}, recorder.filters)
+}
+
+func TestFind_UserWithoutTeamSeesThemselves(t *testing.T) {
+	// 1. Setup Mocks
+	actor := &entity.User{ID: bson.NewObjectID(), Role: entity.RoleUser}
+	recorder := &scopeRecorder{actor: actor}
+	userLookup := newTestLookup(t, recorder.repository())
+
+	// 2. Call Method for two keystrokes
+	_, err := userLookup.Find(context.Background(), actor.ID.Hex(), entity.RoleUser, "j", 10)
+	require.NoError(t, err)
+	_, err = userLookup.Find(context.Background(), actor.ID.Hex(), entity.RoleUser, "ja", 10)
+	require.NoError(t, err)
+
+	// 3. Assertions
+	want := bson.M{"_id": actor.ID}
+	assert.Equal(t, []bson.M{want, want}, recorder.filters)
+}
+
+func TestFind_UnknownActor(t *testing.T) {
+	// 1. Setup Mocks
+	recorder := &scopeRecorder{}
+	userLookup := newTestLookup(t, recorder.repository())
+
+	// 2. Call Method
+	_, err := userLookup.Find(context.Background(), bson.NewObjectID().Hex(), entity.RoleUser, "ja", 10)
+
+	// 3. Assertions
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+	assert.Empty(t, recorder.filters)
+}
@@ -0,0 +1,127 @@
+package user
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/database/migration"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+)
+
+// caseInsensitive matches the collation used by the repository's prefix lookups
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+// Migrations returns the user module's database migrations. Never edit one
+// that has shipped; add a new version instead.
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		migration.CreateIndexes(2026101901, "users_email_unique", "users",
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		),
+		migration.CreateIndexes(2026101902, "users_deleted_at", "users",
+			mongo.IndexModel{
+				// Supports the scheduled purge of soft-deleted users
+				Keys:    bson.D{{Key: "deletedAt", Value: 1}},
+				Options: options.Index().SetSparse(true),
+			},
+		),
+		migration.CreateIndexes(2026101903, "users_keyset", "users",
+			mongo.IndexModel{
+				// Supports keyset (cursor) pagination, newest first
+				Keys: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+			},
+		),
+		migration.CreateIndexes(2026101904, "users_search", "users",
+			mongo.IndexModel{
+				// Full-text search over names and email. Names are not stemmed.
+				Keys: bson.D{
+					{Key: "firstName", Value: "text"},
+					{Key: "lastName", Value: "text"},
+					{Key: "email", Value: "text"},
+				},
+				Options: options.Index().
+					SetName("user_text_search").
+					SetWeights(bson.D{{Key: "firstName", Value: 10}, {Key: "lastName", Value: 10}, {Key: "email", Value: 5}}).
+					SetDefaultLanguage("none"),
+			},
+			mongo.IndexModel{
+				// Case-insensitive prefix (typeahead) lookups, see FindByPrefix
+				Keys:    bson.D{{Key: "firstName", Value: 1}},
+				Options: options.Index().SetName("firstName_ci").SetCollation(caseInsensitive),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "lastName", Value: 1}},
+				Options: options.Index().SetName("lastName_ci").SetCollation(caseInsensitive),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetName("email_ci").SetCollation(caseInsensitive),
+			},
+		),
+		migration.CreateIndexes(2026101905, "users_team", "users",
+			mongo.IndexModel{
+				// Scopes non-admin lookups to a team; collated like the prefix indexes
+				Keys:    bson.D{{Key: "team", Value: 1}},
+				Options: options.Index().SetName("team_ci").SetSparse(true).SetCollation(caseInsensitive),
+			},
+		),
+		migration.CreateIndexes(2026101906, "user_stats_user_id_unique", "user_stats",
+			mongo.IndexModel{
+				// Supports embedding stats into user responses with include=stats
+				Keys:    bson.D{{Key: "user_id", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		),
+		migration.CreateIndexes(2026101914, "users_activity", "users",
+			mongo.IndexModel{
+				// Finds dormant users: inactive-days and sorting by last-seen-at
+				Keys: bson.D{{Key: "lastSeenAt", Value: 1}, {Key: "_id", Value: 1}},
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "lastLoginAt", Value: 1}, {Key: "_id", Value: 1}},
+			},
+		),
+		{
+			// Users created before activity tracking have no loginCount, which
+			// login-count filters such as login-count[eq]=0 would miss
+			Version:    2026101916,
+			Name:       "users_backfill_login_count",
+			Definition: bson.D{{Key: "set", Value: bson.D{{Key: "loginCount", Value: 0}}}, {Key: "where", Value: "missing"}},
+			Up: func(ctx context.Context, db *database.MongoDB) error {
+				_, err := db.Collection("users").UpdateMany(ctx,
+					bson.M{"loginCount": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"loginCount": 0}},
+				)
+				return err
+			},
+			// A zero count is valid for every user, so it is left in place
+			Down: func(ctx context.Context, db *database.MongoDB) error {
+				return nil
+			},
+		},
+		{
+			// Users created before optimistic locking have no version, which
+			// would leave conditional updates with nothing to match against
+			Version:    2026101917,
+			Name:       "users_backfill_version",
+			Definition: bson.D{{Key: "set", Value: bson.D{{Key: "version", Value: 1}}}, {Key: "where", Value: "missing"}},
+			Up: func(ctx context.Context, db *database.MongoDB) error {
+				_, err := db.Collection("users").UpdateMany(ctx,
+					bson.M{"version": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"version": 1}},
+				)
+				return err
+			},
+			// Every user starts at version 1, so it is left in place
+			Down: func(ctx context.Context, db *database.MongoDB) error {
+				return nil
+			},
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+
+	emailDto "github.com/itsahyarr/gofiber-boilerplate/internal/email/dto"
+	emailService "github.com/itsahyarr/gofiber-boilerplate/internal/email/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+	notificationDto "github.com/itsahyarr/gofiber-boilerplate/internal/notification/dto"
+	notificationService "github.com/itsahyarr/gofiber-boilerplate/internal/notification/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/events"
+)
+
+// WelcomeTemplate is the email sent to users who registered themselves
+const WelcomeTemplate = "welcome"
+
+// SubscribeNotifications subscribes the user module's email and in-app
+// notifications to the event dispatcher
+func SubscribeNotifications(dispatcher *event.Dispatcher, emailService emailService.EmailService, notificationService notificationService.NotificationService, appName string) {
+	dispatcher.Subscribe(events.TypeRegistered, func(ctx context.Context, evt event.Event) error {
+		var payload events.UserPayload
+		if err := evt.Decode(&payload); err != nil {
+			return err
+		}
+
+		// Keyed by event, so a redelivered event doesn't send a second email
+		_, err := emailService.Send(ctx, &emailDto.SendEmailRequest{
+			To:       payload.User.Email,
+			Template: WelcomeTemplate,
+			Data: map[string]any{
+				"AppName":   appName,
+				"FirstName": payload.User.FirstName,
+				"Email":     payload.User.Email,
+			},
+			Key: WelcomeTemplate + ":" + evt.ID,
+		})
+		return err
+	})
+
+	dispatcher.Subscribe(events.TypePasswordChanged, func(ctx context.Context, evt event.Event) error {
+		return notificationService.Notify(ctx, evt.AggregateID, &notificationDto.CreateNotificationRequest{
+			Type:  events.TypePasswordChanged,
+			Title: "Your password was changed",
+			Body:  "If you didn't change it, reset your password and contact an administrator.",
+			Key:   evt.ID,
+		})
+	})
+}
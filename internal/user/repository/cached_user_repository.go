@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+const (
+	userCacheIDPrefix    = "user_cache:id:"
+	userCacheEmailPrefix = "user_cache:email:"
+
+	// userCacheTombstone replaces an invalidated user for
+	// userCacheTombstoneTTL, so a miss that read the user before the write
+	// cannot cache the stale copy once the write is done
+	userCacheTombstone    = "-"
+	userCacheTombstoneTTL = 10 * time.Second
+)
+
+// cacheMetrics exposes hit, miss and error counts of the user cache through
+// expvar (served at /debug/vars)
+var cacheMetrics = expvar.NewMap("user_repository_cache")
+
+// cachedUserRepository caches FindByID and FindByEmail in Redis in front of
+// another UserRepository. Every other method is passed through; writes
+// invalidate the cached user afterwards, or once their transaction commits.
+//
+// Users are cached as BSON rather than JSON so the password hash, which is
+// excluded from JSON, survives the round trip for login. Email keys only hold
+// the user ID, since emails never change while the ID entry is invalidated.
+//
+// Misses only fill an empty key and invalidation leaves a short-lived
+// tombstone, so a read racing a write never caches what the write replaced.
+type cachedUserRepository struct {
+	UserRepository
+	redis *database.Redis
+	ttl   time.Duration
+	group singleflight.Group
+}
+
+// NewCachedUserRepository wraps next with a Redis read-through cache whose
+// entries live for ttl
+func NewCachedUserRepository(next UserRepository, redis *database.Redis, ttl time.Duration) UserRepository {
+	return &cachedUserRepository{
+		UserRepository: next,
+		redis:          redis,
+		ttl:            ttl,
+	}
+}
+
+func (r *cachedUserRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
+	// Reads inside a transaction may see uncommitted writes, never cache those
+	if mongo.SessionFromContext(ctx) != nil {
+		return r.UserRepository.FindByID(ctx, id)
+	}
+
+	if user, ok := r.get(ctx, userCacheIDPrefix+id); ok {
+		return user, nil
+	}
+
+	// Concurrent misses for the same user share one database read
+	v, err, _ := r.group.Do(id, func() (any, error) {
+		// Shared by every waiting caller, so one caller giving up must not fail the rest
+		ctx := context.WithoutCancel(ctx)
+		user, err := r.UserRepository.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		r.set(ctx, userCacheIDPrefix+id, user)
+		return user, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user := *v.(*entity.User)
+	return &user, nil
+}
+
+func (r *cachedUserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	if mongo.SessionFromContext(ctx) != nil {
+		return r.UserRepository.FindByEmail(ctx, email)
+	}
+
+	id, err := r.redis.Client.Get(ctx, userCacheEmailPrefix+email).Result()
+	switch {
+	case err == nil:
+		user, err := r.FindByID(ctx, id)
+		if err == nil {
+			return user, nil
+		}
+		// The user behind the email is gone, e.g. purged and re-registered
+		r.delete(ctx, userCacheEmailPrefix+email)
+	case errors.Is(err, redis.Nil):
+		cacheMetrics.Add("misses", 1)
+	default:
+		r.recordError("read", err)
+	}
+
+	v, err, _ := r.group.Do("email:"+email, func() (any, error) {
+		ctx := context.WithoutCancel(ctx)
+		user, err := r.UserRepository.FindByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		id := user.ID.Hex()
+		r.set(ctx, userCacheIDPrefix+id, user)
+		if err := r.redis.Client.Set(ctx, userCacheEmailPrefix+email, id, r.ttl).Err(); err != nil {
+			r.recordError("write", err)
+		}
+		return user, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user := *v.(*entity.User)
+	return &user, nil
+}
+
+func (r *cachedUserRepository) Update(ctx context.Context, id string, expectedVersion int64, update *UserUpdate) (*entity.User, error) {
+	user, err := r.UserRepository.Update(ctx, id, expectedVersion, update)
+	r.invalidate(ctx, id)
+	return user, err
+}
+
+func (r *cachedUserRepository) Delete(ctx context.Context, id string) error {
+	err := r.UserRepository.Delete(ctx, id)
+	r.invalidate(ctx, id)
+	return err
+}
+
+func (r *cachedUserRepository) Restore(ctx context.Context, id string) error {
+	err := r.UserRepository.Restore(ctx, id)
+	r.invalidate(ctx, id)
+	return err
+}
+
+func (r *cachedUserRepository) RecordLogin(ctx context.Context, id string, at time.Time) error {
+	err := r.UserRepository.RecordLogin(ctx, id, at)
+	r.invalidate(ctx, id)
+	return err
+}
+
+func (r *cachedUserRepository) TouchLastSeen(ctx context.Context, id string, at time.Time, interval time.Duration) error {
+	err := r.UserRepository.TouchLastSeen(ctx, id, at, interval)
+	r.invalidate(ctx, id)
+	return err
+}
+
+// get reads a cached user. Cache failures are logged and treated as misses
+// so requests keep working without Redis.
+func (r *cachedUserRepository) get(ctx context.Context, key string) (*entity.User, bool) {
+	data, err := r.redis.Client.Get(ctx, key).Bytes()
+	if err != nil || string(data) == userCacheTombstone {
+		if err != nil && !errors.Is(err, redis.Nil) {
+			r.recordError("read", err)
+		}
+		cacheMetrics.Add("misses", 1)
+		return nil, false
+	}
+
+	var user entity.User
+	if err := bson.Unmarshal(data, &user); err != nil {
+		r.recordError("decode", err)
+		cacheMetrics.Add("misses", 1)
+		return nil, false
+	}
+
+	cacheMetrics.Add("hits", 1)
+	return &user, true
+}
+
+func (r *cachedUserRepository) set(ctx context.Context, key string, user *entity.User) {
+	data, err := bson.Marshal(user)
+	if err != nil {
+		r.recordError("encode", err)
+		return
+	}
+	// A tombstone means the user was written since the miss began
+	if err := r.redis.Client.SetNX(ctx, key, data, r.ttl).Err(); err != nil {
+		r.recordError("write", err)
+	}
+}
+
+// delete invalidates a key. It runs even when the write failed, since the
+// write may still have been applied.
+func (r *cachedUserRepository) delete(ctx context.Context, key string) {
+	if err := r.redis.Client.Del(ctx, key).Err(); err != nil {
+		r.recordError("invalidate", err)
+	}
+}
+
+// invalidate replaces a cached user with a tombstone. Like delete, it runs
+// even when the write failed. Inside a transaction it waits for the commit,
+// since a read in between would cache the user from before the write.
+func (r *cachedUserRepository) invalidate(ctx context.Context, id string) {
+	database.AfterCommit(ctx, func(ctx context.Context) {
+		if err := r.redis.Client.Set(ctx, userCacheIDPrefix+id, userCacheTombstone, userCacheTombstoneTTL).Err(); err != nil {
+			r.recordError("invalidate", err)
+		}
+	})
+}
+
+func (r *cachedUserRepository) recordError(op string, err error) {
+	cacheMetrics.Add("errors", 1)
+	logger.Warn("user cache "+op+" failed", zap.Error(err))
+}
@@ -0,0 +1,178 @@
+package repository_test
+
+import (
+	"context"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// unreachableRedis fails every command immediately
+func unreachableRedis() *database.Redis {
+	return &database.Redis{Client: redis.NewClient(&redis.Options{
+		Addr:               "127.0.0.1:1",
+		MaxRetries:         -1,
+		DialerRetries:      1,
+		DialerRetryTimeout: time.Millisecond,
+	})}
+}
+
+func testRedis(t *testing.T) *database.Redis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	return &database.Redis{Client: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+}
+
+// countingRepository serves one user and counts the database reads
+type countingRepository struct {
+	user  entity.User
+	reads int
+}
+
+func (r *countingRepository) mock() *mock.MockUserRepository {
+	return &mock.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.User, error) {
+			r.reads++
+			user := r.user
+			return &user, nil
+		},
+		UpdateFunc: func(ctx context.Context, id string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error) {
+			r.user.Version++
+			user := r.user
+			return &user, nil
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			return nil
+		},
+		RestoreFunc: func(ctx context.Context, id string) error {
+			return nil
+		},
+	}
+}
+
+func cacheMetric(name string) int64 {
+	v := expvar.Get("user_repository_cache").(*expvar.Map).Get(name)
+	if v == nil {
+		return 0
+	}
+	return v.(*expvar.Int).Value()
+}
+
+func TestCachedUserRepository_FallsBackWithoutRedis(t *testing.T) {
+	// 1. Setup Mock
+	counting := &countingRepository{user: entity.User{Email: "john@example.com"}}
+	cached := repository.NewCachedUserRepository(counting.mock(), unreachableRedis(), time.Minute)
+	errorsBefore := cacheMetric("errors")
+
+	// 2. Call Method twice
+	for range 2 {
+		user, err := cached.FindByID(context.Background(), "658bd7c1f1e29e0001bcdef0")
+		require.NoError(t, err)
+		assert.Equal(t, "john@example.com", user.Email)
+	}
+
+	// 3. Assertions: every read goes to the database and failures are counted
+	assert.Equal(t, 2, counting.reads)
+	assert.Greater(t, cacheMetric("errors"), errorsBefore)
+}
+
+func TestCachedUserRepository_HitsAndMisses(t *testing.T) {
+	// 1. Setup Mock
+	counting := &countingRepository{user: entity.User{ID: bson.NewObjectID(), Email: "john@example.com"}}
+	cached := repository.NewCachedUserRepository(counting.mock(), testRedis(t), time.Minute)
+	id := counting.user.ID.Hex()
+	hitsBefore, missesBefore := cacheMetric("hits"), cacheMetric("misses")
+
+	// 2. Call Method twice
+	_, err := cached.FindByID(context.Background(), id)
+	require.NoError(t, err)
+	user, err := cached.FindByID(context.Background(), id)
+	require.NoError(t, err)
+
+	// 3. Assertions: the second read is served from Redis
+	assert.Equal(t, "john@example.com", user.Email)
+	assert.Equal(t, 1, counting.reads)
+	assert.Equal(t, hitsBefore+1, cacheMetric("hits"))
+	assert.Equal(t, missesBefore+1, cacheMetric("misses"))
+}
+
+func TestCachedUserRepository_WritesInvalidate(t *testing.T) {
+	writes := map[string]func(repo repository.UserRepository, id string) error{
+		"update": func(repo repository.UserRepository, id string) error {
+			_, err := repo.Update(context.Background(), id, repository.AnyVersion, repository.NewUserUpdate().Set(repository.FieldFirstName, "Jane"))
+			return err
+		},
+		"delete": func(repo repository.UserRepository, id string) error {
+			return repo.Delete(context.Background(), id)
+		},
+		"restore": func(repo repository.UserRepository, id string) error {
+			return repo.Restore(context.Background(), id)
+		},
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			// 1. Setup Mock with a cached user
+			counting := &countingRepository{user: entity.User{ID: bson.NewObjectID()}}
+			cached := repository.NewCachedUserRepository(counting.mock(), testRedis(t), time.Minute)
+			id := counting.user.ID.Hex()
+			_, err := cached.FindByID(context.Background(), id)
+			require.NoError(t, err)
+
+			// 2. Call Method
+			require.NoError(t, write(cached, id))
+			_, err = cached.FindByID(context.Background(), id)
+			require.NoError(t, err)
+
+			// 3. Assertions: the user is read again after the write
+			assert.Equal(t, 2, counting.reads)
+		})
+	}
+}
+
+func TestCachedUserRepository_MissRacingUpdate(t *testing.T) {
+	// 1. Setup Mock whose first read returns the user before an update that
+	// completes while the read is in flight
+	id := bson.NewObjectID()
+	var cached repository.UserRepository
+	version := int64(1)
+	reads := 0
+	mockRepo := &mock.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, hex string) (*entity.User, error) {
+			reads++
+			user := &entity.User{ID: id, Version: version}
+			if reads == 1 {
+				_, err := cached.Update(ctx, hex, version, repository.NewUserUpdate())
+				require.NoError(t, err)
+			}
+			return user, nil
+		},
+		UpdateFunc: func(ctx context.Context, id string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error) {
+			version++
+			return &entity.User{Version: version}, nil
+		},
+	}
+	cached = repository.NewCachedUserRepository(mockRepo, testRedis(t), time.Minute)
+
+	// 2. Call Method
+	stale, err := cached.FindByID(context.Background(), id.Hex())
+	require.NoError(t, err)
+	fresh, err := cached.FindByID(context.Background(), id.Hex())
+	require.NoError(t, err)
+
+	// 3. Assertions: the stale copy was not cached
+	assert.Equal(t, int64(1), stale.Version)
+	assert.Equal(t, int64(2), fresh.Version)
+	assert.Equal(t, 2, reads)
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MockUserStatsRepository is a manual mock for the UserStatsRepository interface
+type MockUserStatsRepository struct {
+	CreateFunc              func(ctx context.Context, stats *entity.UserStats) error
+	FindByUserIDsFunc       func(ctx context.Context, userIDs []bson.ObjectID) (map[bson.ObjectID]*entity.UserStats, error)
+	IncrementLoginCountFunc func(ctx context.Context, userID bson.ObjectID) error
+	DeleteByUserIDsFunc     func(ctx context.Context, userIDs []bson.ObjectID) error
+}
+
+func (m *MockUserStatsRepository) Create(ctx context.Context, stats *entity.UserStats) error {
+	return m.CreateFunc(ctx, stats)
+}
+
+func (m *MockUserStatsRepository) FindByUserIDs(ctx context.Context, userIDs []bson.ObjectID) (map[bson.ObjectID]*entity.UserStats, error) {
+	return m.FindByUserIDsFunc(ctx, userIDs)
+}
+
+func (m *MockUserStatsRepository) IncrementLoginCount(ctx context.Context, userID bson.ObjectID) error {
+	return m.IncrementLoginCountFunc(ctx, userID)
+}
+
+func (m *MockUserStatsRepository) DeleteByUserIDs(ctx context.Context, userIDs []bson.ObjectID) error {
+	return m.DeleteByUserIDsFunc(ctx, userIDs)
+}
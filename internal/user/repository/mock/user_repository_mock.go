@@ -2,20 +2,33 @@ package mock
 
 import (
 	"context"
+	"time"
 
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
 	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 // MockUserRepository is a manual mock for the UserRepository interface
 type MockUserRepository struct {
-	CreateFunc        func(ctx context.Context, user *entity.User) error
-	FindByIDFunc      func(ctx context.Context, id string) (*entity.User, error)
-	FindByEmailFunc   func(ctx context.Context, email string) (*entity.User, error)
-	FindAllFunc       func(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.User, int64, error)
-	UpdateFunc        func(ctx context.Context, user *entity.User) error
-	DeleteFunc        func(ctx context.Context, id string) error
-	ExistsByEmailFunc func(ctx context.Context, email string) (bool, error)
+	CreateFunc            func(ctx context.Context, user *entity.User) error
+	FindByIDFunc          func(ctx context.Context, id string) (*entity.User, error)
+	FindByEmailFunc       func(ctx context.Context, email string) (*entity.User, error)
+	FindAllFunc           func(ctx context.Context, filter bson.M, opts repository.FindOptions, page, pageSize int) ([]*entity.User, int64, error)
+	FindDeletedFunc       func(ctx context.Context, page, pageSize int) ([]*entity.User, int64, error)
+	FindAllCursorFunc     func(ctx context.Context, filter bson.M, projection bson.M, query pagination.CursorQuery) ([]*entity.User, *pagination.CursorPage, error)
+	FindDeletedCursorFunc func(ctx context.Context, query pagination.CursorQuery) ([]*entity.User, *pagination.CursorPage, error)
+	SearchFunc            func(ctx context.Context, text string, filter bson.M, opts repository.FindOptions, page, pageSize int) ([]*entity.User, int64, error)
+	FindByPrefixFunc      func(ctx context.Context, prefix string, filter bson.M, limit int) ([]*entity.User, error)
+	StreamFunc            func(ctx context.Context, filter bson.M, fn func(user *entity.User) error) error
+	UpdateFunc            func(ctx context.Context, id string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error)
+	DeleteFunc            func(ctx context.Context, id string) error
+	RestoreFunc           func(ctx context.Context, id string) error
+	RecordLoginFunc       func(ctx context.Context, id string, at time.Time) error
+	TouchLastSeenFunc     func(ctx context.Context, id string, at time.Time, interval time.Duration) error
+	PurgeDeletedFunc      func(ctx context.Context, before time.Time) ([]bson.ObjectID, error)
+	ExistsByEmailFunc     func(ctx context.Context, email string) (bool, error)
 }
 
 func (m *MockUserRepository) Create(ctx context.Context, user *entity.User) error {
@@ -30,18 +43,58 @@ func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*en
 	return m.FindByEmailFunc(ctx, email)
 }
 
-func (m *MockUserRepository) FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.User, int64, error) {
-	return m.FindAllFunc(ctx, filter, page, pageSize)
+func (m *MockUserRepository) FindAll(ctx context.Context, filter bson.M, opts repository.FindOptions, page, pageSize int) ([]*entity.User, int64, error) {
+	return m.FindAllFunc(ctx, filter, opts, page, pageSize)
 }
 
-func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
-	return m.UpdateFunc(ctx, user)
+func (m *MockUserRepository) FindDeleted(ctx context.Context, page, pageSize int) ([]*entity.User, int64, error) {
+	return m.FindDeletedFunc(ctx, page, pageSize)
+}
+
+func (m *MockUserRepository) FindAllCursor(ctx context.Context, filter bson.M, projection bson.M, query pagination.CursorQuery) ([]*entity.User, *pagination.CursorPage, error) {
+	return m.FindAllCursorFunc(ctx, filter, projection, query)
+}
+
+func (m *MockUserRepository) FindDeletedCursor(ctx context.Context, query pagination.CursorQuery) ([]*entity.User, *pagination.CursorPage, error) {
+	return m.FindDeletedCursorFunc(ctx, query)
+}
+
+func (m *MockUserRepository) Search(ctx context.Context, text string, filter bson.M, opts repository.FindOptions, page, pageSize int) ([]*entity.User, int64, error) {
+	return m.SearchFunc(ctx, text, filter, opts, page, pageSize)
+}
+
+func (m *MockUserRepository) FindByPrefix(ctx context.Context, prefix string, filter bson.M, limit int) ([]*entity.User, error) {
+	return m.FindByPrefixFunc(ctx, prefix, filter, limit)
+}
+
+func (m *MockUserRepository) Stream(ctx context.Context, filter bson.M, fn func(user *entity.User) error) error {
+	return m.StreamFunc(ctx, filter, fn)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, id string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error) {
+	return m.UpdateFunc(ctx, id, expectedVersion, update)
 }
 
 func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
 	return m.DeleteFunc(ctx, id)
 }
 
+func (m *MockUserRepository) Restore(ctx context.Context, id string) error {
+	return m.RestoreFunc(ctx, id)
+}
+
+func (m *MockUserRepository) RecordLogin(ctx context.Context, id string, at time.Time) error {
+	return m.RecordLoginFunc(ctx, id, at)
+}
+
+func (m *MockUserRepository) TouchLastSeen(ctx context.Context, id string, at time.Time, interval time.Duration) error {
+	return m.TouchLastSeenFunc(ctx, id, at, interval)
+}
+
+func (m *MockUserRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]bson.ObjectID, error) {
+	return m.PurgeDeletedFunc(ctx, before)
+}
+
 func (m *MockUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	return m.ExistsByEmailFunc(ctx, email)
 }
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// UserStatsRepository defines the interface for user stats data access
+type UserStatsRepository interface {
+	Create(ctx context.Context, stats *entity.UserStats) error
+	FindByUserIDs(ctx context.Context, userIDs []bson.ObjectID) (map[bson.ObjectID]*entity.UserStats, error)
+	IncrementLoginCount(ctx context.Context, userID bson.ObjectID) error
+	DeleteByUserIDs(ctx context.Context, userIDs []bson.ObjectID) error
+}
+
+type userStatsRepositoryMongo struct {
+	collection *mongo.Collection
+}
+
+func NewUserStatsRepository(db *database.MongoDB) UserStatsRepository {
+	return &userStatsRepositoryMongo{
+		collection: db.Collection("user_stats"),
+	}
+}
+
+func (r *userStatsRepositoryMongo) Create(ctx context.Context, stats *entity.UserStats) error {
+	stats.ID = bson.NewObjectID()
+
+	_, err := r.collection.InsertOne(ctx, stats)
+	return err
+}
+
+// FindByUserIDs loads the stats of several users in one query, keyed by user
+// ID. Users without stats are missing from the map.
+func (r *userStatsRepositoryMongo) FindByUserIDs(ctx context.Context, userIDs []bson.ObjectID) (map[bson.ObjectID]*entity.UserStats, error) {
+	result := make(map[bson.ObjectID]*entity.UserStats, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var stats entity.UserStats
+		if err := cursor.Decode(&stats); err != nil {
+			return nil, err
+		}
+		result[stats.UserID] = &stats
+	}
+
+	return result, cursor.Err()
+}
+
+// IncrementLoginCount counts a login, creating the stats of users registered
+// without them
+func (r *userStatsRepositoryMongo) IncrementLoginCount(ctx context.Context, userID bson.ObjectID) error {
+	filter := bson.M{"user_id": userID}
+	update := bson.M{"$inc": bson.M{"login_count": 1}}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent login created the stats first; count on top of them
+		_, err = r.collection.UpdateOne(ctx, filter, update)
+	}
+	return err
+}
+
+// DeleteByUserIDs removes the stats of purged users
+func (r *userStatsRepositoryMongo) DeleteByUserIDs(ctx context.Context, userIDs []bson.ObjectID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": bson.M{"$in": userIDs}})
+	return err
+}
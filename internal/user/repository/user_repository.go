@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -10,29 +11,82 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
 	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrVersionConflict = errors.New("user was modified concurrently")
 )
 
+// AnyVersion passed to Update writes regardless of the stored version
+const AnyVersion int64 = -1
+
 // UserRepository defines the interface for user data access
 type UserRepository interface {
 	Create(ctx context.Context, user *entity.User) error
 	FindByID(ctx context.Context, id string) (*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
-	FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.User, int64, error)
-	Update(ctx context.Context, user *entity.User) error
+	FindAll(ctx context.Context, filter bson.M, opts FindOptions, page, pageSize int) ([]*entity.User, int64, error)
+	FindDeleted(ctx context.Context, page, pageSize int) ([]*entity.User, int64, error)
+	FindAllCursor(ctx context.Context, filter bson.M, projection bson.M, query pagination.CursorQuery) ([]*entity.User, *pagination.CursorPage, error)
+	FindDeletedCursor(ctx context.Context, query pagination.CursorQuery) ([]*entity.User, *pagination.CursorPage, error)
+	Search(ctx context.Context, text string, filter bson.M, opts FindOptions, page, pageSize int) ([]*entity.User, int64, error)
+	FindByPrefix(ctx context.Context, prefix string, filter bson.M, limit int) ([]*entity.User, error)
+	Stream(ctx context.Context, filter bson.M, fn func(user *entity.User) error) error
+	Update(ctx context.Context, id string, expectedVersion int64, update *UserUpdate) (*entity.User, error)
 	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+	RecordLogin(ctx context.Context, id string, at time.Time) error
+	TouchLastSeen(ctx context.Context, id string, at time.Time, interval time.Duration) error
+	PurgeDeleted(ctx context.Context, before time.Time) ([]bson.ObjectID, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 }
 
+// defaultSort lists the newest users first, with _id keeping pages stable
+var defaultSort = bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+
+// prefixCollation makes prefix lookups case-insensitive. It must match the
+// collation of the name and email prefix indexes for them to be used.
+var prefixCollation = &options.Collation{Locale: "en", Strength: 2}
+
+// userKeyset orders cursor pages newest first, matching the offset listing
+var userKeyset = pagination.Keyset{Field: "createdAt", Descending: true}
+
+// FindOptions controls the order and shape of listed users. An empty Sort
+// falls back to newest first and an empty Projection loads whole documents.
+type FindOptions struct {
+	Sort       bson.D
+	Projection bson.M
+}
+
 type userRepositoryMongo struct {
 	db         *database.MongoDB
 	collection *mongo.Collection
 }
 
+// TextFilter restricts filter to users matching text in the text index.
+// The text is handed to $text as a search string, never as a pattern.
+func TextFilter(filter bson.M, text string) bson.M {
+	scoped := bson.M{"$text": bson.M{"$search": text}}
+	for k, v := range filter {
+		scoped[k] = v
+	}
+	return scoped
+}
+
+// notDeleted scopes a filter to users that have not been soft-deleted.
+// Missing and null deletedAt both match, so documents created before soft
+// delete existed are treated as active.
+func notDeleted(filter bson.M) bson.M {
+	scoped := bson.M{"deletedAt": nil}
+	for k, v := range filter {
+		scoped[k] = v
+	}
+	return scoped
+}
+
 func NewUserRepository(db *database.MongoDB) UserRepository {
 	collection := db.Collection("users")
 
@@ -44,6 +98,7 @@ func NewUserRepository(db *database.MongoDB) UserRepository {
 
 func (r *userRepositoryMongo) Create(ctx context.Context, user *entity.User) error {
 	user.ID = bson.NewObjectID()
+	user.Version = 1
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
@@ -58,7 +113,7 @@ func (r *userRepositoryMongo) FindByID(ctx context.Context, id string) (*entity.
 	}
 
 	var user entity.User
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&user)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": objectID})).Decode(&user)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, ErrUserNotFound
@@ -71,7 +126,7 @@ func (r *userRepositoryMongo) FindByID(ctx context.Context, id string) (*entity.
 
 func (r *userRepositoryMongo) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"email": email})).Decode(&user)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, ErrUserNotFound
@@ -82,11 +137,109 @@ func (r *userRepositoryMongo) FindByEmail(ctx context.Context, email string) (*e
 	return &user, nil
 }
 
-func (r *userRepositoryMongo) FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.User, int64, error) {
+func (r *userRepositoryMongo) FindAll(ctx context.Context, filter bson.M, opts FindOptions, page, pageSize int) ([]*entity.User, int64, error) {
+	return r.find(ctx, notDeleted(filter), opts, page, pageSize)
+}
+
+func (r *userRepositoryMongo) FindDeleted(ctx context.Context, page, pageSize int) ([]*entity.User, int64, error) {
+	return r.find(ctx, bson.M{"deletedAt": bson.M{"$ne": nil}}, FindOptions{}, page, pageSize)
+}
+
+func (r *userRepositoryMongo) FindAllCursor(ctx context.Context, filter bson.M, projection bson.M, query pagination.CursorQuery) ([]*entity.User, *pagination.CursorPage, error) {
+	return r.findCursor(ctx, notDeleted(filter), projection, query)
+}
+
+func (r *userRepositoryMongo) FindDeletedCursor(ctx context.Context, query pagination.CursorQuery) ([]*entity.User, *pagination.CursorPage, error) {
+	return r.findCursor(ctx, bson.M{"deletedAt": bson.M{"$ne": nil}}, nil, query)
+}
+
+// Search returns a page of users matching text, most relevant first unless
+// opts has its own sort
+func (r *userRepositoryMongo) Search(ctx context.Context, text string, filter bson.M, opts FindOptions, page, pageSize int) ([]*entity.User, int64, error) {
+	if len(opts.Sort) == 0 {
+		opts.Sort = bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: -1}}
+	}
+	return r.find(ctx, notDeleted(TextFilter(filter, text)), opts, page, pageSize)
+}
+
+// FindByPrefix returns users matching filter whose first name, last name or
+// email starts with every whitespace separated term of prefix, ignoring case.
+// Terms are matched with index range scans rather than patterns.
+func (r *userRepositoryMongo) FindByPrefix(ctx context.Context, prefix string, filter bson.M, limit int) ([]*entity.User, error) {
+	terms := bson.A{}
+	for _, term := range strings.Fields(prefix) {
+		// U+FFFF sorts after every other character under ICU collation
+		between := bson.M{"$gte": term, "$lt": term + "\uffff"}
+		terms = append(terms, bson.M{"$or": bson.A{
+			bson.M{"firstName": between},
+			bson.M{"lastName": between},
+			bson.M{"email": between},
+		}})
+	}
+	if len(terms) == 0 {
+		return []*entity.User{}, nil
+	}
+
+	opts := options.Find().
+		SetCollation(prefixCollation).
+		SetSort(bson.D{{Key: "lastName", Value: 1}, {Key: "firstName", Value: 1}}).
+		SetLimit(int64(limit))
+
+	if len(filter) > 0 {
+		terms = append(terms, filter)
+	}
+
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{"$and": terms}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []*entity.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Stream calls fn for every user matching filter, decoding one document at a
+// time from the cursor. Iteration stops at the first error returned by fn.
+func (r *userRepositoryMongo) Stream(ctx context.Context, filter bson.M, fn func(user *entity.User) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, notDeleted(filter), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user entity.User
+		if err := cursor.Decode(&user); err != nil {
+			return err
+		}
+		if err := fn(&user); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func (r *userRepositoryMongo) find(ctx context.Context, filter bson.M, findOpts FindOptions, page, pageSize int) ([]*entity.User, int64, error) {
 	skip := int64((page - 1) * pageSize)
 	limit := int64(pageSize)
 
-	opts := options.Find().SetSkip(skip).SetLimit(limit).SetSort(bson.D{{Key: "created_at", Value: -1}})
+	sort := findOpts.Sort
+	if len(sort) == 0 {
+		sort = defaultSort
+	}
+
+	opts := options.Find().SetSkip(skip).SetLimit(limit).SetSort(sort)
+	if len(findOpts.Projection) > 0 {
+		opts.SetProjection(findOpts.Projection)
+	}
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
@@ -107,13 +260,102 @@ func (r *userRepositoryMongo) FindAll(ctx context.Context, filter bson.M, page,
 	return users, total, nil
 }
 
-func (r *userRepositoryMongo) Update(ctx context.Context, user *entity.User) error {
-	user.UpdatedAt = time.Now()
+// findCursor fetches one keyset page. Unlike find it never skips over earlier
+// pages, and only counts the matching documents when the caller asks for it.
+func (r *userRepositoryMongo) findCursor(ctx context.Context, filter bson.M, projection bson.M, query pagination.CursorQuery) ([]*entity.User, *pagination.CursorPage, error) {
+	pageFilter, sort, limit, reverse, err := userKeyset.Plan(filter, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := options.Find().SetSort(sort).SetLimit(limit)
+	if len(projection) > 0 {
+		// The cursor is built from the sort key, so it is always loaded
+		withKey := bson.M{userKeyset.Field: 1}
+		for k, v := range projection {
+			withKey[k] = v
+		}
+		opts.SetProjection(withKey)
+	}
+
+	cursor, err := r.collection.Find(ctx, pageFilter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []*entity.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, nil, err
+	}
+
+	users, page := pagination.Page(users, query, reverse, func(user *entity.User) pagination.Cursor {
+		return pagination.Cursor{Key: user.CreatedAt, ID: user.ID}
+	})
+
+	if query.WithTotal {
+		total, err := r.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		page.Total = &total
+	}
+
+	return users, page, nil
+}
+
+// Update applies only the fields in update and returns the updated user.
+// Unless expectedVersion is AnyVersion, the write is conditional on the
+// stored version, returning ErrVersionConflict when another writer got there
+// first.
+func (r *userRepositoryMongo) Update(ctx context.Context, id string, expectedVersion int64, update *UserUpdate) (*entity.User, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	filter := notDeleted(bson.M{"_id": objectID})
+	if expectedVersion != AnyVersion {
+		filter["version"] = expectedVersion
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var user entity.User
+	err = r.collection.FindOneAndUpdate(ctx, filter, update.document(time.Now()), opts).Decode(&user)
+	if err == nil {
+		return &user, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	if expectedVersion != AnyVersion {
+		count, err := r.collection.CountDocuments(ctx, notDeleted(bson.M{"_id": objectID}))
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, ErrVersionConflict
+		}
+	}
+	return nil, ErrUserNotFound
+}
 
+func (r *userRepositoryMongo) Delete(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	now := time.Now()
 	result, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": user.ID},
-		bson.M{"$set": user},
+		notDeleted(bson.M{"_id": objectID}),
+		bson.M{
+			"$set": bson.M{"deletedAt": now, "updatedAt": now},
+			"$inc": bson.M{"version": 1},
+		},
 	)
 	if err != nil {
 		return err
@@ -126,24 +368,112 @@ func (r *userRepositoryMongo) Update(ctx context.Context, user *entity.User) err
 	return nil
 }
 
-func (r *userRepositoryMongo) Delete(ctx context.Context, id string) error {
+func (r *userRepositoryMongo) Restore(ctx context.Context, id string) error {
 	objectID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
 		return ErrUserNotFound
 	}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "deletedAt": bson.M{"$ne": nil}},
+		bson.M{
+			"$unset": bson.M{"deletedAt": ""},
+			"$set":   bson.M{"updatedAt": time.Now()},
+			"$inc":   bson.M{"version": 1},
+		},
+	)
 	if err != nil {
 		return err
 	}
 
-	if result.DeletedCount == 0 {
+	if result.MatchedCount == 0 {
 		return ErrUserNotFound
 	}
 
 	return nil
 }
 
+// RecordLogin stamps lastLoginAt and lastSeenAt and counts the login. Like
+// TouchLastSeen it leaves version and updatedAt alone, so tracking activity
+// never conflicts with an edit.
+func (r *userRepositoryMongo) RecordLogin(ctx context.Context, id string, at time.Time) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": objectID}),
+		bson.M{
+			"$set": bson.M{"lastLoginAt": at, "lastSeenAt": at},
+			"$inc": bson.M{"loginCount": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// TouchLastSeen sets lastSeenAt to at unless it was already set within
+// interval before at, so replicas seeing the same user don't all write
+func (r *userRepositoryMongo) TouchLastSeen(ctx context.Context, id string, at time.Time, interval time.Duration) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	filter := notDeleted(bson.M{"_id": objectID})
+	filter["$or"] = bson.A{
+		bson.M{"lastSeenAt": nil},
+		bson.M{"lastSeenAt": bson.M{"$lte": at.Add(-interval)}},
+	}
+
+	_, err = r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"lastSeenAt": at}})
+	return err
+}
+
+// PurgeDeleted permanently removes users soft-deleted at or before before and
+// returns their IDs, so data kept alongside them can be removed too
+func (r *userRepositoryMongo) PurgeDeleted(ctx context.Context, before time.Time) ([]bson.ObjectID, error) {
+	filter := bson.M{"deletedAt": bson.M{"$lte": before}}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var purged []struct {
+		ID bson.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &purged); err != nil {
+		return nil, err
+	}
+	if len(purged) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]bson.ObjectID, len(purged))
+	for i, user := range purged {
+		ids[i] = user.ID
+	}
+
+	filter["_id"] = bson.M{"$in": ids}
+	if _, err := r.collection.DeleteMany(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ExistsByEmail also counts soft-deleted users, since the unique email index
+// still covers them until they are purged.
 func (r *userRepositoryMongo) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	count, err := r.collection.CountDocuments(ctx, bson.M{"email": email})
 	if err != nil {
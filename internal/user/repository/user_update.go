@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Updatable user document fields
+const (
+	FieldFirstName          = "firstName"
+	FieldLastName           = "lastName"
+	FieldPassword           = "password"
+	FieldRole               = "role"
+	FieldTeam               = "team"
+	FieldIsActive           = "isActive"
+	FieldMustChangePassword = "mustChangePassword"
+)
+
+// UserUpdate describes a partial update of a user document. Only the fields
+// added to it are written; updatedAt and version are maintained by the
+// repository.
+type UserUpdate struct {
+	set   bson.M
+	unset bson.M
+	inc   bson.M
+}
+
+// NewUserUpdate creates an empty update
+func NewUserUpdate() *UserUpdate {
+	return &UserUpdate{
+		set:   bson.M{},
+		unset: bson.M{},
+		inc:   bson.M{},
+	}
+}
+
+// Set assigns value to field
+func (u *UserUpdate) Set(field string, value any) *UserUpdate {
+	u.set[field] = value
+	return u
+}
+
+// Unset removes field from the document
+func (u *UserUpdate) Unset(field string) *UserUpdate {
+	u.unset[field] = ""
+	return u
+}
+
+// Inc increments a numeric field by delta
+func (u *UserUpdate) Inc(field string, delta int64) *UserUpdate {
+	u.inc[field] = delta
+	return u
+}
+
+// IsEmpty reports whether the update changes no fields
+func (u *UserUpdate) IsEmpty() bool {
+	return len(u.set) == 0 && len(u.unset) == 0 && len(u.inc) == 0
+}
+
+// Fields returns the names of the changed fields, sorted
+func (u *UserUpdate) Fields() []string {
+	fields := make([]string, 0, len(u.set)+len(u.unset)+len(u.inc))
+	for _, m := range []bson.M{u.set, u.unset, u.inc} {
+		for field := range m {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// document builds the Mongo update document, stamping updatedAt and bumping
+// the version alongside the requested changes
+func (u *UserUpdate) document(now any) bson.M {
+	set := bson.M{"updatedAt": now}
+	for k, v := range u.set {
+		set[k] = v
+	}
+
+	inc := bson.M{"version": int64(1)}
+	for k, v := range u.inc {
+		inc[k] = v
+	}
+
+	doc := bson.M{"$set": set, "$inc": inc}
+	if len(u.unset) > 0 {
+		doc["$unset"] = u.unset
+	}
+	return doc
+}
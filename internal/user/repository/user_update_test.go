@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestUserUpdate_Document(t *testing.T) {
+	now := time.Now()
+
+	doc := NewUserUpdate().
+		Set(FieldFirstName, "Jane").
+		Unset(FieldLastName).
+		document(now)
+
+	assert.Equal(t, bson.M{
+		"$set":   bson.M{"firstName": "Jane", "updatedAt": now},
+		"$unset": bson.M{"lastName": ""},
+		"$inc":   bson.M{"version": int64(1)},
+	}, doc)
+}
+
+func TestUserUpdate_IsEmpty(t *testing.T) {
+	assert.True(t, NewUserUpdate().IsEmpty())
+	assert.False(t, NewUserUpdate().Inc("loginCount", 1).IsEmpty())
+}
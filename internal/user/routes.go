@@ -16,13 +16,26 @@ func RegisterRoutes(router fiber.Router, h *handler.UserHandler, tokenMaker *tok
 	// User routes (authenticated)
 	users.Get("/me", h.GetCurrentUser)
 	users.Put("/me/password", h.ChangePassword)
-
-	// Admin-only routes
-	adminUsers := users.Group("", middleware.RequireRoles(entity.RoleAdmin))
-	adminUsers.Get("/", h.GetAllUsers)
-	adminUsers.Get("/:id", h.GetUserByID)
-	adminUsers.Delete("/:id", h.DeleteUser)
+	users.Get("/lookup", h.LookupUsers)
 
 	// User update - allow self-update or admin
 	users.Put("/:id", h.UpdateUser)
+	users.Patch("/:id", h.PatchUser)
+
+	// Admin-only routes. The role check is added per route: a group middleware
+	// would also run for every /users route registered after it
+	admin := middleware.RequireRoles(entity.RoleAdmin)
+	users.Get("/", admin, h.GetAllUsers)
+	users.Post("/", admin, h.CreateUser)
+	users.Get("/suggest", admin, h.SuggestUsers)
+	users.Post("/bulk", admin, h.BulkUsers)
+	users.Post("/import", admin, h.ImportUsers)
+	users.Get("/import/:jobId", admin, h.GetImportJob)
+	users.Get("/export", admin, h.ExportUsers)
+	users.Get("/export/:jobId", admin, h.GetExportJob)
+	users.Get("/export/:jobId/download", admin, h.DownloadExport)
+	users.Get("/deleted", admin, h.GetDeletedUsers)
+	users.Get("/:id", admin, h.GetUserByID)
+	users.Delete("/:id", admin, h.DeleteUser)
+	users.Post("/:id/restore", admin, h.RestoreUser)
 }
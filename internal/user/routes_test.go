@@ -0,0 +1,149 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	auditMock "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service/mock"
+	eventMock "github.com/itsahyarr/gofiber-boilerplate/internal/event/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/exporter"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/handler"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/export"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// newTestApp serves the user routes backed by repo
+func newTestApp(t *testing.T, repo repository.UserRepository) (*fiber.App, *token.PasetoMaker) {
+	t.Helper()
+
+	tokenMaker, err := token.NewPasetoMaker("01234567890123456789012345678901")
+	require.NoError(t, err)
+
+	userSvc := service.NewUserService(repo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+	app := fiber.New()
+	RegisterRoutes(app, handler.NewUserHandler(userSvc, nil, nil, nil), tokenMaker)
+	return app, tokenMaker
+}
+
+func TestPatchUser_SelfAsNonAdmin(t *testing.T) {
+	// 1. Setup Mocks
+	userID := bson.NewObjectID()
+	user := &entity.User{
+		ID:        userID,
+		Email:     "user@example.com",
+		FirstName: "John",
+		LastName:  "Doe",
+		Role:      entity.RoleUser,
+		IsActive:  true,
+		Version:   1,
+	}
+	mockRepo := &mock.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.User, error) {
+			return user, nil
+		},
+		UpdateFunc: func(ctx context.Context, id string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error) {
+			updated := *user
+			updated.FirstName = "Jane"
+			updated.Version = expectedVersion + 1
+			return &updated, nil
+		},
+	}
+	app, tokenMaker := newTestApp(t, mockRepo)
+	accessToken, _, err := tokenMaker.CreateAccessToken(userID.Hex(), string(entity.RoleUser), time.Minute)
+	require.NoError(t, err)
+
+	// 2. Call Method
+	req := httptest.NewRequest(http.MethodPatch, "/users/"+userID.Hex(), strings.NewReader(`{"firstName":"Jane"}`))
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+accessToken)
+	req.Header.Set(fiber.HeaderContentType, "application/merge-patch+json")
+	res, err := app.Test(req)
+	require.NoError(t, err)
+
+	// 3. Assertions
+	assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	assert.Equal(t, `"2"`, res.Header.Get(fiber.HeaderETag))
+}
+
+func TestGetUserByID_NonAdminForbidden(t *testing.T) {
+	// 1. Setup Mocks
+	app, tokenMaker := newTestApp(t, &mock.MockUserRepository{})
+	userID := bson.NewObjectID()
+	accessToken, _, err := tokenMaker.CreateAccessToken(userID.Hex(), string(entity.RoleUser), time.Minute)
+	require.NoError(t, err)
+
+	// 2. Call Method
+	req := httptest.NewRequest(http.MethodGet, "/users/"+userID.Hex(), nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+accessToken)
+	res, err := app.Test(req)
+	require.NoError(t, err)
+
+	// 3. Assertions
+	assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+}
+
+// slowExporter streams rows slower than the server's write timeout allows
+// for the whole response
+type slowExporter struct {
+	exporter.Exporter
+}
+
+func (slowExporter) Write(ctx context.Context, w io.Writer, filter bson.M, format export.Format) (int, error) {
+	flusher := w.(interface{ Flush() error })
+	for i := 0; i < 5; i++ {
+		time.Sleep(60 * time.Millisecond)
+		if _, err := fmt.Fprintf(w, "row %d\n", i); err != nil {
+			return i, err
+		}
+		if err := flusher.Flush(); err != nil {
+			return i, err
+		}
+	}
+	return 5, nil
+}
+
+func TestExportUsers_StreamsPastWriteTimeout(t *testing.T) {
+	// 1. Setup Mocks
+	tokenMaker, err := token.NewPasetoMaker("01234567890123456789012345678901")
+	require.NoError(t, err)
+	app := fiber.New(fiber.Config{WriteTimeout: 100 * time.Millisecond})
+	RegisterRoutes(app, handler.NewUserHandler(nil, nil, slowExporter{}, nil), tokenMaker)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = app.Listener(ln) }()
+	defer func() { _ = app.Shutdown() }()
+
+	accessToken, _, err := tokenMaker.CreateAccessToken(bson.NewObjectID().Hex(), string(entity.RoleAdmin), time.Minute)
+	require.NoError(t, err)
+
+	// 2. Call Method
+	req, err := http.NewRequest(http.MethodGet, "http://"+ln.Addr().String()+"/users/export?format=csv", nil)
+	require.NoError(t, err)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+accessToken)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+
+	// 3. Assertions
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	assert.Equal(t, "row 0\nrow 1\nrow 2\nrow 3\nrow 4\n", string(body))
+}
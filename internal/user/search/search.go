@@ -0,0 +1,56 @@
+package search
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// Request describes a full-text user search. Filter and Sort use the same
+// document fields as the user list; an empty Sort ranks by relevance.
+type Request struct {
+	Text       string
+	Filter     bson.M
+	Sort       bson.D
+	Projection bson.M
+	Page       int
+	PageSize   int
+}
+
+// Provider is a user search engine. The MongoDB text index is the default;
+// an external engine can be plugged in by implementing this interface.
+type Provider interface {
+	// Search returns a page of users matching the request and the total count
+	Search(ctx context.Context, req Request) ([]*entity.User, int64, error)
+	// Suggest returns up to limit users matching filter whose name or email
+	// starts with prefix, for typeahead
+	Suggest(ctx context.Context, prefix string, filter bson.M, limit int) ([]*entity.User, error)
+	// Filter narrows a list filter to users matching text, for consumers that
+	// stream from the database such as exports
+	Filter(filter bson.M, text string) bson.M
+}
+
+type mongoProvider struct {
+	userRepo repository.UserRepository
+}
+
+// NewMongoProvider creates a provider backed by the users text index
+func NewMongoProvider(userRepo repository.UserRepository) Provider {
+	return &mongoProvider{userRepo: userRepo}
+}
+
+func (p *mongoProvider) Search(ctx context.Context, req Request) ([]*entity.User, int64, error) {
+	opts := repository.FindOptions{Sort: req.Sort, Projection: req.Projection}
+	return p.userRepo.Search(ctx, req.Text, req.Filter, opts, req.Page, req.PageSize)
+}
+
+func (p *mongoProvider) Suggest(ctx context.Context, prefix string, filter bson.M, limit int) ([]*entity.User, error) {
+	return p.userRepo.FindByPrefix(ctx, prefix, filter, limit)
+}
+
+func (p *mongoProvider) Filter(filter bson.M, text string) bson.M {
+	return repository.TextFilter(filter, text)
+}
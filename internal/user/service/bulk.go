@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+
+	auditService "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/events"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+)
+
+// maxBulkUsers caps how many users a single bulk request may touch
+const maxBulkUsers = 1000
+
+var (
+	ErrEmptyBulkFilter  = errors.New("bulk filter must contain at least one criterion")
+	ErrTooManyBulkUsers = errors.New("bulk filter matches too many users")
+)
+
+// Bulk applies one action to a list of users or to every user matching a
+// filter and reports the outcome per user. Failures of individual users are
+// reported rather than aborting the batch; the admin's own account is skipped
+// so they cannot lock themselves out.
+func (s *userServiceImpl) Bulk(ctx context.Context, actorID string, req *dto.BulkUserRequest) (*dto.BulkUserResponse, error) {
+	var result *dto.BulkUserResponse
+	var audits []auditService.Entry
+
+	err := s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// Reset on every attempt, transient transaction errors are retried
+		result = &dto.BulkUserResponse{Action: req.Action, Transactional: database.InTransaction(txCtx)}
+		audits = nil
+
+		ids, err := s.resolveBulkIDs(txCtx, req)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			item := dto.BulkItemResult{ID: id}
+			if id == actorID {
+				item.Error = "cannot apply bulk actions to your own account"
+			} else if audit, err := s.applyBulkAction(txCtx, id, req); err != nil {
+				if !errors.Is(err, repository.ErrUserNotFound) {
+					return err
+				}
+				item.Error = ErrUserNotFound.Error()
+			} else {
+				item.Success = true
+				audits = append(audits, audit)
+			}
+
+			if item.Success {
+				result.Succeeded++
+			} else {
+				result.Failed++
+			}
+			result.Items = append(result.Items, item)
+		}
+		result.Total = len(result.Items)
+
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, ErrEmptyBulkFilter) && !errors.Is(err, ErrTooManyBulkUsers) {
+			logger.Error("bulk user action failed", zap.Error(err), zap.String("action", req.Action))
+		}
+		return nil, err
+	}
+
+	for _, audit := range audits {
+		s.auditor.Record(ctx, audit)
+	}
+
+	logger.Info("bulk user action completed",
+		zap.String("action", req.Action),
+		zap.Int("succeeded", result.Succeeded),
+		zap.Int("failed", result.Failed),
+	)
+	return result, nil
+}
+
+func (s *userServiceImpl) resolveBulkIDs(ctx context.Context, req *dto.BulkUserRequest) ([]string, error) {
+	if len(req.IDs) > 0 {
+		return req.IDs, nil
+	}
+
+	filter := bson.M{}
+	if req.Filter != nil {
+		if req.Filter.Role != nil {
+			filter["role"] = *req.Filter.Role
+		}
+		if req.Filter.IsActive != nil {
+			filter["isActive"] = *req.Filter.IsActive
+		}
+	}
+	if len(filter) == 0 {
+		return nil, ErrEmptyBulkFilter
+	}
+
+	users, total, err := s.userRepo.FindAll(ctx, filter, repository.FindOptions{}, 1, maxBulkUsers)
+	if err != nil {
+		return nil, err
+	}
+	if total > maxBulkUsers {
+		return nil, ErrTooManyBulkUsers
+	}
+
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.ID.Hex()
+	}
+	return ids, nil
+}
+
+// applyBulkAction applies the action to one user and returns its audit entry
+func (s *userServiceImpl) applyBulkAction(ctx context.Context, id string, req *dto.BulkUserRequest) (auditService.Entry, error) {
+	audit := auditService.Entry{TargetType: auditService.TargetUser, TargetID: id}
+
+	update := repository.NewUserUpdate()
+	switch req.Action {
+	case dto.BulkActionDelete:
+		if err := s.userRepo.Delete(ctx, id); err != nil {
+			return audit, err
+		}
+		audit.Action = auditService.ActionUserDelete
+		return audit, event.Record(ctx, s.outbox, events.TypeDeleted, id, events.UserRefPayload{UserID: id})
+	case dto.BulkActionActivate:
+		update.Set(repository.FieldIsActive, true)
+	case dto.BulkActionDeactivate:
+		update.Set(repository.FieldIsActive, false)
+	case dto.BulkActionChangeRole:
+		update.Set(repository.FieldRole, *req.Role)
+	}
+
+	before, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return audit, err
+	}
+	user, err := s.userRepo.Update(ctx, id, repository.AnyVersion, update)
+	if err != nil {
+		return audit, err
+	}
+
+	audit.Action = auditService.ActionUserUpdate
+	audit.Changes = userChanges(before, user)
+	return audit, s.recordUpdated(ctx, user, update)
+}
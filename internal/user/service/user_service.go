@@ -3,13 +3,21 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
+	auditService "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/events"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/search"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
 	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/query"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
 	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.uber.org/zap"
@@ -18,29 +26,126 @@ import (
 var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidOldPassword = errors.New("invalid old password")
+	ErrVersionConflict    = errors.New("user was modified concurrently")
+	ErrEmailAlreadyExists = errors.New("email already exists")
 )
 
+// AnyVersion passed to Update writes regardless of the stored version
+const AnyVersion = repository.AnyVersion
+
 // UserService defines the interface for user operations
 type UserService interface {
+	Create(ctx context.Context, req *dto.CreateUserRequest) (*dto.CreateUserResponse, error)
+	Bulk(ctx context.Context, actorID string, req *dto.BulkUserRequest) (*dto.BulkUserResponse, error)
 	GetByID(ctx context.Context, id string) (*dto.UserResponse, error)
-	GetAll(ctx context.Context, filter bson.M, page, pageSize int) ([]dto.UserResponse, int64, error)
-	Update(ctx context.Context, id string, req *dto.UpdateUserRequest) (*dto.UserResponse, error)
+	GetAll(ctx context.Context, q *query.Query, page, pageSize int) ([]dto.UserResponse, int64, error)
+	GetAllCursor(ctx context.Context, q *query.Query, cursorQuery pagination.CursorQuery) ([]dto.UserResponse, *pagination.CursorPage, error)
+	Embed(ctx context.Context, users []dto.UserResponse, include []string) error
+	Search(ctx context.Context, q *query.Query, text string, page, pageSize int) ([]dto.UserResponse, int64, error)
+	Suggest(ctx context.Context, prefix string, filter bson.M, limit int) ([]dto.UserResponse, error)
+	SearchFilter(filter bson.M, text string) bson.M
+	Export(ctx context.Context, filter bson.M, fn func(user dto.UserResponse) error) error
+	Update(ctx context.Context, id string, req *dto.UpdateUserRequest, expectedVersion int64) (*dto.UserResponse, error)
 	Delete(ctx context.Context, id string) error
+	GetDeleted(ctx context.Context, page, pageSize int) ([]dto.UserResponse, int64, error)
+	GetDeletedCursor(ctx context.Context, query pagination.CursorQuery) ([]dto.UserResponse, *pagination.CursorPage, error)
+	Restore(ctx context.Context, id string) (*dto.UserResponse, error)
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error)
 	ChangePassword(ctx context.Context, id string, req *dto.ChangePasswordRequest) error
+	SetPassword(ctx context.Context, id, password string, mustChange bool) error
 	RegisterWithStats(ctx context.Context, user *entity.User) error
 }
 
 type userServiceImpl struct {
-	userRepo repository.UserRepository
-	db       *database.MongoDB
+	userRepo  repository.UserRepository
+	statsRepo repository.UserStatsRepository
+	searcher  search.Provider
+	txManager database.TxManager
+	outbox    event.Outbox
+	auditor   auditService.Recorder
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository, db *database.MongoDB) UserService {
+func NewUserService(userRepo repository.UserRepository, statsRepo repository.UserStatsRepository, searcher search.Provider, txManager database.TxManager, outbox event.Outbox, auditor auditService.Recorder) UserService {
 	return &userServiceImpl{
-		userRepo: userRepo,
-		db:       db,
+		userRepo:  userRepo,
+		statsRepo: statsRepo,
+		searcher:  searcher,
+		txManager: txManager,
+		outbox:    outbox,
+		auditor:   auditor,
+	}
+}
+
+// Create adds a user on behalf of an admin. The password, generated when not
+// supplied, is temporary and must be changed on first login.
+func (s *userServiceImpl) Create(ctx context.Context, req *dto.CreateUserRequest) (*dto.CreateUserResponse, error) {
+	exists, err := s.userRepo.ExistsByEmail(ctx, req.Email)
+	if err != nil {
+		logger.Error("failed to check email existence", zap.Error(err))
+		return nil, err
+	}
+	if exists {
+		return nil, ErrEmailAlreadyExists
+	}
+
+	password := req.Password
+	var generated string
+	if password == "" {
+		generated, err = utils.GenerateTemporaryPassword()
+		if err != nil {
+			logger.Error("failed to generate temporary password", zap.Error(err))
+			return nil, err
+		}
+		password = generated
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		logger.Error("failed to hash password", zap.Error(err))
+		return nil, err
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	user := &entity.User{
+		Email:              req.Email,
+		Password:           string(hashedPassword),
+		FirstName:          req.FirstName,
+		LastName:           req.LastName,
+		Role:               req.Role,
+		Team:               req.Team,
+		IsActive:           isActive,
+		MustChangePassword: true,
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Create(txCtx, user); err != nil {
+			return err
+		}
+		return event.Record(txCtx, s.outbox, events.TypeCreated, user.ID.Hex(), events.UserPayload{User: dto.ToUserResponse(user)})
+	})
+	if err != nil {
+		logger.Error("failed to create user", zap.Error(err))
+		return nil, err
 	}
+
+	s.auditor.Record(ctx, auditService.Entry{
+		Action:     auditService.ActionUserCreate,
+		TargetType: auditService.TargetUser,
+		TargetID:   user.ID.Hex(),
+		Changes:    append(userChanges(nil, user), auditService.Redacted("password")),
+	})
+
+	logger.Info("user created by admin", zap.String("user_id", user.ID.Hex()))
+
+	return &dto.CreateUserResponse{
+		User:              dto.ToUserResponse(user),
+		TemporaryPassword: generated,
+	}, nil
 }
 
 func (s *userServiceImpl) GetByID(ctx context.Context, id string) (*dto.UserResponse, error) {
@@ -53,57 +158,205 @@ func (s *userServiceImpl) GetByID(ctx context.Context, id string) (*dto.UserResp
 		return nil, err
 	}
 
-	response := dto.ToUserResponse(user)
+	response := dto.ToAdminUserResponse(user)
 	return &response, nil
 }
 
-func (s *userServiceImpl) GetAll(ctx context.Context, filter bson.M, page, pageSize int) ([]dto.UserResponse, int64, error) {
-	users, total, err := s.userRepo.FindAll(ctx, filter, page, pageSize)
+func (s *userServiceImpl) GetAll(ctx context.Context, q *query.Query, page, pageSize int) ([]dto.UserResponse, int64, error) {
+	opts := repository.FindOptions{Sort: q.Sort, Projection: q.Projection}
+	users, total, err := s.userRepo.FindAll(ctx, q.Filter, opts, page, pageSize)
 	if err != nil {
 		logger.Error("failed to get all users", zap.Error(err))
 		return nil, 0, err
 	}
 
-	return dto.ToUserResponses(users), total, nil
+	return dto.ToAdminUserResponses(users), total, nil
 }
 
-func (s *userServiceImpl) Update(ctx context.Context, id string, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
-	user, err := s.userRepo.FindByID(ctx, id)
+func (s *userServiceImpl) GetAllCursor(ctx context.Context, q *query.Query, cursorQuery pagination.CursorQuery) ([]dto.UserResponse, *pagination.CursorPage, error) {
+	users, page, err := s.userRepo.FindAllCursor(ctx, q.Filter, q.Projection, cursorQuery)
 	if err != nil {
-		if errors.Is(err, repository.ErrUserNotFound) {
-			return nil, ErrUserNotFound
+		if !errors.Is(err, pagination.ErrInvalidCursor) {
+			logger.Error("failed to get users by cursor", zap.Error(err))
 		}
-		logger.Error("failed to find user for update", zap.Error(err), zap.String("user_id", id))
+		return nil, nil, err
+	}
+
+	return dto.ToAdminUserResponses(users), page, nil
+}
+
+// Search runs a full-text search combined with the list filter. Without an
+// explicit sort in q the results are ranked by relevance.
+func (s *userServiceImpl) Search(ctx context.Context, q *query.Query, text string, page, pageSize int) ([]dto.UserResponse, int64, error) {
+	users, total, err := s.searcher.Search(ctx, search.Request{
+		Text:       text,
+		Filter:     q.Filter,
+		Sort:       q.Sort,
+		Projection: q.Projection,
+		Page:       page,
+		PageSize:   pageSize,
+	})
+	if err != nil {
+		logger.Error("failed to search users", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return dto.ToAdminUserResponses(users), total, nil
+}
+
+// Suggest returns typeahead matches for a name or email prefix among the
+// users matching filter
+func (s *userServiceImpl) Suggest(ctx context.Context, prefix string, filter bson.M, limit int) ([]dto.UserResponse, error) {
+	users, err := s.searcher.Suggest(ctx, prefix, filter, limit)
+	if err != nil {
+		logger.Error("failed to suggest users", zap.Error(err))
 		return nil, err
 	}
 
-	// Update fields if provided
+	return dto.ToUserResponses(users), nil
+}
+
+// SearchFilter narrows filter to users matching text, for exports
+func (s *userServiceImpl) SearchFilter(filter bson.M, text string) bson.M {
+	return s.searcher.Filter(filter, text)
+}
+
+// Embed loads the requested related resources into users, with one query per
+// relation regardless of how many users there are
+func (s *userServiceImpl) Embed(ctx context.Context, users []dto.UserResponse, include []string) error {
+	for _, relation := range include {
+		switch relation {
+		case dto.IncludeStats:
+			if err := s.embedStats(ctx, users); err != nil {
+				logger.Error("failed to embed user stats", zap.Error(err))
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *userServiceImpl) embedStats(ctx context.Context, users []dto.UserResponse) error {
+	ids := make([]bson.ObjectID, 0, len(users))
+	for _, user := range users {
+		if id, err := bson.ObjectIDFromHex(user.ID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	stats, err := s.statsRepo.FindByUserIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		id, _ := bson.ObjectIDFromHex(users[i].ID)
+		if userStats, ok := stats[id]; ok {
+			users[i].Stats = dto.ToUserStatsResponse(userStats)
+		}
+	}
+
+	return nil
+}
+
+// Export streams every user matching filter to fn without loading the whole
+// result set into memory
+func (s *userServiceImpl) Export(ctx context.Context, filter bson.M, fn func(user dto.UserResponse) error) error {
+	err := s.userRepo.Stream(ctx, filter, func(user *entity.User) error {
+		return fn(dto.ToUserResponse(user))
+	})
+	if err != nil {
+		logger.Error("failed to export users", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Update writes only the fields present in req. Unless expectedVersion is
+// AnyVersion, the update is conditional on the version the client last saw.
+func (s *userServiceImpl) Update(ctx context.Context, id string, req *dto.UpdateUserRequest, expectedVersion int64) (*dto.UserResponse, error) {
+	update := repository.NewUserUpdate()
 	if req.FirstName != nil {
-		user.FirstName = *req.FirstName
+		update.Set(repository.FieldFirstName, *req.FirstName)
 	}
 	if req.LastName != nil {
-		user.LastName = *req.LastName
+		update.Set(repository.FieldLastName, *req.LastName)
 	}
 	if req.Role != nil {
-		user.Role = *req.Role
+		update.Set(repository.FieldRole, *req.Role)
+	}
+	if req.Team != nil {
+		if *req.Team == "" {
+			update.Unset(repository.FieldTeam)
+		} else {
+			update.Set(repository.FieldTeam, *req.Team)
+		}
 	}
 	if req.IsActive != nil {
-		user.IsActive = *req.IsActive
+		update.Set(repository.FieldIsActive, *req.IsActive)
+	}
+
+	// Nothing to write, but still honour the version precondition
+	if update.IsEmpty() {
+		current, err := s.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if expectedVersion != AnyVersion && current.Version != expectedVersion {
+			return nil, ErrVersionConflict
+		}
+		return current, nil
 	}
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	var before, user *entity.User
+	err := s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		// Read in the transaction so the audited before values are the ones
+		// the update replaced
+		before, err = s.userRepo.FindByID(txCtx, id)
+		if err != nil {
+			return err
+		}
+		user, err = s.userRepo.Update(txCtx, id, expectedVersion, update)
+		if err != nil {
+			return err
+		}
+		return s.recordUpdated(txCtx, user, update)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, ErrVersionConflict
+		}
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
 		logger.Error("failed to update user", zap.Error(err), zap.String("user_id", id))
 		return nil, err
 	}
 
+	s.auditor.Record(ctx, auditService.Entry{
+		Action:     auditService.ActionUserUpdate,
+		TargetType: auditService.TargetUser,
+		TargetID:   id,
+		Changes:    userChanges(before, user),
+	})
+
 	logger.Info("user updated successfully", zap.String("user_id", id))
 
-	response := dto.ToUserResponse(user)
+	response := dto.ToAdminUserResponse(user)
 	return &response, nil
 }
 
 func (s *userServiceImpl) Delete(ctx context.Context, id string) error {
-	if err := s.userRepo.Delete(ctx, id); err != nil {
+	err := s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Delete(txCtx, id); err != nil {
+			return err
+		}
+		return event.Record(txCtx, s.outbox, events.TypeDeleted, id, events.UserRefPayload{UserID: id})
+	})
+	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
 			return ErrUserNotFound
 		}
@@ -111,10 +364,104 @@ func (s *userServiceImpl) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
+	s.auditor.Record(ctx, auditService.Entry{
+		Action:     auditService.ActionUserDelete,
+		TargetType: auditService.TargetUser,
+		TargetID:   id,
+	})
+
 	logger.Info("user deleted successfully", zap.String("user_id", id))
 	return nil
 }
 
+func (s *userServiceImpl) GetDeleted(ctx context.Context, page, pageSize int) ([]dto.UserResponse, int64, error) {
+	users, total, err := s.userRepo.FindDeleted(ctx, page, pageSize)
+	if err != nil {
+		logger.Error("failed to get deleted users", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return dto.ToAdminUserResponses(users), total, nil
+}
+
+func (s *userServiceImpl) GetDeletedCursor(ctx context.Context, query pagination.CursorQuery) ([]dto.UserResponse, *pagination.CursorPage, error) {
+	users, page, err := s.userRepo.FindDeletedCursor(ctx, query)
+	if err != nil {
+		if !errors.Is(err, pagination.ErrInvalidCursor) {
+			logger.Error("failed to get deleted users by cursor", zap.Error(err))
+		}
+		return nil, nil, err
+	}
+
+	return dto.ToAdminUserResponses(users), page, nil
+}
+
+func (s *userServiceImpl) Restore(ctx context.Context, id string) (*dto.UserResponse, error) {
+	var user *entity.User
+	err := s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Restore(txCtx, id); err != nil {
+			return err
+		}
+
+		var err error
+		user, err = s.userRepo.FindByID(txCtx, id)
+		if err != nil {
+			return err
+		}
+		return event.Record(txCtx, s.outbox, events.TypeRestored, id, events.UserPayload{User: dto.ToUserResponse(user)})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		logger.Error("failed to restore user", zap.Error(err), zap.String("user_id", id))
+		return nil, err
+	}
+
+	s.auditor.Record(ctx, auditService.Entry{
+		Action:     auditService.ActionUserRestore,
+		TargetType: auditService.TargetUser,
+		TargetID:   id,
+	})
+
+	logger.Info("user restored successfully", zap.String("user_id", id))
+
+	response := dto.ToAdminUserResponse(user)
+	return &response, nil
+}
+
+// PurgeDeleted permanently removes users soft-deleted longer than retention
+// ago together with their stats
+func (s *userServiceImpl) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	var purged []bson.ObjectID
+	err := s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		purged, err = s.userRepo.PurgeDeleted(txCtx, time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		return s.statsRepo.DeleteByUserIDs(txCtx, purged)
+	})
+	if err != nil {
+		logger.Error("failed to purge deleted users", zap.Error(err))
+		return 0, err
+	}
+
+	for _, id := range purged {
+		s.auditor.Record(ctx, auditService.Entry{
+			Action:     auditService.ActionUserPurge,
+			TargetType: auditService.TargetUser,
+			TargetID:   id.Hex(),
+			Reason:     "deleted longer than " + retention.String() + " ago",
+		})
+	}
+
+	if len(purged) > 0 {
+		logger.Info("deleted users purged", zap.Int("count", len(purged)))
+	}
+	return int64(len(purged)), nil
+}
+
 func (s *userServiceImpl) ChangePassword(ctx context.Context, id string, req *dto.ChangePasswordRequest) error {
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
@@ -127,6 +474,13 @@ func (s *userServiceImpl) ChangePassword(ctx context.Context, id string, req *dt
 
 	// Verify old password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); err != nil {
+		s.auditor.Record(ctx, auditService.Entry{
+			Action:     auditService.ActionPasswordChange,
+			TargetType: auditService.TargetUser,
+			TargetID:   id,
+			Outcome:    entity.AuditFailure,
+			Reason:     ErrInvalidOldPassword.Error(),
+		})
 		return ErrInvalidOldPassword
 	}
 
@@ -137,53 +491,117 @@ func (s *userServiceImpl) ChangePassword(ctx context.Context, id string, req *dt
 		return err
 	}
 
-	user.Password = string(hashedPassword)
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	// Conditional on the version whose password hash was just verified
+	update := repository.NewUserUpdate().
+		Set(repository.FieldPassword, string(hashedPassword)).
+		Set(repository.FieldMustChangePassword, false)
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := s.userRepo.Update(txCtx, id, user.Version, update); err != nil {
+			return err
+		}
+		return event.Record(txCtx, s.outbox, events.TypePasswordChanged, id, events.UserRefPayload{UserID: id})
+	})
+	if err != nil {
+		// The user may have been deleted since it was read
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return ErrVersionConflict
+		}
 		logger.Error("failed to update password", zap.Error(err), zap.String("user_id", id))
 		return err
 	}
 
+	s.auditor.Record(ctx, auditService.Entry{
+		Action:     auditService.ActionPasswordChange,
+		TargetType: auditService.TargetUser,
+		TargetID:   id,
+		Changes:    []entity.AuditChange{auditService.Redacted("password")},
+	})
+
 	logger.Info("password changed successfully", zap.String("user_id", id))
 	return nil
 }
 
-func (s *userServiceImpl) RegisterWithStats(ctx context.Context, user *entity.User) error {
-	// 1. Start a Session
-	session, err := s.db.Client.StartSession()
+// SetPassword replaces a user's password without checking the old one. It is
+// meant for operators; mustChange forces a change on next login.
+func (s *userServiceImpl) SetPassword(ctx context.Context, id, password string, mustChange bool) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		logger.Error("failed to start mongodb session", zap.Error(err))
+		logger.Error("failed to hash new password", zap.Error(err))
 		return err
 	}
-	defer session.EndSession(ctx)
 
-	// 2. Use WithTransaction to wrap your logic
-	_, err = session.WithTransaction(ctx, func(sessCtx context.Context) (any, error) {
-		// --- OPERATION 1: Create User ---
-		// We use the repository but we must ensure the repository methods can accept the session context
-		// Currently the repo methods use the context passed to them.
-		if err := s.userRepo.Create(sessCtx, user); err != nil {
-			logger.Error("failed to create user in transaction", zap.Error(err))
-			return nil, err
+	update := repository.NewUserUpdate().
+		Set(repository.FieldPassword, string(hashedPassword)).
+		Set(repository.FieldMustChangePassword, mustChange)
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := s.userRepo.Update(txCtx, id, repository.AnyVersion, update); err != nil {
+			return err
 		}
-
-		// --- OPERATION 2: Create associated data (Stats) ---
-		statsCollection := s.db.Collection("user_stats")
-		userStats := bson.M{
-			"user_id":     user.ID,
-			"login_count": 0,
-			"points":      100, // Welcome bonus
+		return event.Record(txCtx, s.outbox, events.TypePasswordChanged, id, events.UserRefPayload{UserID: id})
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return ErrUserNotFound
 		}
+		logger.Error("failed to set password", zap.Error(err), zap.String("user_id", id))
+		return err
+	}
 
-		if _, err := statsCollection.InsertOne(sessCtx, userStats); err != nil {
-			logger.Error("failed to create user stats in transaction", zap.Error(err))
-			return nil, err
-		}
+	s.auditor.Record(ctx, auditService.Entry{
+		Action:     auditService.ActionPasswordSet,
+		TargetType: auditService.TargetUser,
+		TargetID:   id,
+		Changes:    []entity.AuditChange{auditService.Redacted("password")},
+	})
+
+	logger.Info("password set by operator", zap.String("user_id", id))
+	return nil
+}
+
+// auditIgnoredFields change on every write and say nothing about what changed
+var auditIgnoredFields = []string{"id", "version", "createdAt", "updatedAt"}
 
-		return nil, nil
+// userChanges diffs two states of a user for the audit log; before is nil
+// for new users
+func userChanges(before, after *entity.User) []entity.AuditChange {
+	var beforeResponse any
+	if before != nil {
+		beforeResponse = dto.ToUserResponse(before)
+	}
+	return auditService.Diff(beforeResponse, dto.ToUserResponse(after), auditIgnoredFields...)
+}
+
+// recordUpdated adds a user.updated event for an update that was just applied
+func (s *userServiceImpl) recordUpdated(ctx context.Context, user *entity.User, update *repository.UserUpdate) error {
+	return event.Record(ctx, s.outbox, events.TypeUpdated, user.ID.Hex(), events.UserPayload{
+		User:    dto.ToUserResponse(user),
+		Changed: update.Fields(),
 	})
+}
+
+// RegisterWithStats creates a user together with their stats document; neither
+// is kept if the other fails
+func (s *userServiceImpl) RegisterWithStats(ctx context.Context, user *entity.User) error {
+	err := s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Create(txCtx, user); err != nil {
+			return err
+		}
 
+		userStats := &entity.UserStats{
+			UserID:     user.ID,
+			LoginCount: 0,
+			Points:     100, // Welcome bonus
+		}
+		if err := s.statsRepo.Create(txCtx, userStats); err != nil {
+			return err
+		}
+		return event.Record(txCtx, s.outbox, events.TypeRegistered, user.ID.Hex(), events.UserPayload{User: dto.ToUserResponse(user)})
+	})
 	if err != nil {
-		logger.Error("transaction failed and rolled back", zap.Error(err))
+		logger.Error("failed to create user with stats", zap.Error(err))
 		return err
 	}
 
@@ -2,20 +2,31 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/crypto/bcrypt"
 
+	auditService "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service"
+	auditMock "github.com/itsahyarr/gofiber-boilerplate/internal/audit/service/mock"
+	eventMock "github.com/itsahyarr/gofiber-boilerplate/internal/event/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/events"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository"
 	"github.com/itsahyarr/gofiber-boilerplate/internal/user/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/search"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/query"
 	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
 )
 
 func TestGetByID(t *testing.T) {
 	// 1. Setup Mock
 	mockRepo := &mock.MockUserRepository{
-		FindAllFunc: func(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.User, int64, error) {
+		FindAllFunc: func(ctx context.Context, filter bson.M, opts repository.FindOptions, page, pageSize int) ([]*entity.User, int64, error) {
 			return nil, 0, nil
 		},
 		FindByIDFunc: func(ctx context.Context, id string) (*entity.User, error) {
@@ -30,8 +41,8 @@ func TestGetByID(t *testing.T) {
 	}
 
 	// 2. Initialize Service with Mock
-	// Note: We pass nil for MongoDB since GetByID doesn't use it for transactions
-	service := NewUserService(mockRepo, nil)
+	// Note: GetByID doesn't use transactions, so the no-op TxManager is enough
+	service := NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
 
 	// 3. Call Method
 	res, err := service.GetByID(context.Background(), "658bd7c1f1e29e0001bcdefg")
@@ -46,7 +57,7 @@ func TestGetByID(t *testing.T) {
 func TestGetByID_NotFound(t *testing.T) {
 	// 1. Setup Mock to return NotFound
 	mockRepo := &mock.MockUserRepository{
-		FindAllFunc: func(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.User, int64, error) {
+		FindAllFunc: func(ctx context.Context, filter bson.M, opts repository.FindOptions, page, pageSize int) ([]*entity.User, int64, error) {
 			return nil, 0, nil
 		},
 		FindByIDFunc: func(ctx context.Context, id string) (*entity.User, error) {
@@ -54,7 +65,7 @@ func TestGetByID_NotFound(t *testing.T) {
 		},
 	}
 
-	service := NewUserService(mockRepo, nil)
+	service := NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
 
 	// 2. Call Method
 	res, err := service.GetByID(context.Background(), "invalid-id")
@@ -64,3 +75,351 @@ func TestGetByID_NotFound(t *testing.T) {
 	assert.Nil(t, res)
 	assert.Equal(t, ErrUserNotFound, err)
 }
+
+func TestRestore_NotFound(t *testing.T) {
+	// 1. Setup Mock where no soft-deleted user matches
+	mockRepo := &mock.MockUserRepository{
+		RestoreFunc: func(ctx context.Context, id string) error {
+			return repository.ErrUserNotFound
+		},
+	}
+
+	service := NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+
+	// 2. Call Method
+	res, err := service.Restore(context.Background(), "658bd7c1f1e29e0001bcdef0")
+
+	// 3. Assertions
+	assert.Nil(t, res)
+	assert.Equal(t, ErrUserNotFound, err)
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	// 1. Setup Mock capturing the purge cutoff and the purged users' stats
+	var cutoff time.Time
+	purgedIDs := []bson.ObjectID{bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()}
+	mockRepo := &mock.MockUserRepository{
+		PurgeDeletedFunc: func(ctx context.Context, before time.Time) ([]bson.ObjectID, error) {
+			cutoff = before
+			return purgedIDs, nil
+		},
+	}
+	var deletedStats []bson.ObjectID
+	mockStatsRepo := &mock.MockUserStatsRepository{
+		DeleteByUserIDsFunc: func(ctx context.Context, userIDs []bson.ObjectID) error {
+			deletedStats = userIDs
+			return nil
+		},
+	}
+
+	recorder := &auditMock.MockRecorder{}
+	service := NewUserService(mockRepo, mockStatsRepo, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, recorder)
+
+	// 2. Call Method
+	purged, err := service.PurgeDeleted(context.Background(), 24*time.Hour)
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), cutoff, time.Second)
+	assert.Equal(t, purgedIDs, deletedStats)
+	if assert.Len(t, recorder.Entries, 3) {
+		assert.Equal(t, auditService.ActionUserPurge, recorder.Entries[0].Action)
+		assert.Equal(t, purgedIDs[2].Hex(), recorder.Entries[2].TargetID)
+	}
+}
+
+func TestUpdate_StaleVersion(t *testing.T) {
+	// 1. Setup Mock where the stored user has moved past the client's version
+	var gotVersion int64
+	mockRepo := &mock.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.User, error) {
+			return &entity.User{FirstName: "John", Version: 3}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error) {
+			gotVersion = expectedVersion
+			return nil, repository.ErrVersionConflict
+		},
+	}
+
+	service := NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+	firstName := "Jane"
+
+	// 2. Call Method with the version the client saw earlier
+	res, err := service.Update(context.Background(), "658bd7c1f1e29e0001bcdef0", &dto.UpdateUserRequest{FirstName: &firstName}, 2)
+
+	// 3. Assertions
+	assert.Nil(t, res)
+	assert.Equal(t, ErrVersionConflict, err)
+	assert.Equal(t, int64(2), gotVersion)
+}
+
+func TestUpdate_RecordsUpdatedEventAndAudit(t *testing.T) {
+	// 1. Setup Mock returning the user before and after the update
+	id := bson.NewObjectID()
+	mockRepo := &mock.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, _ string) (*entity.User, error) {
+			return &entity.User{ID: id, FirstName: "John", Role: entity.RoleUser, Version: 2}, nil
+		},
+		UpdateFunc: func(ctx context.Context, _ string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error) {
+			return &entity.User{ID: id, FirstName: "Jane", Role: entity.RoleAdmin, Version: 3}, nil
+		},
+	}
+	outbox := &eventMock.MockOutbox{}
+	auditor := &auditMock.MockRecorder{}
+
+	service := NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), outbox, auditor)
+	firstName := "Jane"
+	role := entity.RoleAdmin
+
+	// 2. Call Method
+	_, err := service.Update(context.Background(), id.Hex(), &dto.UpdateUserRequest{FirstName: &firstName, Role: &role}, 2)
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	if assert.Len(t, outbox.Events, 1) {
+		evt := outbox.Events[0]
+		assert.Equal(t, events.TypeUpdated, evt.Type)
+		assert.Equal(t, id.Hex(), evt.AggregateID)
+		assert.Equal(t, []any{"firstName", "role"}, evt.Data["changed"])
+	}
+	if assert.Len(t, auditor.Entries, 1) {
+		assert.Equal(t, auditService.ActionUserUpdate, auditor.Entries[0].Action)
+		assert.Equal(t, []entity.AuditChange{
+			{Field: "firstName", Before: `"John"`, After: `"Jane"`},
+			{Field: "role", Before: `"USER"`, After: `"ADMIN"`},
+		}, auditor.Entries[0].Changes)
+	}
+}
+
+func TestChangePassword_ConditionalOnReadVersion(t *testing.T) {
+	// 1. Setup Mock with a stored password hash at version 5
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	var gotVersion int64
+	mockRepo := &mock.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.User, error) {
+			return &entity.User{Password: string(hash), Version: 5}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error) {
+			gotVersion = expectedVersion
+			return &entity.User{Version: 6}, nil
+		},
+	}
+
+	service := NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+
+	// 2. Call Method
+	err := service.ChangePassword(context.Background(), "658bd7c1f1e29e0001bcdef0", &dto.ChangePasswordRequest{
+		OldPassword: "old-password",
+		NewPassword: "new-password",
+	})
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), gotVersion)
+}
+
+func TestChangePassword_DeletedBeforeWrite(t *testing.T) {
+	// 1. Setup Mock: the user is deleted between the read and the write
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	mockRepo := &mock.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.User, error) {
+			return &entity.User{Password: string(hash), Version: 5}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error) {
+			return nil, repository.ErrUserNotFound
+		},
+	}
+
+	service := NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+
+	// 2. Call Method
+	err := service.ChangePassword(context.Background(), "658bd7c1f1e29e0001bcdef0", &dto.ChangePasswordRequest{
+		OldPassword: "old-password",
+		NewPassword: "new-password",
+	})
+
+	// 3. Assertions
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestSetPassword_NotFound(t *testing.T) {
+	// 1. Setup Mock
+	mockRepo := &mock.MockUserRepository{
+		UpdateFunc: func(ctx context.Context, id string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error) {
+			return nil, repository.ErrUserNotFound
+		},
+	}
+
+	service := NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+
+	// 2. Call Method
+	err := service.SetPassword(context.Background(), "658bd7c1f1e29e0001bcdef0", "new-password", true)
+
+	// 3. Assertions
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestCreate_GeneratesTemporaryPassword(t *testing.T) {
+	// 1. Setup Mock capturing the created user
+	var created *entity.User
+	mockRepo := &mock.MockUserRepository{
+		ExistsByEmailFunc: func(ctx context.Context, email string) (bool, error) {
+			return false, nil
+		},
+		CreateFunc: func(ctx context.Context, user *entity.User) error {
+			user.ID = bson.NewObjectID()
+			created = user
+			return nil
+		},
+	}
+
+	service := NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+
+	// 2. Call Method without a password
+	res, err := service.Create(context.Background(), &dto.CreateUserRequest{
+		Email:     "new@example.com",
+		FirstName: "New",
+		LastName:  "User",
+		Role:      entity.RoleAdmin,
+	})
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res.TemporaryPassword)
+	assert.True(t, created.MustChangePassword)
+	assert.True(t, created.IsActive)
+	assert.Equal(t, entity.RoleAdmin, created.Role)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(created.Password), []byte(res.TemporaryPassword)))
+}
+
+func TestBulk_ReportsPerItem(t *testing.T) {
+	// 1. Setup Mock where one of the users no longer exists
+	actorID := "658bd7c1f1e29e0001bcdef0"
+	missingID := "658bd7c1f1e29e0001bcdef1"
+	okID := "658bd7c1f1e29e0001bcdef2"
+	mockRepo := &mock.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.User, error) {
+			if id == missingID {
+				return nil, repository.ErrUserNotFound
+			}
+			return &entity.User{IsActive: true}, nil
+		},
+		UpdateFunc: func(ctx context.Context, id string, expectedVersion int64, update *repository.UserUpdate) (*entity.User, error) {
+			return &entity.User{}, nil
+		},
+	}
+	auditor := &auditMock.MockRecorder{}
+
+	service := NewUserService(mockRepo, nil, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, auditor)
+
+	// 2. Call Method including the acting admin's own ID
+	res, err := service.Bulk(context.Background(), actorID, &dto.BulkUserRequest{
+		Action: dto.BulkActionDeactivate,
+		IDs:    []string{actorID, missingID, okID},
+	})
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, 1, res.Succeeded)
+	assert.Equal(t, 2, res.Failed)
+	assert.False(t, res.Items[0].Success)
+	assert.Equal(t, ErrUserNotFound.Error(), res.Items[1].Error)
+	assert.True(t, res.Items[2].Success)
+	assert.False(t, res.Transactional)
+	if assert.Len(t, auditor.Entries, 1) {
+		assert.Equal(t, okID, auditor.Entries[0].TargetID)
+		assert.Equal(t, []entity.AuditChange{{Field: "isActive", Before: "true", After: "false"}}, auditor.Entries[0].Changes)
+	}
+}
+
+func TestEmbed_StatsInOneQuery(t *testing.T) {
+	// 1. Setup Mock
+	withStats := bson.NewObjectID()
+	withoutStats := bson.NewObjectID()
+	calls := 0
+	mockStatsRepo := &mock.MockUserStatsRepository{
+		FindByUserIDsFunc: func(ctx context.Context, userIDs []bson.ObjectID) (map[bson.ObjectID]*entity.UserStats, error) {
+			calls++
+			assert.ElementsMatch(t, []bson.ObjectID{withStats, withoutStats}, userIDs)
+			return map[bson.ObjectID]*entity.UserStats{
+				withStats: {UserID: withStats, LoginCount: 3, Points: 100},
+			}, nil
+		},
+	}
+
+	service := NewUserService(&mock.MockUserRepository{}, mockStatsRepo, nil, database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+	users := []dto.UserResponse{{ID: withStats.Hex()}, {ID: withoutStats.Hex()}}
+
+	// 2. Call Method
+	err := service.Embed(context.Background(), users, []string{dto.IncludeStats})
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, &dto.UserStatsResponse{LoginCount: 3, Points: 100}, users[0].Stats)
+	assert.Nil(t, users[1].Stats)
+}
+
+func TestSearch_PassesTextSeparatelyFromFilter(t *testing.T) {
+	// 1. Setup Mock
+	mockRepo := &mock.MockUserRepository{
+		SearchFunc: func(ctx context.Context, text string, filter bson.M, opts repository.FindOptions, page, pageSize int) ([]*entity.User, int64, error) {
+			// The text must never be folded into the filter as a pattern
+			assert.Equal(t, "jo.*", text)
+			assert.Equal(t, bson.M{"role": "USER"}, filter)
+			assert.Empty(t, opts.Sort)
+			return []*entity.User{{Email: "jo@example.com"}}, 1, nil
+		},
+	}
+
+	service := NewUserService(mockRepo, nil, search.NewMongoProvider(mockRepo), database.NewNoopTxManager(), &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+
+	// 2. Call Method
+	users, total, err := service.Search(context.Background(), &query.Query{Filter: bson.M{"role": "USER"}}, "jo.*", 1, 10)
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "jo@example.com", users[0].Email)
+}
+
+// recordingTxManager counts the units of work it runs
+type recordingTxManager struct {
+	calls int
+}
+
+func (m *recordingTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+func TestRegisterWithStats_SingleUnitOfWork(t *testing.T) {
+	// 1. Setup Mocks where creating the stats fails
+	statsErr := errors.New("stats write failed")
+	var created bool
+	mockRepo := &mock.MockUserRepository{
+		CreateFunc: func(ctx context.Context, user *entity.User) error {
+			created = true
+			user.ID = bson.NewObjectID()
+			return nil
+		},
+	}
+	mockStatsRepo := &mock.MockUserStatsRepository{
+		CreateFunc: func(ctx context.Context, stats *entity.UserStats) error {
+			return statsErr
+		},
+	}
+	txManager := &recordingTxManager{}
+
+	service := NewUserService(mockRepo, mockStatsRepo, nil, txManager, &eventMock.MockOutbox{}, &auditMock.MockRecorder{})
+
+	// 2. Call Method
+	err := service.RegisterWithStats(context.Background(), &entity.User{Email: "new@example.com"})
+
+	// 3. Assertions: both writes ran in the same unit of work, whose error is returned
+	assert.ErrorIs(t, err, statsErr)
+	assert.True(t, created)
+	assert.Equal(t, 1, txManager.calls)
+}
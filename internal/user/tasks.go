@@ -0,0 +1,34 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/config"
+	schedulerService "github.com/itsahyarr/gofiber-boilerplate/internal/scheduler/service"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/exporter"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/user/service"
+)
+
+// RegisterTasks registers the user module's periodic tasks
+func RegisterTasks(scheduler schedulerService.Scheduler, userService service.UserService, userExporter exporter.Exporter, cfg config.UserConfig) error {
+	return errors.Join(
+		scheduler.Register(schedulerService.Task{
+			Name:        "user.purge_deleted",
+			Schedule:    cfg.PurgeSchedule,
+			Description: "Permanently remove users soft-deleted longer than the retention period",
+			Timeout:     30 * time.Minute,
+			Run: func(ctx context.Context) error {
+				_, err := userService.PurgeDeleted(ctx, cfg.DeletedRetention)
+				return err
+			},
+		}),
+		scheduler.Register(schedulerService.Task{
+			Name:        "user.delete_expired_exports",
+			Schedule:    "30 * * * *",
+			Description: "Delete generated export files once their job has expired",
+			Run:         userExporter.DeleteExpired,
+		}),
+	)
+}
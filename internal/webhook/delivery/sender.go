@@ -0,0 +1,72 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/netguard"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// maxResponseBody bounds how much of a receiver's response is read
+const maxResponseBody = 64 * 1024
+
+// Sender posts signed deliveries to webhook endpoints
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a Sender whose requests time out after timeout. Unless
+// allowPrivate is set, it refuses to connect to loopback, private and
+// link-local addresses, whatever the webhook's host resolves to at the time.
+func NewSender(timeout time.Duration, allowPrivate bool) *Sender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Receivers are configured by URL, not by the environment
+	transport.Proxy = nil
+	if !allowPrivate {
+		transport.DialContext = netguard.Dialer(net.Dialer{Timeout: timeout}).DialContext
+	}
+
+	return &Sender{
+		client: &http.Client{Timeout: timeout, Transport: transport},
+	}
+}
+
+// Send posts the delivery's payload to the webhook and returns the response
+// status. Any status outside 2xx is an error.
+func (s *Sender) Send(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gofiber-boilerplate-webhooks/1.0")
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID.Hex())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,141 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+const (
+	// claimLease must outlast the sender's timeout
+	claimLease = 2 * time.Minute
+
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = 6 * time.Hour
+)
+
+// Worker sends due deliveries and schedules retries with exponential
+// backoff until a delivery succeeds or runs out of attempts
+type Worker struct {
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.DeliveryRepository
+	sender       *Sender
+	maxAttempts  int
+	batchSize    int
+}
+
+// NewWorker creates a delivery Worker
+func NewWorker(webhookRepo repository.WebhookRepository, deliveryRepo repository.DeliveryRepository, sender *Sender, maxAttempts, batchSize int) *Worker {
+	return &Worker{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		sender:       sender,
+		maxAttempts:  maxAttempts,
+		batchSize:    batchSize,
+	}
+}
+
+// DeliverDue sends one batch of due deliveries and returns how many it sent
+func (w *Worker) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := w.deliveryRepo.ClaimDue(ctx, w.batchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks := map[string]*entity.Webhook{}
+	for _, delivery := range deliveries {
+		webhookID := delivery.WebhookID.Hex()
+		webhook, ok := webhooks[webhookID]
+		if !ok {
+			webhook, err = w.webhookRepo.FindByID(ctx, webhookID)
+			if err != nil && !errors.Is(err, repository.ErrWebhookNotFound) {
+				return 0, err
+			}
+			webhooks[webhookID] = webhook
+		}
+
+		w.attempt(ctx, webhook, delivery)
+		if err := w.deliveryRepo.SaveAttempt(ctx, delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// attempt sends a delivery and records the outcome on it
+func (w *Worker) attempt(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) {
+	now := time.Now()
+	delivery.LastAttemptAt = &now
+
+	switch {
+	case webhook == nil:
+		delivery.Status = entity.DeliveryFailed
+		delivery.LastError = "webhook was deleted"
+		return
+	case !webhook.IsActive:
+		delivery.Status = entity.DeliveryFailed
+		delivery.LastError = "webhook is disabled"
+		return
+	}
+
+	status, err := w.sender.Send(ctx, webhook, delivery)
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = entity.DeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= w.maxAttempts {
+		delivery.Status = entity.DeliveryFailed
+		logger.Warn("webhook delivery failed permanently",
+			zap.String("delivery_id", delivery.ID.Hex()),
+			zap.String("webhook_id", webhook.ID.Hex()),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(err),
+		)
+		return
+	}
+
+	delivery.Status = entity.DeliveryPending
+	delivery.NextAttemptAt = now.Add(utils.Backoff(delivery.Attempts, retryBaseDelay, retryMaxDelay))
+}
+
+// StartWorker delivers due webhooks every interval until ctx is cancelled
+func StartWorker(ctx context.Context, worker *Worker, interval time.Duration) {
+	logger.Info("starting webhook delivery worker", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("webhook delivery worker stopped")
+			return
+		case <-ticker.C:
+			for {
+				sent, err := worker.DeliverDue(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						logger.Error("webhook delivery failed", zap.Error(err))
+					}
+					break
+				}
+				if sent < worker.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
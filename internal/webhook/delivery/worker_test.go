@@ -0,0 +1,152 @@
+package delivery_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/delivery"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+const secret = "whsec_test"
+
+// runWorker delivers one claimed delivery to url and returns it as saved.
+// Test receivers listen on loopback, so private targets are allowed.
+func runWorker(t *testing.T, url string, attempts, maxAttempts int) *entity.WebhookDelivery {
+	t.Helper()
+	return runWorkerWith(t, delivery.NewSender(time.Second, true), url, attempts, maxAttempts)
+}
+
+func runWorkerWith(t *testing.T, sender *delivery.Sender, url string, attempts, maxAttempts int) *entity.WebhookDelivery {
+	t.Helper()
+
+	webhook := &entity.Webhook{ID: bson.NewObjectID(), URL: url, Secret: secret, IsActive: true}
+	claimed := &entity.WebhookDelivery{
+		ID:        bson.NewObjectID(),
+		WebhookID: webhook.ID,
+		EventID:   "evt-1",
+		EventType: "user.created",
+		Payload:   `{"id":"evt-1","type":"user.created"}`,
+		Status:    entity.DeliveryPending,
+		Attempts:  attempts,
+	}
+
+	var saved *entity.WebhookDelivery
+	webhookRepo := &mock.MockWebhookRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Webhook, error) {
+			return webhook, nil
+		},
+	}
+	deliveryRepo := &mock.MockDeliveryRepository{
+		ClaimDueFunc: func(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error) {
+			return []*entity.WebhookDelivery{claimed}, nil
+		},
+		SaveAttemptFunc: func(ctx context.Context, d *entity.WebhookDelivery) error {
+			saved = d
+			return nil
+		},
+	}
+
+	worker := delivery.NewWorker(webhookRepo, deliveryRepo, sender, maxAttempts, 10)
+	sent, err := worker.DeliverDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	return saved
+}
+
+func TestDeliverDue_SignedRequestSucceeds(t *testing.T) {
+	// 1. Setup a receiver that verifies the signature like a real consumer
+	var verifyErr error
+	var eventID string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = delivery.Verify(secret, r.Header, body, 5*time.Minute, time.Now())
+		eventID = r.Header.Get(delivery.HeaderEventID)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	// 2. Call Method
+	saved := runWorker(t, receiver.URL, 1, 5)
+
+	// 3. Assertions
+	assert.NoError(t, verifyErr)
+	assert.Equal(t, "evt-1", eventID)
+	assert.Equal(t, entity.DeliverySucceeded, saved.Status)
+	assert.Equal(t, http.StatusNoContent, saved.ResponseStatus)
+	assert.NotNil(t, saved.DeliveredAt)
+}
+
+func TestDeliverDue_FailureIsRetriedWithBackoff(t *testing.T) {
+	// 1. Setup a receiver that is down
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	// 2. Call Method on the second attempt
+	saved := runWorker(t, receiver.URL, 2, 5)
+
+	// 3. Assertions
+	assert.Equal(t, entity.DeliveryPending, saved.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, saved.ResponseStatus)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), saved.NextAttemptAt, 5*time.Second)
+}
+
+func TestDeliverDue_FailsAfterMaxAttempts(t *testing.T) {
+	// 1. Setup a receiver that is down
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	// 2. Call Method on the last attempt
+	saved := runWorker(t, receiver.URL, 5, 5)
+
+	// 3. Assertions
+	assert.Equal(t, entity.DeliveryFailed, saved.Status)
+	assert.Contains(t, saved.LastError, "500")
+}
+
+func TestDeliverDue_RefusesPrivateTargets(t *testing.T) {
+	// 1. Setup a receiver on loopback
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	// 2. Call Method with a sender that only reaches public addresses
+	saved := runWorkerWith(t, delivery.NewSender(time.Second, false), receiver.URL, 1, 5)
+
+	// 3. Assertions
+	assert.False(t, called)
+	assert.Equal(t, entity.DeliveryPending, saved.Status)
+	assert.Contains(t, saved.LastError, "not publicly routable")
+}
+
+func TestVerify_RejectsTamperedAndStaleRequests(t *testing.T) {
+	body := []byte(`{"id":"evt-1"}`)
+	now := time.Now()
+
+	header := http.Header{}
+	header.Set(delivery.HeaderTimestamp, "1700000000")
+	header.Set(delivery.HeaderSignature, delivery.Sign(secret, 1700000000, body))
+
+	// Stale timestamp
+	assert.ErrorIs(t, delivery.Verify(secret, header, body, 5*time.Minute, now), delivery.ErrInvalidSignature)
+
+	// Valid, then tampered body and wrong secret
+	assert.NoError(t, delivery.Verify(secret, header, body, 5*time.Minute, time.Unix(1700000000, 0)))
+	assert.ErrorIs(t, delivery.Verify(secret, header, []byte(`{"id":"evt-2"}`), 5*time.Minute, time.Unix(1700000000, 0)), delivery.ErrInvalidSignature)
+	assert.ErrorIs(t, delivery.Verify("other", header, body, 5*time.Minute, time.Unix(1700000000, 0)), delivery.ErrInvalidSignature)
+}
@@ -0,0 +1,112 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// CreateWebhookRequest represents the create webhook request body. Events
+// are event types such as "user.created", prefixes such as "user.*", or "*".
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url,startswith=http"`
+	Events      []string `json:"events" validate:"required,min=1,dive,required,max=64"`
+	Description string   `json:"description,omitempty" validate:"omitempty,max=256"`
+	IsActive    *bool    `json:"isActive,omitempty"`
+}
+
+// UpdateWebhookRequest represents the update webhook request body. Omitted
+// fields are left unchanged.
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url,omitempty" validate:"omitempty,url,startswith=http"`
+	Events      []string `json:"events,omitempty" validate:"omitempty,min=1,dive,required,max=64"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=256"`
+	IsActive    *bool    `json:"isActive,omitempty"`
+	// RotateSecret replaces the signing secret; the new one is returned once
+	RotateSecret bool `json:"rotateSecret,omitempty"`
+}
+
+// WebhookResponse represents a webhook in API responses. The secret is only
+// returned when it is created or rotated.
+type WebhookResponse struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description,omitempty"`
+	IsActive    bool      `json:"isActive"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// ToWebhookResponse converts a webhook entity to a response without its secret
+func ToWebhookResponse(webhook *entity.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:          webhook.ID.Hex(),
+		URL:         webhook.URL,
+		Events:      webhook.Events,
+		Description: webhook.Description,
+		IsActive:    webhook.IsActive,
+		CreatedAt:   webhook.CreatedAt,
+		UpdatedAt:   webhook.UpdatedAt,
+	}
+}
+
+// ToWebhookResponses converts webhook entities to responses
+func ToWebhookResponses(webhooks []*entity.Webhook) []WebhookResponse {
+	responses := make([]WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		responses[i] = ToWebhookResponse(webhook)
+	}
+	return responses
+}
+
+// DeliveryResponse represents a webhook delivery in the delivery log
+type DeliveryResponse struct {
+	ID             string                `json:"id"`
+	WebhookID      string                `json:"webhookId"`
+	EventID        string                `json:"eventId"`
+	EventType      string                `json:"eventType"`
+	Payload        string                `json:"payload"`
+	Status         entity.DeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  *time.Time            `json:"lastAttemptAt,omitempty"`
+	ResponseStatus int                   `json:"responseStatus,omitempty"`
+	LastError      string                `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time            `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt"`
+}
+
+// ToDeliveryResponse converts a delivery entity to a response
+func ToDeliveryResponse(delivery *entity.WebhookDelivery) DeliveryResponse {
+	response := DeliveryResponse{
+		ID:             delivery.ID.Hex(),
+		WebhookID:      delivery.WebhookID.Hex(),
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	// Only pending deliveries have a next attempt
+	if delivery.Status == entity.DeliveryPending {
+		next := delivery.NextAttemptAt
+		response.NextAttemptAt = &next
+	}
+	return response
+}
+
+// ToDeliveryResponses converts delivery entities to responses
+func ToDeliveryResponses(deliveries []*entity.WebhookDelivery) []DeliveryResponse {
+	responses := make([]DeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = ToDeliveryResponse(delivery)
+	}
+	return responses
+}
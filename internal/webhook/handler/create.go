@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/validator"
+)
+
+// CreateWebhook godoc
+// @Summary      Create webhook
+// @Description  Register an endpoint for domain events (ADMIN only). The signing secret is returned once
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body dto.CreateWebhookRequest true "Create webhook request"
+// @Success      201 {object} response.Response{data=dto.WebhookResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	var req dto.CreateWebhookRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.BadRequest(c, "invalid request body", validator.FormatValidationErrors(err))
+	}
+
+	webhook, err := h.webhookService.Create(c.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrForbiddenURL) {
+			return response.BadRequest(c, "invalid webhook url", err.Error())
+		}
+		return response.InternalServerError(c, "failed to create webhook")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "webhook created successfully", webhook)
+}
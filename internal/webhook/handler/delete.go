@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// DeleteWebhook godoc
+// @Summary      Delete webhook
+// @Description  Delete a webhook and its delivery log (ADMIN only)
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Webhook ID"
+// @Success      200 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	if err := h.webhookService.Delete(c.Context(), c.Params("id")); err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			return response.NotFound(c, "webhook not found")
+		}
+		return response.InternalServerError(c, "failed to delete webhook")
+	}
+
+	return response.Success(c, fiber.StatusOK, "webhook deleted successfully", nil)
+}
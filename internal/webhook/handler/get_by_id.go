@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetWebhookByID godoc
+// @Summary      Get webhook by ID
+// @Description  Get a webhook (ADMIN only)
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Webhook ID"
+// @Success      200 {object} response.Response{data=dto.WebhookResponse}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhookByID(c *fiber.Ctx) error {
+	webhook, err := h.webhookService.GetByID(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			return response.NotFound(c, "webhook not found")
+		}
+		return response.InternalServerError(c, "failed to get webhook")
+	}
+
+	return response.Success(c, fiber.StatusOK, "webhook retrieved successfully", webhook)
+}
@@ -0,0 +1,15 @@
+package handler
+
+import "github.com/itsahyarr/gofiber-boilerplate/internal/webhook/service"
+
+// WebhookHandler handles webhook-related HTTP requests
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
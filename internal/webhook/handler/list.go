@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/query"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// GetWebhooks godoc
+// @Summary      Get webhooks
+// @Description  Get a paginated list of webhooks (ADMIN only)
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        url query string false "Filter by URL; also url[ne|in|nin|contains]"
+// @Param        events query string false "Only webhooks subscribed to this event pattern; also events[in]"
+// @Param        is-active query bool false "Filter by status"
+// @Param        created-at[gte] query string false "Created on or after (RFC 3339 or YYYY-MM-DD); also gt, lt, lte"
+// @Param        page query int false "Page number" default(1)
+// @Param        per-page query int false "Items per page" default(10)
+// @Success      200 {object} response.PaginatedResponse{data=[]dto.WebhookResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *fiber.Ctx) error {
+	page, perPage := pagination.ParsePage(c)
+
+	q, err := webhookQuerySchema.Parse(c.Queries())
+	if err != nil {
+		return response.BadRequest(c, "invalid query", err.Error())
+	}
+
+	webhooks, total, err := h.webhookService.GetAll(c.Context(), q.Filter, page, perPage)
+	if err != nil {
+		return response.InternalServerError(c, "failed to get webhooks")
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "webhooks retrieved successfully", webhooks, page, perPage, total)
+}
+
+// webhookQuerySchema whitelists the fields clients may filter webhooks by
+var webhookQuerySchema = query.Schema{
+	Fields: map[string]query.Field{
+		"url":        {Name: "url", Type: query.String},
+		"events":     {Name: "events", Type: query.String, Operators: []query.Operator{query.OpEq, query.OpIn}},
+		"is-active":  {Name: "isActive", Type: query.Bool},
+		"created-at": {Name: "createdAt", Type: query.Time},
+	},
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/query"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// GetDeliveries godoc
+// @Summary      Get webhook deliveries
+// @Description  Get a webhook's delivery log, newest first (ADMIN only)
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Webhook ID"
+// @Param        status query string false "Only deliveries with this status; also status[ne|in|nin]" Enums(pending, succeeded, failed)
+// @Param        event-type query string false "Only deliveries of this event type; also event-type[ne|in|nin]"
+// @Param        event-id query string false "Only deliveries of this event"
+// @Param        created-at[gte] query string false "Created on or after (RFC 3339 or YYYY-MM-DD); also gt, lt, lte"
+// @Param        page query int false "Page number" default(1)
+// @Param        per-page query int false "Items per page" default(10)
+// @Success      200 {object} response.PaginatedResponse{data=[]dto.DeliveryResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *fiber.Ctx) error {
+	page, perPage := pagination.ParsePage(c)
+
+	q, err := deliveryQuerySchema.Parse(c.Queries())
+	if err != nil {
+		return response.BadRequest(c, "invalid query", err.Error())
+	}
+
+	deliveries, total, err := h.webhookService.GetDeliveries(c.Context(), c.Params("id"), q.Filter, page, perPage)
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			return response.NotFound(c, "webhook not found")
+		}
+		return response.InternalServerError(c, "failed to get webhook deliveries")
+	}
+
+	return response.Paginated(c, fiber.StatusOK, "webhook deliveries retrieved successfully", deliveries, page, perPage, total)
+}
+
+// deliveryQuerySchema whitelists the fields clients may filter deliveries by
+var deliveryQuerySchema = query.Schema{
+	Fields: map[string]query.Field{
+		"status": {
+			Name:      "status",
+			Type:      query.String,
+			Operators: []query.Operator{query.OpEq, query.OpNe, query.OpIn, query.OpNin},
+			Values:    []string{string(entity.DeliveryPending), string(entity.DeliverySucceeded), string(entity.DeliveryFailed)},
+		},
+		"event-type": {Name: "eventType", Type: query.String, Operators: []query.Operator{query.OpEq, query.OpNe, query.OpIn, query.OpNin}},
+		"event-id":   {Name: "eventId", Type: query.String, Operators: []query.Operator{query.OpEq}},
+		"created-at": {Name: "createdAt", Type: query.Time},
+	},
+}
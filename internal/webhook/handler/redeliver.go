@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+)
+
+// Redeliver godoc
+// @Summary      Redeliver webhook
+// @Description  Queue a delivery to be sent again with the same payload and event ID, with a fresh retry budget (ADMIN only)
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Webhook ID"
+// @Param        deliveryId path string true "Delivery ID"
+// @Success      202 {object} response.Response{data=dto.DeliveryResponse}
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	delivery, err := h.webhookService.Redeliver(c.Context(), c.Params("id"), c.Params("deliveryId"))
+	if err != nil {
+		if errors.Is(err, service.ErrDeliveryNotFound) {
+			return response.NotFound(c, "webhook delivery not found")
+		}
+		return response.InternalServerError(c, "failed to redeliver webhook")
+	}
+
+	return response.Success(c, fiber.StatusAccepted, "webhook redelivery queued", delivery)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/service"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/response"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/validator"
+)
+
+// UpdateWebhook godoc
+// @Summary      Update webhook
+// @Description  Change a webhook's URL, events, description or state, or rotate its secret (ADMIN only). A rotated secret is returned once
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Webhook ID"
+// @Param        request body dto.UpdateWebhookRequest true "Update webhook request"
+// @Success      200 {object} response.Response{data=dto.WebhookResponse}
+// @Failure      400 {object} response.Response
+// @Failure      401 {object} response.Response
+// @Failure      403 {object} response.Response
+// @Failure      404 {object} response.Response
+// @Failure      500 {object} response.Response
+// @Router       /webhooks/{id} [patch]
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	var req dto.UpdateWebhookRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.BadRequest(c, "invalid request body", validator.FormatValidationErrors(err))
+	}
+
+	webhook, err := h.webhookService.Update(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			return response.NotFound(c, "webhook not found")
+		}
+		if errors.Is(err, service.ErrForbiddenURL) {
+			return response.BadRequest(c, "invalid webhook url", err.Error())
+		}
+		return response.InternalServerError(c, "failed to update webhook")
+	}
+
+	return response.Success(c, fiber.StatusOK, "webhook updated successfully", webhook)
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/database/migration"
+)
+
+// deliveryRetention is how long the delivery log is kept
+const deliveryRetention = 30 * 24 * time.Hour
+
+// Migrations returns the webhook module's database migrations
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		migration.CreateIndexes(2026101908, "webhook_deliveries_indexes", "webhook_deliveries",
+			mongo.IndexModel{
+				// An event is queued at most once per webhook
+				Keys:    bson.D{{Key: "webhookId", Value: 1}, {Key: "eventId", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			mongo.IndexModel{
+				// Claiming due deliveries
+				Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+			},
+			mongo.IndexModel{
+				// Delivery log, newest first
+				Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "createdAt", Value: 1}},
+				Options: options.Index().SetName("webhook_deliveries_ttl").SetExpireAfterSeconds(int32(deliveryRetention.Seconds())),
+			},
+		),
+	}
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+var (
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrDeliveryExists means the event was already queued for the webhook
+	ErrDeliveryExists = errors.New("webhook delivery already exists")
+)
+
+// DeliveryRepository defines the interface for webhook delivery data access
+type DeliveryRepository interface {
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) error
+	FindByID(ctx context.Context, webhookID, id string) (*entity.WebhookDelivery, error)
+	// FindByWebhook returns a page of a webhook's deliveries matching filter,
+	// newest first
+	FindByWebhook(ctx context.Context, webhookID string, filter bson.M, page, pageSize int) ([]*entity.WebhookDelivery, int64, error)
+	// ClaimDue leases up to limit pending deliveries whose next attempt is
+	// due, counting the attempt, so concurrent workers don't send them twice
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error)
+	// SaveAttempt stores the outcome of an attempt
+	SaveAttempt(ctx context.Context, delivery *entity.WebhookDelivery) error
+	// Redeliver makes a delivery pending again with a fresh retry budget
+	Redeliver(ctx context.Context, webhookID, id string) (*entity.WebhookDelivery, error)
+	DeleteByWebhook(ctx context.Context, webhookID string) error
+}
+
+type deliveryRepositoryMongo struct {
+	collection *mongo.Collection
+}
+
+// NewDeliveryRepository creates a new MongoDB webhook delivery repository
+func NewDeliveryRepository(db *database.MongoDB) DeliveryRepository {
+	return &deliveryRepositoryMongo{
+		collection: db.Collection("webhook_deliveries"),
+	}
+}
+
+func (r *deliveryRepositoryMongo) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	now := time.Now()
+	delivery.ID = bson.NewObjectID()
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, delivery)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDeliveryExists
+	}
+	return err
+}
+
+func (r *deliveryRepositoryMongo) FindByID(ctx context.Context, webhookID, id string) (*entity.WebhookDelivery, error) {
+	filter, err := deliveryFilter(webhookID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var delivery entity.WebhookDelivery
+	err = r.collection.FindOne(ctx, filter).Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (r *deliveryRepositoryMongo) FindByWebhook(ctx context.Context, webhookID string, filter bson.M, page, pageSize int) ([]*entity.WebhookDelivery, int64, error) {
+	objectID, err := bson.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, 0, ErrWebhookNotFound
+	}
+
+	scoped := bson.M{}
+	maps.Copy(scoped, filter)
+	scoped["webhookId"] = objectID
+	filter = scoped
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []*entity.WebhookDelivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+func (r *deliveryRepositoryMongo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error) {
+	deliveries := make([]*entity.WebhookDelivery, 0, limit)
+	for len(deliveries) < limit {
+		now := time.Now()
+		filter := bson.M{
+			"status":        entity.DeliveryPending,
+			"nextAttemptAt": bson.M{"$lte": now},
+		}
+		update := bson.M{
+			"$set": bson.M{"nextAttemptAt": now.Add(lease), "updatedAt": now},
+			"$inc": bson.M{"attempts": 1},
+		}
+		opts := options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+			SetReturnDocument(options.After)
+
+		var delivery entity.WebhookDelivery
+		err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return deliveries, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (r *deliveryRepositoryMongo) SaveAttempt(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+
+	set := bson.M{
+		"status":         delivery.Status,
+		"nextAttemptAt":  delivery.NextAttemptAt,
+		"lastAttemptAt":  delivery.LastAttemptAt,
+		"responseStatus": delivery.ResponseStatus,
+		"lastError":      delivery.LastError,
+		"updatedAt":      delivery.UpdatedAt,
+	}
+	if delivery.DeliveredAt != nil {
+		set["deliveredAt"] = delivery.DeliveredAt
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": delivery.ID}, bson.M{"$set": set})
+	return err
+}
+
+func (r *deliveryRepositoryMongo) Redeliver(ctx context.Context, webhookID, id string) (*entity.WebhookDelivery, error) {
+	filter, err := deliveryFilter(webhookID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	// The outcome of the previous run no longer describes the delivery
+	update := bson.M{
+		"$set": bson.M{
+			"status":        entity.DeliveryPending,
+			"attempts":      0,
+			"nextAttemptAt": now,
+			"updatedAt":     now,
+		},
+		"$unset": bson.M{"deliveredAt": "", "lastError": "", "responseStatus": ""},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var delivery entity.WebhookDelivery
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (r *deliveryRepositoryMongo) DeleteByWebhook(ctx context.Context, webhookID string) error {
+	objectID, err := bson.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return ErrWebhookNotFound
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"webhookId": objectID})
+	return err
+}
+
+func deliveryFilter(webhookID, id string) (bson.M, error) {
+	webhookObjectID, err := bson.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, ErrDeliveryNotFound
+	}
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrDeliveryNotFound
+	}
+	return bson.M{"_id": objectID, "webhookId": webhookObjectID}, nil
+}
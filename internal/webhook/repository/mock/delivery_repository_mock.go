@@ -0,0 +1,49 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// MockDeliveryRepository is a mock implementation of repository.DeliveryRepository
+type MockDeliveryRepository struct {
+	CreateFunc          func(ctx context.Context, delivery *entity.WebhookDelivery) error
+	FindByIDFunc        func(ctx context.Context, webhookID, id string) (*entity.WebhookDelivery, error)
+	FindByWebhookFunc   func(ctx context.Context, webhookID string, filter bson.M, page, pageSize int) ([]*entity.WebhookDelivery, int64, error)
+	ClaimDueFunc        func(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error)
+	SaveAttemptFunc     func(ctx context.Context, delivery *entity.WebhookDelivery) error
+	RedeliverFunc       func(ctx context.Context, webhookID, id string) (*entity.WebhookDelivery, error)
+	DeleteByWebhookFunc func(ctx context.Context, webhookID string) error
+}
+
+func (m *MockDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return m.CreateFunc(ctx, delivery)
+}
+
+func (m *MockDeliveryRepository) FindByID(ctx context.Context, webhookID, id string) (*entity.WebhookDelivery, error) {
+	return m.FindByIDFunc(ctx, webhookID, id)
+}
+
+func (m *MockDeliveryRepository) FindByWebhook(ctx context.Context, webhookID string, filter bson.M, page, pageSize int) ([]*entity.WebhookDelivery, int64, error) {
+	return m.FindByWebhookFunc(ctx, webhookID, filter, page, pageSize)
+}
+
+func (m *MockDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error) {
+	return m.ClaimDueFunc(ctx, limit, lease)
+}
+
+func (m *MockDeliveryRepository) SaveAttempt(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return m.SaveAttemptFunc(ctx, delivery)
+}
+
+func (m *MockDeliveryRepository) Redeliver(ctx context.Context, webhookID, id string) (*entity.WebhookDelivery, error) {
+	return m.RedeliverFunc(ctx, webhookID, id)
+}
+
+func (m *MockDeliveryRepository) DeleteByWebhook(ctx context.Context, webhookID string) error {
+	return m.DeleteByWebhookFunc(ctx, webhookID)
+}
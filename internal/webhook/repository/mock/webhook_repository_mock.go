@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+// MockWebhookRepository is a mock implementation of repository.WebhookRepository
+type MockWebhookRepository struct {
+	CreateFunc     func(ctx context.Context, webhook *entity.Webhook) error
+	FindByIDFunc   func(ctx context.Context, id string) (*entity.Webhook, error)
+	FindAllFunc    func(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.Webhook, int64, error)
+	FindActiveFunc func(ctx context.Context) ([]*entity.Webhook, error)
+	UpdateFunc     func(ctx context.Context, webhook *entity.Webhook) error
+	DeleteFunc     func(ctx context.Context, id string) error
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	return m.CreateFunc(ctx, webhook)
+}
+
+func (m *MockWebhookRepository) FindByID(ctx context.Context, id string) (*entity.Webhook, error) {
+	return m.FindByIDFunc(ctx, id)
+}
+
+func (m *MockWebhookRepository) FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.Webhook, int64, error) {
+	return m.FindAllFunc(ctx, filter, page, pageSize)
+}
+
+func (m *MockWebhookRepository) FindActive(ctx context.Context) ([]*entity.Webhook, error) {
+	return m.FindActiveFunc(ctx)
+}
+
+func (m *MockWebhookRepository) Update(ctx context.Context, webhook *entity.Webhook) error {
+	return m.UpdateFunc(ctx, webhook)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id string) error {
+	return m.DeleteFunc(ctx, id)
+}
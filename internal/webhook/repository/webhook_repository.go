@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// WebhookRepository defines the interface for webhook data access
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *entity.Webhook) error
+	FindByID(ctx context.Context, id string) (*entity.Webhook, error)
+	// FindAll returns a page of the webhooks matching filter, newest first
+	FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.Webhook, int64, error)
+	// FindActive returns every active webhook. There are few enough to
+	// match event types in memory.
+	FindActive(ctx context.Context) ([]*entity.Webhook, error)
+	Update(ctx context.Context, webhook *entity.Webhook) error
+	Delete(ctx context.Context, id string) error
+}
+
+type webhookRepositoryMongo struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookRepository creates a new MongoDB webhook repository
+func NewWebhookRepository(db *database.MongoDB) WebhookRepository {
+	return &webhookRepositoryMongo{
+		collection: db.Collection("webhooks"),
+	}
+}
+
+func (r *webhookRepositoryMongo) Create(ctx context.Context, webhook *entity.Webhook) error {
+	now := time.Now()
+	webhook.ID = bson.NewObjectID()
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, webhook)
+	return err
+}
+
+func (r *webhookRepositoryMongo) FindByID(ctx context.Context, id string) (*entity.Webhook, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	var webhook entity.Webhook
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&webhook)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *webhookRepositoryMongo) FindAll(ctx context.Context, filter bson.M, page, pageSize int) ([]*entity.Webhook, int64, error) {
+	opts := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var webhooks []*entity.Webhook
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return webhooks, total, nil
+}
+
+func (r *webhookRepositoryMongo) FindActive(ctx context.Context) ([]*entity.Webhook, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"isActive": true})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var webhooks []*entity.Webhook
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepositoryMongo) Update(ctx context.Context, webhook *entity.Webhook) error {
+	webhook.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": webhook.ID},
+		bson.M{"$set": bson.M{
+			"url":         webhook.URL,
+			"events":      webhook.Events,
+			"secret":      webhook.Secret,
+			"description": webhook.Description,
+			"isActive":    webhook.IsActive,
+			"updatedAt":   webhook.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepositoryMongo) Delete(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrWebhookNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
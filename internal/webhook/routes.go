@@ -0,0 +1,22 @@
+package webhook
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/middleware"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/handler"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/token"
+)
+
+// RegisterRoutes registers all webhook routes (ADMIN only)
+func RegisterRoutes(router fiber.Router, h *handler.WebhookHandler, tokenMaker *token.PasetoMaker) {
+	webhooks := router.Group("/webhooks", middleware.AuthMiddleware(tokenMaker), middleware.RequireAdmin())
+
+	webhooks.Get("/", h.GetWebhooks)
+	webhooks.Post("/", h.CreateWebhook)
+	webhooks.Get("/:id", h.GetWebhookByID)
+	webhooks.Patch("/:id", h.UpdateWebhook)
+	webhooks.Delete("/:id", h.DeleteWebhook)
+	webhooks.Get("/:id/deliveries", h.GetDeliveries)
+	webhooks.Post("/:id/deliveries/:deliveryId/redeliver", h.Redeliver)
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/netguard"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrForbiddenURL is returned for URLs that don't resolve to public
+	// addresses, such as localhost, private networks or metadata endpoints
+	ErrForbiddenURL = errors.New("webhook URL must resolve to a public address")
+)
+
+// WebhookService defines the interface for webhook operations
+type WebhookService interface {
+	Create(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.WebhookResponse, error)
+	GetAll(ctx context.Context, filter bson.M, page, pageSize int) ([]dto.WebhookResponse, int64, error)
+	Update(ctx context.Context, id string, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error)
+	Delete(ctx context.Context, id string) error
+	GetDeliveries(ctx context.Context, webhookID string, filter bson.M, page, pageSize int) ([]dto.DeliveryResponse, int64, error)
+	Redeliver(ctx context.Context, webhookID, deliveryID string) (*dto.DeliveryResponse, error)
+	// HandleEvent queues a delivery of evt for every active webhook that
+	// subscribed to it. Subscribe it to the event dispatcher.
+	HandleEvent(ctx context.Context, evt event.Event) error
+}
+
+type webhookServiceImpl struct {
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.DeliveryRepository
+	allowPrivate bool
+}
+
+// NewWebhookService creates a new webhook service. Unless allowPrivate is
+// set, webhook URLs must resolve to public addresses.
+func NewWebhookService(webhookRepo repository.WebhookRepository, deliveryRepo repository.DeliveryRepository, allowPrivate bool) WebhookService {
+	return &webhookServiceImpl{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		allowPrivate: allowPrivate,
+	}
+}
+
+func (s *webhookServiceImpl) Create(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	if err := s.checkURL(ctx, req.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		logger.Error("failed to generate webhook secret", zap.Error(err))
+		return nil, err
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	webhook := &entity.Webhook{
+		URL:         req.URL,
+		Events:      req.Events,
+		Secret:      secret,
+		Description: req.Description,
+		IsActive:    isActive,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		logger.Error("failed to create webhook", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("webhook created", zap.String("webhook_id", webhook.ID.Hex()))
+
+	response := dto.ToWebhookResponse(webhook)
+	response.Secret = secret
+	return &response, nil
+}
+
+func (s *webhookServiceImpl) GetByID(ctx context.Context, id string) (*dto.WebhookResponse, error) {
+	webhook, err := s.findWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToWebhookResponse(webhook)
+	return &response, nil
+}
+
+func (s *webhookServiceImpl) GetAll(ctx context.Context, filter bson.M, page, pageSize int) ([]dto.WebhookResponse, int64, error) {
+	webhooks, total, err := s.webhookRepo.FindAll(ctx, filter, page, pageSize)
+	if err != nil {
+		logger.Error("failed to get webhooks", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return dto.ToWebhookResponses(webhooks), total, nil
+}
+
+func (s *webhookServiceImpl) Update(ctx context.Context, id string, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	webhook, err := s.findWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := s.checkURL(ctx, *req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		webhook.Events = req.Events
+	}
+	if req.Description != nil {
+		webhook.Description = *req.Description
+	}
+	if req.IsActive != nil {
+		webhook.IsActive = *req.IsActive
+	}
+	if req.RotateSecret {
+		if webhook.Secret, err = generateSecret(); err != nil {
+			logger.Error("failed to generate webhook secret", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		logger.Error("failed to update webhook", zap.Error(err), zap.String("webhook_id", id))
+		return nil, err
+	}
+
+	logger.Info("webhook updated", zap.String("webhook_id", id), zap.Bool("secret_rotated", req.RotateSecret))
+
+	response := dto.ToWebhookResponse(webhook)
+	if req.RotateSecret {
+		response.Secret = webhook.Secret
+	}
+	return &response, nil
+}
+
+func (s *webhookServiceImpl) Delete(ctx context.Context, id string) error {
+	if err := s.webhookRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return ErrWebhookNotFound
+		}
+		logger.Error("failed to delete webhook", zap.Error(err), zap.String("webhook_id", id))
+		return err
+	}
+
+	// Pending deliveries would fail anyway; drop the log with the webhook
+	if err := s.deliveryRepo.DeleteByWebhook(ctx, id); err != nil {
+		logger.Error("failed to delete webhook deliveries", zap.Error(err), zap.String("webhook_id", id))
+	}
+
+	logger.Info("webhook deleted", zap.String("webhook_id", id))
+	return nil
+}
+
+func (s *webhookServiceImpl) GetDeliveries(ctx context.Context, webhookID string, filter bson.M, page, pageSize int) ([]dto.DeliveryResponse, int64, error) {
+	if _, err := s.findWebhook(ctx, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, total, err := s.deliveryRepo.FindByWebhook(ctx, webhookID, filter, page, pageSize)
+	if err != nil {
+		logger.Error("failed to get webhook deliveries", zap.Error(err), zap.String("webhook_id", webhookID))
+		return nil, 0, err
+	}
+
+	return dto.ToDeliveryResponses(deliveries), total, nil
+}
+
+func (s *webhookServiceImpl) Redeliver(ctx context.Context, webhookID, deliveryID string) (*dto.DeliveryResponse, error) {
+	delivery, err := s.deliveryRepo.Redeliver(ctx, webhookID, deliveryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeliveryNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		logger.Error("failed to redeliver webhook", zap.Error(err), zap.String("delivery_id", deliveryID))
+		return nil, err
+	}
+
+	logger.Info("webhook redelivery queued", zap.String("delivery_id", deliveryID))
+
+	response := dto.ToDeliveryResponse(delivery)
+	return &response, nil
+}
+
+func (s *webhookServiceImpl) HandleEvent(ctx context.Context, evt event.Event) error {
+	webhooks, err := s.webhookRepo.FindActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(evt.Type) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = json.Marshal(evt); err != nil {
+				return err
+			}
+		}
+
+		delivery := &entity.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       evt.ID,
+			EventType:     evt.Type,
+			Payload:       string(payload),
+			Status:        entity.DeliveryPending,
+			NextAttemptAt: time.Now(),
+		}
+		// The event may be handled again after a relay retry
+		if err := s.deliveryRepo.Create(ctx, delivery); err != nil && !errors.Is(err, repository.ErrDeliveryExists) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *webhookServiceImpl) findWebhook(ctx context.Context, id string) (*entity.Webhook, error) {
+	webhook, err := s.webhookRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		logger.Error("failed to find webhook", zap.Error(err), zap.String("webhook_id", id))
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// checkURL rejects URLs the sender would refuse to deliver to. The sender
+// checks again on every connection, since DNS answers can change.
+func (s *webhookServiceImpl) checkURL(ctx context.Context, url string) error {
+	if s.allowPrivate {
+		return nil
+	}
+	if err := netguard.CheckURL(ctx, url); err != nil {
+		return fmt.Errorf("%w: %v", ErrForbiddenURL, err)
+	}
+	return nil
+}
+
+// generateSecret returns a random signing secret
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/internal/event"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/dto"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/repository"
+	"github.com/itsahyarr/gofiber-boilerplate/internal/webhook/repository/mock"
+	"github.com/itsahyarr/gofiber-boilerplate/shared/entity"
+)
+
+func TestHandleEvent_QueuesForSubscribedWebhooks(t *testing.T) {
+	// 1. Setup Mocks: exact, prefix and unrelated subscriptions; the prefix
+	// webhook already has this event queued from an earlier relay attempt
+	exact := &entity.Webhook{ID: bson.NewObjectID(), Events: []string{"user.created"}, IsActive: true}
+	prefix := &entity.Webhook{ID: bson.NewObjectID(), Events: []string{"user.*"}, IsActive: true}
+	other := &entity.Webhook{ID: bson.NewObjectID(), Events: []string{"order.created"}, IsActive: true}
+
+	var queued []bson.ObjectID
+	webhookRepo := &mock.MockWebhookRepository{
+		FindActiveFunc: func(ctx context.Context) ([]*entity.Webhook, error) {
+			return []*entity.Webhook{exact, prefix, other}, nil
+		},
+	}
+	deliveryRepo := &mock.MockDeliveryRepository{
+		CreateFunc: func(ctx context.Context, delivery *entity.WebhookDelivery) error {
+			queued = append(queued, delivery.WebhookID)
+			if delivery.WebhookID == prefix.ID {
+				return repository.ErrDeliveryExists
+			}
+			return nil
+		},
+	}
+
+	service := NewWebhookService(webhookRepo, deliveryRepo, false)
+
+	// 2. Call Method
+	err := service.HandleEvent(context.Background(), event.Event{ID: "evt-1", Type: "user.created"})
+
+	// 3. Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, []bson.ObjectID{exact.ID, prefix.ID}, queued)
+}
+
+func TestCreate_RejectsPrivateURLs(t *testing.T) {
+	urls := []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.0.0.5/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+	}
+
+	for _, url := range urls {
+		t.Run(url, func(t *testing.T) {
+			// 1. Setup Mocks
+			created := false
+			webhookRepo := &mock.MockWebhookRepository{
+				CreateFunc: func(ctx context.Context, webhook *entity.Webhook) error {
+					created = true
+					return nil
+				},
+			}
+			service := NewWebhookService(webhookRepo, &mock.MockDeliveryRepository{}, false)
+
+			// 2. Call Method
+			_, err := service.Create(context.Background(), &dto.CreateWebhookRequest{URL: url, Events: []string{"*"}})
+
+			// 3. Assertions
+			assert.ErrorIs(t, err, ErrForbiddenURL)
+			assert.False(t, created)
+		})
+	}
+}
+
+func TestUpdate_RejectsPrivateURL(t *testing.T) {
+	// 1. Setup Mocks
+	webhook := &entity.Webhook{ID: bson.NewObjectID(), URL: "https://example.com/hook", IsActive: true}
+	webhookRepo := &mock.MockWebhookRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Webhook, error) {
+			return webhook, nil
+		},
+	}
+	service := NewWebhookService(webhookRepo, &mock.MockDeliveryRepository{}, false)
+	url := "http://192.168.1.10/hook"
+
+	// 2. Call Method
+	_, err := service.Update(context.Background(), webhook.ID.Hex(), &dto.UpdateWebhookRequest{URL: &url})
+
+	// 3. Assertions
+	assert.ErrorIs(t, err, ErrForbiddenURL)
+	assert.Equal(t, "https://example.com/hook", webhook.URL)
+}
@@ -0,0 +1,78 @@
+package clientip
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Resolver finds the IP of the client behind a chain of trusted proxies.
+// Proxies append the address they received a request from to a header such
+// as X-Forwarded-For, so only the entries added by trusted proxies can be
+// believed; anything further left was sent by the client and may be forged.
+type Resolver struct {
+	header  string
+	trusted []netip.Prefix
+}
+
+// New creates a resolver reading header on requests from the trusted proxies,
+// given as IPs or CIDR ranges. Without a header the connection's address is
+// always used.
+func New(header string, trusted []string) (*Resolver, error) {
+	r := &Resolver{header: header}
+	for _, proxy := range trusted {
+		prefix, err := parsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		r.trusted = append(r.trusted, prefix)
+	}
+	return r, nil
+}
+
+func parsePrefix(proxy string) (netip.Prefix, error) {
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// IP returns the client IP of the request: the right-most header entry that
+// isn't a trusted proxy, walking back from the connection's address
+func (r *Resolver) IP(c *fiber.Ctx) string {
+	remote, _ := netip.AddrFromSlice(c.Context().RemoteIP())
+	client := remote.Unmap()
+	if r.header == "" || !r.isTrusted(client) {
+		return client.String()
+	}
+
+	hops := strings.Split(c.Get(r.header), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// Entries left of a malformed one can't be attributed to a proxy
+			break
+		}
+		client = hop.Unmap()
+		if !r.isTrusted(client) {
+			break
+		}
+	}
+	return client.String()
+}
+
+func (r *Resolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
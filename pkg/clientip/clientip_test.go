@@ -0,0 +1,66 @@
+package clientip
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resolve returns the client IP of a test request carrying forwarded. Test
+// requests come from 0.0.0.0.
+func resolve(t *testing.T, resolver *Resolver, forwarded string) string {
+	t.Helper()
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(resolver.IP(c))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if forwarded != "" {
+		req.Header.Set(fiber.HeaderXForwardedFor, forwarded)
+	}
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestResolver_IP(t *testing.T) {
+	trusted, err := New(fiber.HeaderXForwardedFor, []string{"0.0.0.0", "10.0.0.0/8"})
+	require.NoError(t, err)
+	untrusted, err := New(fiber.HeaderXForwardedFor, []string{"192.0.2.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		resolver  *Resolver
+		forwarded string
+		want      string
+	}{
+		{"no header", trusted, "", "0.0.0.0"},
+		{"single hop", trusted, "203.0.113.7", "203.0.113.7"},
+		{"forged entries are skipped", trusted, "198.51.100.1, 203.0.113.7", "203.0.113.7"},
+		{"trusted hops are walked back", trusted, "198.51.100.1, 203.0.113.7, 10.0.0.2, 10.0.0.1", "203.0.113.7"},
+		{"malformed entry stops the walk", trusted, "198.51.100.1, bogus, 10.0.0.1", "10.0.0.1"},
+		{"untrusted connection ignores the header", untrusted, "203.0.113.7", "0.0.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolve(t, tt.resolver, tt.forwarded))
+		})
+	}
+}
+
+func TestNew_InvalidProxy(t *testing.T) {
+	_, err := New(fiber.HeaderXForwardedFor, []string{"10.0.0.0/33"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2026, 10, 21, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 21, 10, 18, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2026, 10, 21, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 21, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 21, 10, 30, 0, 0, time.UTC)},
+		{"30 3 * * *", time.Date(2026, 10, 22, 3, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, 10, 21, 13, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// Day of month or day of week when both are restricted
+		{"0 0 30 * 5", time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@every 1h", time.Date(2026, 10, 21, 11, 0, 0, 0, time.UTC)},
+		{"@every 10m", time.Date(2026, 10, 21, 10, 20, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+		})
+	}
+}
+
+func TestNext_HalfHourOffset(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	from := time.Date(2026, 10, 21, 10, 17, 30, 0, kolkata)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 * * * *", time.Date(2026, 10, 21, 11, 0, 0, 0, kolkata)},
+		{"0 9-17/4 * * *", time.Date(2026, 10, 21, 13, 0, 0, 0, kolkata)},
+		{"30 3 * * *", time.Date(2026, 10, 22, 3, 30, 0, 0, kolkata)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+		})
+	}
+}
+
+func TestNext_DaylightSaving(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	edt := time.FixedZone("EDT", -4*60*60)
+	est := time.FixedZone("EST", -5*60*60)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		// 2026-03-08 02:00 EST jumps to 03:00 EDT
+		{"hourly across the gap", "0 * * * *", time.Date(2026, 3, 8, 1, 30, 0, 0, est), time.Date(2026, 3, 8, 3, 0, 0, 0, edt)},
+		{"skipped time runs the next day", "30 2 * * *", time.Date(2026, 3, 8, 0, 0, 0, 0, est), time.Date(2026, 3, 9, 2, 30, 0, 0, edt)},
+		// 2026-11-01 02:00 EDT falls back to 01:00 EST
+		{"repeated hour runs once", "0 1 * * *", time.Date(2026, 11, 1, 1, 0, 0, 0, edt), time.Date(2026, 11, 2, 1, 0, 0, 0, est)},
+		{"inside the repeated hour", "45 * * * *", time.Date(2026, 11, 1, 1, 30, 0, 0, est), time.Date(2026, 11, 1, 1, 45, 0, 0, est)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			require.NoError(t, err)
+			next := schedule.Next(tt.from.In(newYork))
+			assert.True(t, tt.want.Equal(next), "want %s, got %s", tt.want, next)
+		})
+	}
+}
+
+func TestNext_Impossible(t *testing.T) {
+	schedule, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 0s",
+		"@every soon",
+		"@sometimes",
+	} {
+		_, err := Parse(expr)
+		assert.ErrorIs(t, err, ErrInvalidSchedule, expr)
+	}
+}
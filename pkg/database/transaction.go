@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+)
+
+// TxManager runs units of work atomically. Repositories don't need to know
+// about it: the MongoDB driver picks the session up from the context, so any
+// repository call made with the context passed to fn joins the transaction.
+type TxManager interface {
+	// WithinTransaction runs fn in a transaction and commits it if fn returns
+	// nil. fn may be called more than once when the transaction is retried,
+	// so it must not keep state from a failed attempt. Calls nested inside
+	// fn join the outer transaction.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// afterCommitKey carries the hooks registered with AfterCommit during a
+// transaction
+type afterCommitKey struct{}
+
+// AfterCommit runs fn once the transaction carried by ctx has committed, or
+// right away outside a transaction. Use it for side effects outside MongoDB,
+// such as cache invalidation, that must not be seen before the write is.
+// Hooks of a transaction that is rolled back never run.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if hooks, ok := ctx.Value(afterCommitKey{}).(*[]func(context.Context)); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn(ctx)
+}
+
+// InTransaction reports whether ctx carries a MongoDB transaction
+func InTransaction(ctx context.Context) bool {
+	session := mongo.SessionFromContext(ctx)
+	return session != nil && session.ClientSession().TransactionRunning()
+}
+
+type mongoTxManager struct {
+	db *MongoDB
+}
+
+// NewTxManager creates a TxManager backed by MongoDB sessions. Transient
+// transaction errors and unknown commit results are retried by the driver.
+// A standalone server can't run transactions, so the deployment is probed
+// once and units of work run without one there.
+func NewTxManager(db *MongoDB) (TxManager, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	supported, err := supportsTransactions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		logger.Warn("transactions not supported by this deployment, running without them")
+		return NewNoopTxManager(), nil
+	}
+	return &mongoTxManager{db: db}, nil
+}
+
+// supportsTransactions asks the server what it is: only replica set members
+// (which report a setName) and mongos routers support transactions
+func supportsTransactions(ctx context.Context, db *MongoDB) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := db.Client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, fmt.Errorf("probe transaction support: %w", err)
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
+
+func (m *mongoTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if InTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	session, err := m.db.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	var hooks []func(context.Context)
+	_, err = session.WithTransaction(ctx, func(sessCtx context.Context) (any, error) {
+		// A retried attempt registers its hooks again
+		hooks = nil
+		return nil, fn(context.WithValue(sessCtx, afterCommitKey{}, &hooks))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+	return nil
+}
+
+type noopTxManager struct{}
+
+// NewNoopTxManager creates a TxManager that simply calls fn, for unit tests
+// and deployments without transaction support
+func NewNoopTxManager() TxManager {
+	return noopTxManager{}
+}
+
+func (noopTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
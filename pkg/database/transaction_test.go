@@ -0,0 +1,23 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAfterCommit(t *testing.T) {
+	// Outside a transaction the hook runs right away
+	ran := false
+	AfterCommit(context.Background(), func(context.Context) { ran = true })
+	assert.True(t, ran)
+
+	// Inside one it is queued for the commit
+	var hooks []func(context.Context)
+	ctx := context.WithValue(context.Background(), afterCommitKey{}, &hooks)
+	ran = false
+	AfterCommit(ctx, func(context.Context) { ran = true })
+	assert.False(t, ran)
+	assert.Len(t, hooks, 1)
+}
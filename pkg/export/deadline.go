@@ -0,0 +1,40 @@
+package export
+
+import (
+	"bufio"
+	"net"
+	"time"
+)
+
+// DeadlineWriter extends a connection's write deadline before every write.
+// Servers apply their write timeout to the whole response, so a long
+// streamed export would otherwise be cut off mid-file after the status has
+// already been sent.
+type DeadlineWriter struct {
+	buffered *bufio.Writer
+	conn     net.Conn
+	timeout  time.Duration
+}
+
+// NewDeadlineWriter wraps the response writer w of conn, allowing each write
+// to take up to timeout
+func NewDeadlineWriter(w *bufio.Writer, conn net.Conn, timeout time.Duration) *DeadlineWriter {
+	return &DeadlineWriter{buffered: w, conn: conn, timeout: timeout}
+}
+
+func (d *DeadlineWriter) Write(p []byte) (int, error) {
+	d.extend()
+	return d.buffered.Write(p)
+}
+
+// Flush writes any buffered data to the connection
+func (d *DeadlineWriter) Flush() error {
+	d.extend()
+	return d.buffered.Flush()
+}
+
+func (d *DeadlineWriter) extend() {
+	if d.conn != nil {
+		_ = d.conn.SetWriteDeadline(time.Now().Add(d.timeout))
+	}
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Format is a file format records can be exported to
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatXLSX   Format = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format, use csv, ndjson or xlsx")
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatCSV, FormatNDJSON, FormatXLSX:
+		return format, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+// Column describes one column of a tabular (CSV/XLSX) export
+type Column[T any] struct {
+	Header string
+	Value  func(record T) string
+}
+
+// Writer writes records one at a time so exports never hold the whole data
+// set in memory. Close must be called to flush the output.
+type Writer[T any] interface {
+	Write(record T) error
+	Close() error
+}
+
+// NewWriter creates a writer for format. CSV and XLSX use columns, NDJSON
+// writes each record as its JSON encoding.
+func NewWriter[T any](w io.Writer, format Format, columns []Column[T]) (Writer[T], error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatNDJSON:
+		buffered := bufio.NewWriter(w)
+		return &ndjsonWriter[T]{buffered: buffered, encoder: json.NewEncoder(buffered)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+func headers[T any](columns []Column[T]) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Header
+	}
+	return names
+}
+
+func values[T any](columns []Column[T], record T) []string {
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		row[i] = column.Value(record)
+	}
+	return row
+}
+
+type csvWriter[T any] struct {
+	writer  *csv.Writer
+	columns []Column[T]
+}
+
+func newCSVWriter[T any](w io.Writer, columns []Column[T]) (*csvWriter[T], error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(headers(columns)); err != nil {
+		return nil, err
+	}
+	return &csvWriter[T]{writer: writer, columns: columns}, nil
+}
+
+func (c *csvWriter[T]) Write(record T) error {
+	row := values(c.columns, record)
+	for i, cell := range row {
+		row[i] = escapeFormula(cell)
+	}
+	return c.writer.Write(row)
+}
+
+// escapeFormula prefixes cells that spreadsheet applications would evaluate
+// as a formula with a quote, so user-controlled values stay plain text
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func (c *csvWriter[T]) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type ndjsonWriter[T any] struct {
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+}
+
+func (n *ndjsonWriter[T]) Write(record T) error {
+	return n.encoder.Encode(record)
+}
+
+func (n *ndjsonWriter[T]) Close() error {
+	return n.buffered.Flush()
+}
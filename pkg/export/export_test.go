@@ -0,0 +1,66 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type person struct {
+	Name string `json:"name"`
+}
+
+var personColumns = []Column[person]{
+	{Header: "Name", Value: func(p person) string { return p.Name }},
+}
+
+func writeAll(t *testing.T, format Format, records ...person) []byte {
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, format, personColumns)
+	assert.NoError(t, err)
+	for _, record := range records {
+		assert.NoError(t, writer.Write(record))
+	}
+	assert.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestWriter_CSVAndNDJSON(t *testing.T) {
+	assert.Equal(t, "Name\n\"Doe, Jane\"\n", string(writeAll(t, FormatCSV, person{Name: "Doe, Jane"})))
+	assert.Equal(t, "{\"name\":\"Jane\"}\n", string(writeAll(t, FormatNDJSON, person{Name: "Jane"})))
+}
+
+func TestWriter_CSVEscapesFormulas(t *testing.T) {
+	data := writeAll(t, FormatCSV,
+		person{Name: "=HYPERLINK(\"http://evil\")"},
+		person{Name: "+1"},
+		person{Name: "-1"},
+		person{Name: "@SUM(A1)"},
+		person{Name: "\tTab"},
+		person{Name: "\rReturn"},
+		person{Name: "Jane-Doe"},
+	)
+	assert.Equal(t, "Name\n\"'=HYPERLINK(\"\"http://evil\"\")\"\n'+1\n'-1\n'@SUM(A1)\n'\tTab\n\"'\rReturn\"\nJane-Doe\n", string(data))
+}
+
+func TestWriter_XLSX(t *testing.T) {
+	data := writeAll(t, FormatXLSX, person{Name: "Jane <admin>"})
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+
+	var sheet []byte
+	for _, file := range archive.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			rc, _ := file.Open()
+			sheet, _ = io.ReadAll(rc)
+			rc.Close()
+		}
+	}
+	assert.Contains(t, string(sheet), "<t xml:space=\"preserve\">Name</t>")
+	assert.Contains(t, string(sheet), "Jane &lt;admin&gt;")
+	assert.Len(t, archive.File, 5)
+}
@@ -0,0 +1,88 @@
+package geoip
+
+import (
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Location is where an IP address is registered. Fields are empty when the
+// database doesn't know them.
+type Location struct {
+	// Country is the ISO 3166-1 alpha-2 country code
+	Country     string
+	CountryName string
+	City        string
+}
+
+// Locator resolves IP addresses to locations
+type Locator interface {
+	// Lookup returns nil for addresses that can't be located, such as
+	// private and loopback addresses
+	Lookup(ip string) (*Location, error)
+}
+
+// Reader looks addresses up in a local MaxMind DB file, such as GeoLite2
+// City or Country
+type Reader struct {
+	db *maxminddb.Reader
+}
+
+// Open opens a MaxMind DB file; Close releases it
+func Open(path string) (*Reader, error) {
+	db, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{db: db}, nil
+}
+
+// record is the part of a GeoIP2/GeoLite2 record we read
+type record struct {
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+func (r *Reader) Lookup(ip string) (*Location, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() {
+		return nil, nil
+	}
+
+	var rec record
+	if err := r.db.Lookup(addr, &rec); err != nil {
+		return nil, err
+	}
+	if rec.Country.ISOCode == "" {
+		return nil, nil
+	}
+
+	return &Location{
+		Country:     rec.Country.ISOCode,
+		CountryName: rec.Country.Names["en"],
+		City:        rec.City.Names["en"],
+	}, nil
+}
+
+// Close releases the database file
+func (r *Reader) Close() error {
+	return r.db.Close()
+}
+
+// disabled locates nothing
+type disabled struct{}
+
+func (disabled) Lookup(string) (*Location, error) {
+	return nil, nil
+}
+
+// Disabled returns a Locator that locates nothing, for when no database is
+// configured
+func Disabled() Locator {
+	return disabled{}
+}
@@ -0,0 +1,77 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// ErrForbiddenAddress is returned for targets on loopback, private,
+// link-local (including cloud metadata endpoints) and other non-public
+// addresses
+var ErrForbiddenAddress = errors.New("address is not publicly routable")
+
+// sharedAddressSpace is carrier-grade NAT space (RFC 6598), which is private
+// but not reported by netip.Addr.IsPrivate
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPublic reports whether ip is a publicly routable unicast address
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() &&
+		ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// CheckURL resolves the host of rawURL and fails if any of its addresses is
+// not public. It catches misconfigured targets early; Dialer must still be
+// used at request time, since DNS answers can change.
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := u.Hostname()
+
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !IsPublic(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", host, err)
+	}
+	for _, ip := range addrs {
+		if !IsPublic(ip) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, host, ip)
+		}
+	}
+	return nil
+}
+
+// Dialer returns a copy of base that refuses to connect to non-public
+// addresses. The check runs on the resolved address of each connection, so
+// it also holds when a hostname is re-pointed after CheckURL.
+func Dialer(base net.Dialer) *net.Dialer {
+	base.Control = func(network, address string, _ syscall.RawConn) error {
+		addrPort, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return err
+		}
+		if !IsPublic(addrPort.Addr()) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+		}
+		return nil
+	}
+	return &base
+}
@@ -0,0 +1,56 @@
+package netguard
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"fd00:ec2::254", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsPublic(netip.MustParseAddr(tt.ip)))
+		})
+	}
+}
+
+func TestCheckURL_LiteralAddresses(t *testing.T) {
+	assert.NoError(t, CheckURL(context.Background(), "https://93.184.216.34/hook"))
+	assert.ErrorIs(t, CheckURL(context.Background(), "http://169.254.169.254/latest/meta-data"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL(context.Background(), "http://[::1]:8080/hook"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL(context.Background(), "http://localhost/hook"), ErrForbiddenAddress)
+}
+
+func TestDialer_RefusesPrivateAddresses(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no loopback listener:", err)
+	}
+	defer listener.Close()
+
+	_, err = Dialer(net.Dialer{}).Dial("tcp", listener.Addr().String())
+
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+}
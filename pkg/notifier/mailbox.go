@@ -0,0 +1,39 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MailboxSender writes each message as an .eml file to a directory instead
+// of sending it. Use it in development; the files open in any mail client.
+type MailboxSender struct {
+	dir string
+}
+
+// NewMailboxSender creates a MailboxSender writing to dir, creating it if
+// needed
+func NewMailboxSender(dir string) (*MailboxSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &MailboxSender{dir: dir}, nil
+}
+
+func (s *MailboxSender) Send(ctx context.Context, msg *Message) error {
+	now := time.Now()
+	data, err := Encode(msg, now)
+	if err != nil {
+		return err
+	}
+
+	id := msg.ID
+	if id == "" {
+		id = fmt.Sprintf("%d", now.UnixNano())
+	}
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405"), id)
+	return os.WriteFile(filepath.Join(s.dir, name), data, 0o644)
+}
@@ -0,0 +1,99 @@
+package notifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTemplates = fstest.MapFS{
+	"welcome/en.subject.tmpl": {Data: []byte("Welcome, {{.Name}}!\n")},
+	"welcome/en.text.tmpl":    {Data: []byte("Hi {{.Name}}")},
+	"welcome/en.html.tmpl":    {Data: []byte("<p>Hi {{.Name}}</p>")},
+	"welcome/id.subject.tmpl": {Data: []byte("Selamat datang, {{.Name}}!")},
+	"welcome/id.text.tmpl":    {Data: []byte("Halo {{.Name}}")},
+}
+
+func TestTemplates_RenderFallsBackToLanguageThenDefault(t *testing.T) {
+	templates, err := ParseTemplates(testTemplates, "en")
+	require.NoError(t, err)
+
+	data := map[string]any{"Name": "<Budi>"}
+
+	exact, err := templates.Render("welcome", "id", data)
+	require.NoError(t, err)
+	assert.Equal(t, "id", exact.Locale)
+	assert.Equal(t, "Selamat datang, <Budi>!", exact.Subject)
+	assert.Empty(t, exact.HTML)
+
+	language, err := templates.Render("welcome", "id_ID", data)
+	require.NoError(t, err)
+	assert.Equal(t, "id", language.Locale)
+
+	fallback, err := templates.Render("welcome", "fr", data)
+	require.NoError(t, err)
+	assert.Equal(t, "en", fallback.Locale)
+	assert.Equal(t, "Hi <Budi>", fallback.Text)
+	assert.Equal(t, "<p>Hi &lt;Budi&gt;</p>", fallback.HTML)
+
+	_, err = templates.Render("reset", "en", data)
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+}
+
+func TestParseTemplates_RejectsIncompleteTemplates(t *testing.T) {
+	_, err := ParseTemplates(fstest.MapFS{
+		"welcome/id.subject.tmpl": {Data: []byte("Halo")},
+		"welcome/id.text.tmpl":    {Data: []byte("Halo")},
+	}, "en")
+	assert.ErrorContains(t, err, `no "en" variant`)
+
+	_, err = ParseTemplates(fstest.MapFS{
+		"welcome/en.subject.tmpl": {Data: []byte("Hi")},
+	}, "en")
+	assert.ErrorContains(t, err, "needs a subject and a text part")
+}
+
+func TestEncode(t *testing.T) {
+	msg := &Message{
+		ID:      "abc123",
+		From:    "App <noreply@example.com>",
+		To:      "budi@example.com",
+		Subject: "Selamat datang ☀",
+		Text:    "Hi",
+		HTML:    "<p>Hi</p>",
+	}
+
+	data, err := Encode(msg, time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	raw := string(data)
+	assert.Contains(t, raw, "Message-ID: <abc123@example.com>\r\n")
+	assert.Contains(t, raw, "Subject: =?utf-8?q?Selamat_datang_=E2=98=80?=\r\n")
+	assert.Contains(t, raw, "Content-Type: multipart/alternative; boundary=")
+	assert.Less(t, strings.Index(raw, "text/plain"), strings.Index(raw, "text/html"))
+}
+
+func TestMailboxSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mailbox")
+	sender, err := NewMailboxSender(dir)
+	require.NoError(t, err)
+
+	err = sender.Send(context.Background(), &Message{ID: "abc123", From: "noreply@example.com", To: "budi@example.com", Subject: "Hi", Text: "Hello"})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-abc123.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "To: budi@example.com\r\n")
+	assert.True(t, strings.HasSuffix(string(content), "Hello"))
+}
@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Message is a rendered email ready to be sent. HTML is optional; when set
+// the message carries both parts as multipart/alternative.
+type Message struct {
+	// ID is used for the Message-ID header and the mailbox file name
+	ID      string
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender delivers messages to a mail transport
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// rejectedError marks a message the transport refused outright
+type rejectedError struct {
+	err error
+}
+
+func (e *rejectedError) Error() string { return e.err.Error() }
+func (e *rejectedError) Unwrap() error { return e.err }
+
+// Rejected wraps err to report that sending the message again cannot succeed,
+// e.g. an SMTP 5xx reply for an unknown recipient
+func Rejected(err error) error {
+	return &rejectedError{err: err}
+}
+
+// IsRejected reports whether err was wrapped with Rejected
+func IsRejected(err error) bool {
+	var r *rejectedError
+	return errors.As(err, &r)
+}
+
+// MemorySender keeps sent messages in memory. Use it in tests.
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []*Message
+	// Err, when set, is returned by Send instead of keeping the message
+	Err error
+}
+
+// NewMemorySender creates an empty MemorySender
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (s *MemorySender) Send(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Err != nil {
+		return s.Err
+	}
+	copied := *msg
+	s.messages = append(s.messages, &copied)
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first
+func (s *MemorySender) Messages() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Message(nil), s.messages...)
+}
@@ -0,0 +1,175 @@
+package notifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// Content is a rendered template
+type Content struct {
+	// Locale is the variant that was used, which may be a fallback
+	Locale  string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// localized is one locale variant of a template
+type localized struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// Templates renders emails from a set of templates with per-locale variants.
+//
+// Each template is a directory holding, per locale, a subject and a plain
+// text body and optionally an HTML body:
+//
+//	welcome/en.subject.tmpl
+//	welcome/en.text.tmpl
+//	welcome/en.html.tmpl
+//	welcome/id.subject.tmpl
+//	welcome/id.text.tmpl
+//
+// HTML bodies are escaped with html/template. Every template must have a
+// variant in the default locale.
+type Templates struct {
+	defaultLocale string
+	templates     map[string]map[string]*localized
+}
+
+// ParseTemplates parses every template under fsys
+func ParseTemplates(fsys fs.FS, defaultLocale string) (*Templates, error) {
+	t := &Templates{
+		defaultLocale: normalizeLocale(defaultLocale),
+		templates:     map[string]map[string]*localized{},
+	}
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".tmpl") {
+			return err
+		}
+
+		name := path.Dir(p)
+		locale, kind, ok := strings.Cut(strings.TrimSuffix(path.Base(p), ".tmpl"), ".")
+		if name == "." || !ok {
+			return fmt.Errorf("%s: expected <template>/<locale>.<subject|text|html>.tmpl", p)
+		}
+
+		src, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		variant := t.variant(name, normalizeLocale(locale))
+		switch kind {
+		case "subject":
+			variant.subject, err = texttemplate.New(p).Option("missingkey=error").Parse(strings.TrimSpace(string(src)))
+		case "text":
+			variant.text, err = texttemplate.New(p).Option("missingkey=error").Parse(string(src))
+		case "html":
+			variant.html, err = htmltemplate.New(p).Option("missingkey=error").Parse(string(src))
+		default:
+			return fmt.Errorf("%s: unknown template part %q", p, kind)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name, variants := range t.templates {
+		if _, ok := variants[t.defaultLocale]; !ok {
+			return nil, fmt.Errorf("template %s has no %q variant", name, t.defaultLocale)
+		}
+		for locale, variant := range variants {
+			if variant.subject == nil || variant.text == nil {
+				return nil, fmt.Errorf("template %s/%s needs a subject and a text part", name, locale)
+			}
+		}
+	}
+
+	return t, nil
+}
+
+func (t *Templates) variant(name, locale string) *localized {
+	variants, ok := t.templates[name]
+	if !ok {
+		variants = map[string]*localized{}
+		t.templates[name] = variants
+	}
+	variant, ok := variants[locale]
+	if !ok {
+		variant = &localized{}
+		variants[locale] = variant
+	}
+	return variant
+}
+
+// Has reports whether a template exists
+func (t *Templates) Has(name string) bool {
+	_, ok := t.templates[name]
+	return ok
+}
+
+// Render renders a template in the closest available locale: the exact
+// locale ("pt-br"), then its language ("pt"), then the default locale
+func (t *Templates) Render(name, locale string, data any) (*Content, error) {
+	variants, ok := t.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	locale = t.resolve(variants, normalizeLocale(locale))
+	variant := variants[locale]
+
+	content := &Content{Locale: locale}
+	var buf bytes.Buffer
+	if err := variant.subject.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	content.Subject = buf.String()
+
+	buf.Reset()
+	if err := variant.text.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	content.Text = buf.String()
+
+	if variant.html != nil {
+		buf.Reset()
+		if err := variant.html.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		content.HTML = buf.String()
+	}
+
+	return content, nil
+}
+
+func (t *Templates) resolve(variants map[string]*localized, locale string) string {
+	if _, ok := variants[locale]; ok {
+		return locale
+	}
+	if language, _, ok := strings.Cut(locale, "-"); ok {
+		if _, ok := variants[language]; ok {
+			return language
+		}
+	}
+	return t.defaultLocale
+}
+
+// normalizeLocale lowercases a locale and uses "-" as separator ("pt_BR" is
+// "pt-br")
+func normalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
@@ -0,0 +1,143 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// Cursor marks a position in a result set ordered by a date with _id as
+// tie-breaker. It is handed to clients as an opaque string. The key is typed
+// so a forged cursor cannot smuggle an operator document into the seek filter.
+type Cursor struct {
+	Key time.Time     `bson:"k"`
+	ID  bson.ObjectID `bson:"id"`
+}
+
+// Encode returns the opaque, URL-safe form of the cursor
+func (c Cursor) Encode() string {
+	raw, err := bson.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a cursor previously produced by Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := bson.Unmarshal(raw, &c); err != nil || c.ID.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// CursorQuery requests a page of results relative to an opaque cursor. At
+// most one of After and Before is set; neither means the first page.
+type CursorQuery struct {
+	After     string
+	Before    string
+	Limit     int
+	WithTotal bool
+}
+
+// CursorPage describes where a page of results sits in the full result set.
+// Total is only set when it was requested, since counting is the expensive
+// part of offset pagination.
+type CursorPage struct {
+	PerPage    int
+	NextCursor string
+	PrevCursor string
+	Total      *int64
+}
+
+// Keyset is a keyset pagination plan for a result set sorted by the date
+// Field and then _id, both in the same direction.
+type Keyset struct {
+	Field      string
+	Descending bool
+}
+
+// Plan returns the filter, sort and limit that fetch the page described by
+// query, along with whether the results come back in reverse order. The
+// limit is one more than the page size so Page can tell if there is more.
+func (k Keyset) Plan(filter bson.M, query CursorQuery) (bson.M, bson.D, int64, bool, error) {
+	reverse := query.Before != ""
+	token := query.After
+	if reverse {
+		token = query.Before
+	}
+
+	// Walking backwards flips the sort so the closest documents come first
+	descending := k.Descending != reverse
+	order := 1
+	op := "$gt"
+	if descending {
+		order = -1
+		op = "$lt"
+	}
+	sort := bson.D{{Key: k.Field, Value: order}, {Key: "_id", Value: order}}
+
+	if token != "" {
+		cursor, err := DecodeCursor(token)
+		if err != nil {
+			return nil, nil, 0, false, err
+		}
+		seek := bson.M{"$or": bson.A{
+			bson.M{k.Field: bson.M{op: cursor.Key}},
+			bson.M{k.Field: cursor.Key, "_id": bson.M{op: cursor.ID}},
+		}}
+		if len(filter) > 0 {
+			filter = bson.M{"$and": bson.A{filter, seek}}
+		} else {
+			filter = seek
+		}
+	}
+
+	return filter, sort, int64(query.Limit) + 1, reverse, nil
+}
+
+// Page trims a result fetched with Plan to the page size, restores its order
+// and builds the cursors to the neighbouring pages. key extracts the sort key
+// and _id of an item.
+func Page[T any](items []T, query CursorQuery, reverse bool, key func(T) Cursor) ([]T, *CursorPage) {
+	hasMore := len(items) > query.Limit
+	if hasMore {
+		items = items[:query.Limit]
+	}
+	if reverse {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page := &CursorPage{PerPage: query.Limit}
+	if len(items) == 0 {
+		return items, page
+	}
+
+	// Paging backwards implies a next page exists, paging forwards from a
+	// cursor implies a previous one
+	hasNext, hasPrev := hasMore, query.After != ""
+	if reverse {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext {
+		page.NextCursor = key(items[len(items)-1]).Encode()
+	}
+	if hasPrev {
+		page.PrevCursor = key(items[0]).Encode()
+	}
+
+	return items, page
+}
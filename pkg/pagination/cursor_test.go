@@ -0,0 +1,82 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type item struct {
+	ID        bson.ObjectID
+	CreatedAt time.Time
+}
+
+func itemCursor(i item) Cursor {
+	return Cursor{Key: i.CreatedAt, ID: i.ID}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 12, 27, 22, 15, 0, 0, time.UTC)
+	id := bson.NewObjectID()
+
+	decoded, err := DecodeCursor(Cursor{Key: createdAt, ID: id}.Encode())
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, decoded.ID)
+	assert.True(t, createdAt.Equal(decoded.Key))
+
+	_, err = DecodeCursor("not-a-cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestDecodeCursor_RejectsForgedKey(t *testing.T) {
+	// An operator document in place of the date must not reach the filter
+	raw, err := bson.Marshal(bson.M{"k": bson.M{"$ne": nil}, "id": bson.NewObjectID()})
+	assert.NoError(t, err)
+
+	_, err = DecodeCursor(base64.RawURLEncoding.EncodeToString(raw))
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestKeyset_Plan(t *testing.T) {
+	keyset := Keyset{Field: "createdAt", Descending: true}
+	after := Cursor{Key: time.Now(), ID: bson.NewObjectID()}.Encode()
+
+	filter, sort, limit, reverse, err := keyset.Plan(bson.M{"role": "USER"}, CursorQuery{After: after, Limit: 10})
+	assert.NoError(t, err)
+	assert.False(t, reverse)
+	assert.Equal(t, int64(11), limit)
+	assert.Equal(t, bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}, sort)
+	assert.Contains(t, filter, "$and")
+
+	// Walking backwards flips the sort
+	_, sort, _, reverse, err = keyset.Plan(bson.M{}, CursorQuery{Before: after, Limit: 10})
+	assert.NoError(t, err)
+	assert.True(t, reverse)
+	assert.Equal(t, bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}, sort)
+}
+
+func TestPage(t *testing.T) {
+	items := []item{{ID: bson.NewObjectID()}, {ID: bson.NewObjectID()}, {ID: bson.NewObjectID()}}
+
+	// 1. First page with more results
+	got, page := Page(append([]item(nil), items...), CursorQuery{Limit: 2}, false, itemCursor)
+	assert.Equal(t, items[:2], got)
+	assert.NotEmpty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
+
+	// 2. Last page reached from a cursor
+	got, page = Page(append([]item(nil), items[:1]...), CursorQuery{After: "x", Limit: 2}, false, itemCursor)
+	assert.Len(t, got, 1)
+	assert.Empty(t, page.NextCursor)
+	assert.NotEmpty(t, page.PrevCursor)
+
+	// 3. Backwards page is returned in display order
+	got, page = Page([]item{items[2], items[1], items[0]}, CursorQuery{Before: "x", Limit: 2}, true, itemCursor)
+	assert.Equal(t, []item{items[1], items[2]}, got)
+	assert.NotEmpty(t, page.NextCursor)
+	assert.NotEmpty(t, page.PrevCursor)
+}
@@ -0,0 +1,27 @@
+package pagination
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	DefaultPerPage = 10
+	MaxPerPage     = 100
+)
+
+// ParsePage reads the page and per-page query parameters. Missing or
+// invalid values fall back to the first page of DefaultPerPage items.
+func ParsePage(c *fiber.Ctx) (int, int) {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	perPage, _ := strconv.Atoi(c.Query("per-page", strconv.Itoa(DefaultPerPage)))
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > MaxPerPage {
+		perPage = DefaultPerPage
+	}
+	return page, perPage
+}
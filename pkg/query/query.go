@@ -0,0 +1,436 @@
+package query
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrInvalidQuery is wrapped by every error caused by a malformed client query
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Operator is a comparison applied to a field, written as field[op]=value
+type Operator string
+
+const (
+	OpEq       Operator = "eq"
+	OpNe       Operator = "ne"
+	OpGt       Operator = "gt"
+	OpGte      Operator = "gte"
+	OpLt       Operator = "lt"
+	OpLte      Operator = "lte"
+	OpIn       Operator = "in"
+	OpNin      Operator = "nin"
+	OpContains Operator = "contains"
+	// OpPrefix matches strings starting with the value. It isn't a default
+	// operator; fields that allow it list it in Operators.
+	OpPrefix Operator = "prefix"
+)
+
+// Type determines how a field's values are parsed
+type Type int
+
+const (
+	String Type = iota
+	Bool
+	Int
+	Time
+)
+
+// defaultOperators are allowed on a field that does not list its own
+var defaultOperators = map[Type][]Operator{
+	String: {OpEq, OpNe, OpIn, OpNin, OpContains},
+	Bool:   {OpEq, OpNe},
+	Int:    {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNin},
+	Time:   {OpGt, OpGte, OpLt, OpLte},
+}
+
+// dateLayout is accepted for Time fields besides RFC 3339
+const dateLayout = "2006-01-02"
+
+// Field declares a filterable and/or sortable field of a resource
+type Field struct {
+	// Name is the document field the parameter maps to
+	Name string
+	Type Type
+	// Operators overrides the default operators for Type
+	Operators []Operator
+	// Values restricts a String field to a fixed set, e.g. a status enum
+	Values   []string
+	Sortable bool
+}
+
+// Schema is the whitelist of fields a list endpoint accepts, keyed by query
+// parameter name. Parameters that are not in the schema are ignored, so
+// pagination and other endpoint options can share the query string.
+type Schema struct {
+	Fields map[string]Field
+	// DefaultSort is used when the request has no sort parameter
+	DefaultSort bson.D
+	// Selectable maps the response fields a client may pick with fields= to
+	// the document fields that back them. Nil disables sparse fieldsets.
+	Selectable map[string][]string
+}
+
+// Query is a parsed, validated list query. Fields and Projection are only
+// set when the client asked for a sparse fieldset.
+type Query struct {
+	Filter     bson.M
+	Sort       bson.D
+	Fields     []string
+	Projection bson.M
+}
+
+// Parse compiles query parameters into a filter and sort. Filters use
+// param=value or param[op]=value, several of which are combined with AND;
+// sort takes a comma separated list of fields, each optionally prefixed with
+// '-' for descending order. Values are copied, so the result stays valid
+// after the request's buffers are reused.
+func (s Schema) Parse(params map[string]string) (*Query, error) {
+	q := &Query{Filter: bson.M{}, Sort: s.DefaultSort}
+
+	for key, raw := range params {
+		if key == "fields" {
+			fields, projection, err := s.ParseFields(raw)
+			if err != nil {
+				return nil, err
+			}
+			q.Fields, q.Projection = fields, projection
+			continue
+		}
+		if key == "sort" {
+			sort, err := s.parseSort(raw)
+			if err != nil {
+				return nil, err
+			}
+			q.Sort = sort
+			continue
+		}
+
+		param, op := splitOperator(key)
+		field, ok := s.Fields[param]
+		if !ok {
+			continue
+		}
+
+		condition, err := field.compile(op, strings.Clone(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidQuery, key, err)
+		}
+		q.addCondition(field.Name, condition)
+	}
+
+	return q, nil
+}
+
+// splitOperator splits "param[op]" into its parts; a bare param means eq
+func splitOperator(key string) (string, Operator) {
+	open := strings.IndexByte(key, '[')
+	if open < 0 || !strings.HasSuffix(key, "]") {
+		return key, OpEq
+	}
+	return key[:open], Operator(key[open+1 : len(key)-1])
+}
+
+// addCondition merges a condition into the filter so that several operators
+// on the same field (e.g. a gte/lt date range) all apply
+func (q *Query) addCondition(name string, condition any) {
+	existing, ok := q.Filter[name]
+	if !ok {
+		q.Filter[name] = condition
+		return
+	}
+
+	merged, okExisting := existing.(bson.M)
+	next, okNext := condition.(bson.M)
+	if okExisting && okNext {
+		for k, v := range next {
+			current, ok := merged[k]
+			if !ok {
+				merged[k] = v
+				continue
+			}
+			// The same operator twice (e.g. gte=3 and a gt=2 turned into
+			// $gte) keeps the stricter bound instead of the last one
+			if bound, ok := stricterBound(k, current, v); ok {
+				merged[k] = bound
+				continue
+			}
+			q.addAnd(name, bson.M{k: v})
+		}
+		return
+	}
+
+	// An equality can't share a document with operators, fall back to $and
+	q.addAnd(name, condition)
+}
+
+func (q *Query) addAnd(name string, condition any) {
+	and, _ := q.Filter["$and"].(bson.A)
+	q.Filter["$and"] = append(and, bson.M{name: condition})
+}
+
+// stricterBound returns the narrower of two values of the same range
+// operator. ok is false for other operators or values that don't compare.
+func stricterBound(op string, a, b any) (any, bool) {
+	var c int
+	switch a := a.(type) {
+	case time.Time:
+		b, ok := b.(time.Time)
+		if !ok {
+			return nil, false
+		}
+		c = a.Compare(b)
+	case int64:
+		b, ok := b.(int64)
+		if !ok {
+			return nil, false
+		}
+		c = cmp.Compare(a, b)
+	case string:
+		b, ok := b.(string)
+		if !ok {
+			return nil, false
+		}
+		c = strings.Compare(a, b)
+	default:
+		return nil, false
+	}
+
+	switch op {
+	case "$gt", "$gte":
+		if c >= 0 {
+			return a, true
+		}
+		return b, true
+	case "$lt", "$lte":
+		if c <= 0 {
+			return a, true
+		}
+		return b, true
+	}
+	return nil, false
+}
+
+func (f Field) allows(op Operator) bool {
+	operators := f.Operators
+	if operators == nil {
+		operators = defaultOperators[f.Type]
+	}
+	for _, allowed := range operators {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// compile turns one parameter into a bson condition for the field
+func (f Field) compile(op Operator, raw string) (any, error) {
+	if !f.allows(op) {
+		return nil, fmt.Errorf("operator %q is not supported", op)
+	}
+
+	switch op {
+	case OpIn, OpNin:
+		values := bson.A{}
+		for _, part := range strings.Split(raw, ",") {
+			value, err := f.parseValue(strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return bson.M{"$" + string(op): values}, nil
+	case OpContains:
+		// The value is quoted so clients can't inject regex syntax
+		return bson.M{"$regex": regexp.QuoteMeta(raw), "$options": "i"}, nil
+	case OpPrefix:
+		// Anchored and case sensitive, so an index on the field is used
+		return bson.M{"$regex": "^" + regexp.QuoteMeta(raw)}, nil
+	}
+
+	value, err := f.parseValue(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.Type == Time && isDate(raw) {
+		// A bare date covers the whole day, so lte/gt compare against the
+		// start of the next day
+		switch op {
+		case OpLte:
+			return bson.M{"$lt": value.(time.Time).AddDate(0, 0, 1)}, nil
+		case OpGt:
+			return bson.M{"$gte": value.(time.Time).AddDate(0, 0, 1)}, nil
+		}
+	}
+
+	if op == OpEq {
+		return value, nil
+	}
+	return bson.M{"$" + string(op): value}, nil
+}
+
+func (f Field) parseValue(raw string) (any, error) {
+	switch f.Type {
+	case Bool:
+		return strconv.ParseBool(raw)
+	case Int:
+		return strconv.ParseInt(raw, 10, 64)
+	case Time:
+		if isDate(raw) {
+			return time.Parse(dateLayout, raw)
+		}
+		return time.Parse(time.RFC3339, raw)
+	default:
+		if len(f.Values) > 0 && !slices.Contains(f.Values, raw) {
+			return nil, fmt.Errorf("must be one of %s", strings.Join(f.Values, ", "))
+		}
+		return raw, nil
+	}
+}
+
+func isDate(raw string) bool {
+	_, err := time.Parse(dateLayout, raw)
+	return err == nil
+}
+
+// ParseFields parses a comma separated fields= value into the selected
+// response fields and the Mongo projection that loads them
+func (s Schema) ParseFields(raw string) ([]string, bson.M, error) {
+	var fields []string
+	projection := bson.M{}
+	for _, token := range strings.Split(raw, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+
+		backing, ok := s.Selectable[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: unknown field %q", ErrInvalidQuery, token)
+		}
+		fields = append(fields, strings.Clone(token))
+		for _, name := range backing {
+			projection[name] = 1
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, nil, nil
+	}
+	return fields, projection, nil
+}
+
+// ParseInclude parses a comma separated include= value, rejecting relations
+// that are not in allowed
+func ParseInclude(raw string, allowed ...string) ([]string, error) {
+	var include []string
+	for _, token := range strings.Split(raw, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+
+		known := false
+		for _, name := range allowed {
+			if token == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown include %q", ErrInvalidQuery, token)
+		}
+		include = append(include, strings.Clone(token))
+	}
+
+	return include, nil
+}
+
+// Select trims a response object, or every object in a slice, down to the
+// given JSON fields. An empty field list returns v unchanged.
+func Select(v any, fields []string) (any, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := func(object map[string]any) map[string]any {
+		trimmed := make(map[string]any, len(fields))
+		for _, field := range fields {
+			if value, ok := object[field]; ok {
+				trimmed[field] = value
+			}
+		}
+		return trimmed
+	}
+
+	var objects []map[string]any
+	if err := json.Unmarshal(raw, &objects); err == nil {
+		for i := range objects {
+			objects[i] = keep(objects[i])
+		}
+		return objects, nil
+	}
+
+	var object map[string]any
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+	return keep(object), nil
+}
+
+// parseSort accepts either the parameter name or the document field name of
+// a sortable field. _id is appended as a tie-breaker so pages are stable.
+func (s Schema) parseSort(raw string) (bson.D, error) {
+	sort := bson.D{}
+	for _, token := range strings.Split(raw, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+
+		order := 1
+		if strings.HasPrefix(token, "-") {
+			order = -1
+			token = token[1:]
+		}
+
+		field, ok := s.lookupSortable(token)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, token)
+		}
+		sort = append(sort, bson.E{Key: field.Name, Value: order})
+	}
+
+	if len(sort) == 0 {
+		return s.DefaultSort, nil
+	}
+	return append(sort, bson.E{Key: "_id", Value: sort[len(sort)-1].Value}), nil
+}
+
+func (s Schema) lookupSortable(name string) (Field, bool) {
+	if field, ok := s.Fields[name]; ok && field.Sortable {
+		return field, true
+	}
+	for _, field := range s.Fields {
+		if field.Name == name && field.Sortable {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
@@ -0,0 +1,128 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var testSchema = Schema{
+	Fields: map[string]Field{
+		"last-name":  {Name: "lastName", Type: String, Sortable: true},
+		"role":       {Name: "role", Type: String, Operators: []Operator{OpEq, OpIn}, Values: []string{"ADMIN", "USER"}},
+		"is-active":  {Name: "isActive", Type: Bool},
+		"team":       {Name: "team", Type: String, Operators: []Operator{OpEq, OpPrefix}},
+		"created-at": {Name: "createdAt", Type: Time, Sortable: true},
+	},
+	DefaultSort: bson.D{{Key: "createdAt", Value: -1}},
+}
+
+func TestParse_Filter(t *testing.T) {
+	q, err := testSchema.Parse(map[string]string{
+		"role[in]":            "ADMIN, USER",
+		"is-active":           "true",
+		"last-name[contains]": "o'n.",
+		"team[prefix]":        "core.*",
+		"created-at[gte]":     "2025-01-01",
+		"created-at[lte]":     "2025-01-31",
+		"page":                "2",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, bson.M{"$in": bson.A{"ADMIN", "USER"}}, q.Filter["role"])
+	assert.Equal(t, true, q.Filter["isActive"])
+	assert.Equal(t, bson.M{"$regex": `o'n\.`, "$options": "i"}, q.Filter["lastName"])
+	assert.Equal(t, bson.M{"$regex": `^core\.\*`}, q.Filter["team"])
+	// A date range on one field is merged, and a bare lte date includes that day
+	assert.Equal(t, bson.M{
+		"$gte": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"$lt":  time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}, q.Filter["createdAt"])
+	assert.NotContains(t, q.Filter, "page")
+	assert.Equal(t, testSchema.DefaultSort, q.Sort)
+}
+
+func TestParse_OverlappingBounds(t *testing.T) {
+	// gt on a bare date becomes a $gte of the next day, which must not
+	// replace an explicit gte (or be replaced by it) whichever comes first
+	for range 10 {
+		q, err := testSchema.Parse(map[string]string{
+			"created-at[gt]":  "2025-01-10",
+			"created-at[gte]": "2025-01-05",
+			"created-at[lte]": "2025-01-31",
+			"created-at[lt]":  "2025-01-20T00:00:00Z",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, bson.M{
+			"$gte": time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC),
+			"$lt":  time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+		}, q.Filter["createdAt"])
+	}
+}
+
+func TestParse_Sort(t *testing.T) {
+	q, err := testSchema.Parse(map[string]string{"sort": "-createdAt,last-name"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, bson.D{
+		{Key: "createdAt", Value: -1},
+		{Key: "lastName", Value: 1},
+		{Key: "_id", Value: 1},
+	}, q.Sort)
+}
+
+func TestParse_Rejects(t *testing.T) {
+	for name, params := range map[string]map[string]string{
+		"unsupported operator": {"role[contains]": "ADM"},
+		"unknown operator":     {"role[regex]": ".*"},
+		"prefix not allowed":   {"last-name[prefix]": "O"},
+		"bad value":            {"is-active": "maybe"},
+		"unknown enum value":   {"role[in]": "ADMIN,ROOT"},
+		"bad date":             {"created-at[gte]": "yesterday"},
+		"unsortable field":     {"sort": "role"},
+	} {
+		_, err := testSchema.Parse(params)
+		assert.ErrorIs(t, err, ErrInvalidQuery, name)
+	}
+}
+
+func TestParse_Fields(t *testing.T) {
+	schema := testSchema
+	schema.Selectable = map[string][]string{"id": {"_id"}, "lastName": {"lastName"}}
+
+	q, err := schema.Parse(map[string]string{"fields": "id,lastName"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "lastName"}, q.Fields)
+	assert.Equal(t, bson.M{"_id": 1, "lastName": 1}, q.Projection)
+
+	_, err = schema.Parse(map[string]string{"fields": "password"})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestParseInclude(t *testing.T) {
+	include, err := ParseInclude("stats", "stats")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stats"}, include)
+
+	_, err = ParseInclude("stats,sessions", "stats")
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestSelect(t *testing.T) {
+	type user struct {
+		ID       string `json:"id"`
+		LastName string `json:"lastName"`
+		IsActive bool   `json:"isActive"`
+	}
+
+	selected, err := Select([]user{{ID: "1", LastName: "Doe"}}, []string{"id", "lastName"})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"id": "1", "lastName": "Doe"}}, selected)
+
+	selected, err = Select(user{ID: "1"}, []string{"isActive"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"isActive": false}, selected)
+}
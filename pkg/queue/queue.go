@@ -0,0 +1,474 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/utils"
+)
+
+const (
+	retryBaseDelay = 5 * time.Second
+	retryMaxDelay  = time.Hour
+
+	// promoteLimit bounds how many due or expired jobs one dequeue moves
+	promoteLimit = 100
+)
+
+var (
+	ErrJobNotFound    = errors.New("job not found")
+	ErrUnknownJobType = errors.New("no handler for job type")
+)
+
+// Job is a unit of background work
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	EnqueuedAt  time.Time       `json:"enqueuedAt"`
+	LastError   string          `json:"lastError,omitempty"`
+	FailedAt    *time.Time      `json:"failedAt,omitempty"`
+
+	// lease is the visibility deadline of the current attempt; completing or
+	// failing the job is ignored once another worker has taken it over
+	lease int64
+}
+
+// Decode unmarshals the job payload into v
+func (j *Job) Decode(v any) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// EnqueueOptions customise a single job; zero values use the queue defaults
+type EnqueueOptions struct {
+	// Delay postpones the first attempt
+	Delay time.Duration
+	// MaxAttempts is how many times the job runs before it is dead-lettered
+	MaxAttempts int
+}
+
+// Stats counts the jobs in each state
+type Stats struct {
+	Ready     int64 `json:"ready"`
+	Scheduled int64 `json:"scheduled"`
+	Running   int64 `json:"running"`
+	Dead      int64 `json:"dead"`
+}
+
+// Queue is a Redis-backed job queue with delayed jobs, retries with
+// exponential backoff, a dead-letter queue and visibility timeouts.
+//
+// Jobs are stored in a hash and referenced by ID from a ready list, a
+// scheduled set (delayed jobs and retries, scored by run time), a running set
+// (scored by visibility deadline) and a dead set (scored by failure time). A
+// job whose worker does not finish it before the visibility timeout is handed
+// to another worker, so handlers must be idempotent.
+type Queue struct {
+	redis             *database.Redis
+	keys              keys
+	visibilityTimeout time.Duration
+	maxAttempts       int
+}
+
+// keys share a hash tag so the scripts work on Redis Cluster
+type keys struct {
+	jobs, attempts, ready, scheduled, running, dead string
+}
+
+// NewQueue creates the named queue
+func NewQueue(redis *database.Redis, name string, visibilityTimeout time.Duration, maxAttempts int) *Queue {
+	prefix := "queue:{" + name + "}:"
+	return &Queue{
+		redis: redis,
+		keys: keys{
+			jobs:      prefix + "jobs",
+			attempts:  prefix + "attempts",
+			ready:     prefix + "ready",
+			scheduled: prefix + "scheduled",
+			running:   prefix + "running",
+			dead:      prefix + "dead",
+		},
+		visibilityTimeout: visibilityTimeout,
+		maxAttempts:       maxAttempts,
+	}
+}
+
+// Enqueue adds a job whose payload is v marshalled to JSON
+func (q *Queue) Enqueue(ctx context.Context, jobType string, v any, opts EnqueueOptions) (*Job, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &Job{
+		ID:          bson.NewObjectID().Hex(),
+		Type:        jobType,
+		Payload:     payload,
+		MaxAttempts: opts.MaxAttempts,
+		EnqueuedAt:  time.Now(),
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = q.maxAttempts
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = q.redis.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, q.keys.jobs, job.ID, data)
+		if opts.Delay > 0 {
+			pipe.ZAdd(ctx, q.keys.scheduled, redis.Z{Score: score(job.EnqueuedAt.Add(opts.Delay)), Member: job.ID})
+		} else {
+			pipe.LPush(ctx, q.keys.ready, job.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// errLeaseExpired is recorded on jobs whose last attempt timed out
+var errLeaseExpired = errors.New("visibility timeout expired on the last attempt")
+
+// dequeueScript moves due scheduled jobs and jobs whose visibility timeout
+// expired to the ready list, then claims the oldest ready job. A job that
+// already used all its attempts only gets here when its worker died or hung
+// on the last one; it is dead-lettered instead and returned as expired.
+var dequeueScript = redis.NewScript(`
+local function promote(key)
+	local ids = redis.call('ZRANGEBYSCORE', key, '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+	for _, id in ipairs(ids) do
+		redis.call('ZREM', key, id)
+		redis.call('LPUSH', KEYS[1], id)
+	end
+end
+promote(KEYS[2])
+promote(KEYS[3])
+local expired = {}
+while true do
+	local id = redis.call('RPOP', KEYS[1])
+	if not id then
+		return {'', 0, expired}
+	end
+	local job = redis.call('HGET', KEYS[4], id)
+	if job then
+		local max = tonumber(cjson.decode(job)['maxAttempts']) or 0
+		local used = tonumber(redis.call('HGET', KEYS[5], id)) or 0
+		if max > 0 and used >= max then
+			redis.call('ZADD', KEYS[6], ARGV[1], id)
+			table.insert(expired, job)
+		else
+			redis.call('ZADD', KEYS[3], ARGV[2], id)
+			return {job, redis.call('HINCRBY', KEYS[5], id, 1), expired}
+		end
+	end
+end
+`)
+
+// markDeadScript stores a dead-lettered job if it is still dead since at
+var markDeadScript = redis.NewScript(`
+if tonumber(redis.call('ZSCORE', KEYS[1], ARGV[1])) ~= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[3])
+return 1
+`)
+
+// Dequeue claims the next ready job for one visibility timeout. It returns
+// nil when no job is ready.
+func (q *Queue) Dequeue(ctx context.Context) (*Job, error) {
+	now := time.Now()
+	lease := score(now.Add(q.visibilityTimeout))
+
+	res, err := dequeueScript.Run(ctx, q.redis.Client,
+		[]string{q.keys.ready, q.keys.scheduled, q.keys.running, q.keys.jobs, q.keys.attempts, q.keys.dead},
+		score(now), lease, promoteLimit,
+	).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	expired, _ := res[2].([]any)
+	for _, value := range expired {
+		data, _ := value.(string)
+		if err := q.markExpired(ctx, data, now); err != nil {
+			return nil, err
+		}
+	}
+
+	data, _ := res[0].(string)
+	if data == "" {
+		return nil, nil
+	}
+	attempts, _ := res[1].(int64)
+
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return nil, fmt.Errorf("decode job: %w", err)
+	}
+	job.Attempts = int(attempts)
+	job.lease = int64(lease)
+	return &job, nil
+}
+
+// markExpired records why a job the dequeue script dead-lettered at failedAt
+// failed, the way Fail does for handler errors
+func (q *Queue) markExpired(ctx context.Context, data string, failedAt time.Time) error {
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return fmt.Errorf("decode job: %w", err)
+	}
+	job.Attempts = job.MaxAttempts
+	job.LastError = errLeaseExpired.Error()
+	job.FailedAt = &failedAt
+
+	updated, err := json.Marshal(&job)
+	if err != nil {
+		return err
+	}
+	return markDeadScript.Run(ctx, q.redis.Client,
+		[]string{q.keys.dead, q.keys.jobs},
+		job.ID, score(failedAt), updated,
+	).Err()
+}
+
+// completeScript deletes a job if the caller still holds its lease
+var completeScript = redis.NewScript(`
+if tonumber(redis.call('ZSCORE', KEYS[1], ARGV[1])) ~= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[3], ARGV[1])
+return 1
+`)
+
+// Complete removes a successfully handled job
+func (q *Queue) Complete(ctx context.Context, job *Job) error {
+	return completeScript.Run(ctx, q.redis.Client,
+		[]string{q.keys.running, q.keys.jobs, q.keys.attempts},
+		job.ID, job.lease,
+	).Err()
+}
+
+// failScript moves a job from the running set to the scheduled or dead set
+// if the caller still holds its lease
+var failScript = redis.NewScript(`
+if tonumber(redis.call('ZSCORE', KEYS[1], ARGV[1])) ~= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[3])
+redis.call('ZADD', KEYS[3], ARGV[4], ARGV[1])
+return 1
+`)
+
+// Fail records a failed attempt. The job is retried after RetryDelay unless
+// it has used all its attempts or err is Permanent, in which case it is moved
+// to the dead-letter queue. It reports whether the job was dead-lettered.
+func (q *Queue) Fail(ctx context.Context, job *Job, err error) (bool, error) {
+	now := time.Now()
+	job.LastError = err.Error()
+
+	dead := job.Attempts >= job.MaxAttempts || IsPermanent(err)
+	target, at := q.keys.scheduled, now.Add(RetryDelay(job.Attempts))
+	if dead {
+		job.FailedAt = &now
+		target, at = q.keys.dead, now
+	}
+
+	data, mErr := json.Marshal(job)
+	if mErr != nil {
+		return false, mErr
+	}
+
+	return dead, failScript.Run(ctx, q.redis.Client,
+		[]string{q.keys.running, q.keys.jobs, target},
+		job.ID, job.lease, data, score(at),
+	).Err()
+}
+
+// Stats counts the jobs in each state
+func (q *Queue) Stats(ctx context.Context) (*Stats, error) {
+	var ready, scheduled, running, dead *redis.IntCmd
+	_, err := q.redis.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		ready = pipe.LLen(ctx, q.keys.ready)
+		scheduled = pipe.ZCard(ctx, q.keys.scheduled)
+		running = pipe.ZCard(ctx, q.keys.running)
+		dead = pipe.ZCard(ctx, q.keys.dead)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stats{
+		Ready:     ready.Val(),
+		Scheduled: scheduled.Val(),
+		Running:   running.Val(),
+		Dead:      dead.Val(),
+	}, nil
+}
+
+// Dead returns a page of dead-lettered jobs, most recently failed first
+func (q *Queue) Dead(ctx context.Context, page, pageSize int) ([]*Job, int64, error) {
+	start := int64((page - 1) * pageSize)
+	ids, err := q.redis.Client.ZRevRange(ctx, q.keys.dead, start, start+int64(pageSize)-1).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := q.redis.Client.ZCard(ctx, q.keys.dead).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	jobs := make([]*Job, 0, len(ids))
+	if len(ids) == 0 {
+		return jobs, total, nil
+	}
+
+	values, err := q.redis.Client.HMGet(ctx, q.keys.jobs, ids...).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			return nil, 0, fmt.Errorf("decode job: %w", err)
+		}
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, total, nil
+}
+
+// GetDead returns a dead-lettered job
+func (q *Queue) GetDead(ctx context.Context, id string) (*Job, error) {
+	if err := q.redis.Client.ZScore(ctx, q.keys.dead, id).Err(); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	data, err := q.redis.Client.HGet(ctx, q.keys.jobs, id).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("decode job: %w", err)
+	}
+	return &job, nil
+}
+
+// retryScript moves a job from the dead set back to the ready list with a
+// fresh attempt budget
+var retryScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+redis.call('HDEL', KEYS[3], ARGV[1])
+redis.call('LPUSH', KEYS[4], ARGV[1])
+return 1
+`)
+
+// Retry requeues a dead-lettered job with a fresh attempt budget
+func (q *Queue) Retry(ctx context.Context, id string) (*Job, error) {
+	job, err := q.GetDead(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Attempts = 0
+	job.FailedAt = nil
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := retryScript.Run(ctx, q.redis.Client,
+		[]string{q.keys.dead, q.keys.jobs, q.keys.attempts, q.keys.ready},
+		id, data,
+	).Int()
+	if err != nil {
+		return nil, err
+	}
+	if moved == 0 {
+		return nil, ErrJobNotFound
+	}
+
+	return job, nil
+}
+
+// DeleteDead discards a dead-lettered job
+func (q *Queue) DeleteDead(ctx context.Context, id string) error {
+	removed, err := q.redis.Client.ZRem(ctx, q.keys.dead, id).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrJobNotFound
+	}
+
+	_, err = q.redis.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, q.keys.jobs, id)
+		pipe.HDel(ctx, q.keys.attempts, id)
+		return nil
+	})
+	return err
+}
+
+// RetryDelay is the wait after the given failed attempt: 5s, doubling per
+// attempt, capped at 1h
+func RetryDelay(attempts int) time.Duration {
+	return utils.Backoff(attempts, retryBaseDelay, retryMaxDelay)
+}
+
+// permanentError marks an error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job is dead-lettered without further retries
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// score converts t to a sorted set score in milliseconds
+func score(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
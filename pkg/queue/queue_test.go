@@ -0,0 +1,204 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/database"
+)
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 5*time.Second, RetryDelay(1))
+	assert.Equal(t, 10*time.Second, RetryDelay(2))
+	assert.Equal(t, 40*time.Second, RetryDelay(4))
+	assert.Equal(t, time.Hour, RetryDelay(20))
+}
+
+func TestPermanent(t *testing.T) {
+	base := errors.New("bad payload")
+
+	assert.False(t, IsPermanent(base))
+	assert.True(t, IsPermanent(Permanent(base)))
+	assert.ErrorIs(t, Permanent(base), base)
+}
+
+func TestWorkerHandle(t *testing.T) {
+	// 1. Setup a worker with a failing and a panicking handler
+	worker := NewWorker(nil, 1, time.Second)
+	worker.Handle("fails", func(ctx context.Context, job *Job) error {
+		return errors.New("boom")
+	})
+	worker.Handle("panics", func(ctx context.Context, job *Job) error {
+		panic("nil map")
+	})
+
+	// 2. Call Method
+	failErr := worker.handle(context.Background(), &Job{Type: "fails"})
+	panicErr := worker.handle(context.Background(), &Job{Type: "panics"})
+	unknownErr := worker.handle(context.Background(), &Job{Type: "unknown"})
+
+	// 3. Assertions: failures are retried, unknown job types are not
+	assert.EqualError(t, failErr, "boom")
+	assert.False(t, IsPermanent(failErr))
+	assert.ErrorContains(t, panicErr, "nil map")
+	assert.ErrorIs(t, unknownErr, ErrUnknownJobType)
+	assert.True(t, IsPermanent(unknownErr))
+}
+
+// testQueue returns a queue on an in-memory Redis. A negative visibility
+// timeout makes every claimed job expire at once.
+func testQueue(t *testing.T, visibilityTimeout time.Duration, maxAttempts int) *Queue {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	return NewQueue(&database.Redis{Client: client}, "test", visibilityTimeout, maxAttempts)
+}
+
+// makeDue moves every scheduled job's run time into the past
+func makeDue(t *testing.T, q *Queue) {
+	t.Helper()
+	ids, err := q.redis.Client.ZRange(context.Background(), q.keys.scheduled, 0, -1).Result()
+	require.NoError(t, err)
+	for _, id := range ids {
+		require.NoError(t, q.redis.Client.ZAdd(context.Background(), q.keys.scheduled, redis.Z{Score: 0, Member: id}).Err())
+	}
+}
+
+func TestQueue_CompleteRemovesJob(t *testing.T) {
+	// 1. Setup Queue with one job
+	ctx := context.Background()
+	q := testQueue(t, time.Minute, 3)
+	enqueued, err := q.Enqueue(ctx, "email.send", map[string]string{"emailId": "1"}, EnqueueOptions{})
+	require.NoError(t, err)
+
+	// 2. Call Method
+	job, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, q.Complete(ctx, job))
+	next, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	stats, err := q.Stats(ctx)
+	require.NoError(t, err)
+
+	// 3. Assertions
+	assert.Equal(t, enqueued.ID, job.ID)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Nil(t, next)
+	assert.Equal(t, &Stats{}, stats)
+}
+
+func TestQueue_FailRetriesThenDeadLetters(t *testing.T) {
+	// 1. Setup Queue with a job allowed two attempts
+	ctx := context.Background()
+	q := testQueue(t, time.Minute, 2)
+	_, err := q.Enqueue(ctx, "email.send", nil, EnqueueOptions{})
+	require.NoError(t, err)
+
+	// 2. Call Method: fail both attempts
+	job, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	dead, err := q.Fail(ctx, job, errors.New("smtp down"))
+	require.NoError(t, err)
+	assert.False(t, dead)
+
+	notDue, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, notDue)
+
+	makeDue(t, q)
+	job, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	dead, err = q.Fail(ctx, job, errors.New("smtp down"))
+	require.NoError(t, err)
+
+	// 3. Assertions
+	assert.True(t, dead)
+	assert.Equal(t, 2, job.Attempts)
+	deadJobs, total, err := q.Dead(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "smtp down", deadJobs[0].LastError)
+}
+
+func TestQueue_ExpiredLeaseIsRedelivered(t *testing.T) {
+	// 1. Setup Queue whose jobs expire as soon as they are claimed
+	ctx := context.Background()
+	q := testQueue(t, -time.Second, 3)
+	_, err := q.Enqueue(ctx, "email.send", nil, EnqueueOptions{})
+	require.NoError(t, err)
+
+	// 2. Call Method: the first worker never finishes and the second one
+	// claims the job with a lease that is still valid
+	first, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	q.visibilityTimeout = time.Minute
+	second, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+
+	// 3. Assertions: the stale worker's outcome is ignored
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, 2, second.Attempts)
+	require.NoError(t, q.Complete(ctx, first))
+	stats, err := q.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Running)
+}
+
+func TestQueue_ExpiredLastAttemptIsDeadLettered(t *testing.T) {
+	// 1. Setup Queue whose jobs expire as soon as they are claimed
+	ctx := context.Background()
+	q := testQueue(t, -time.Second, 2)
+	_, err := q.Enqueue(ctx, "email.send", nil, EnqueueOptions{})
+	require.NoError(t, err)
+
+	// 2. Call Method: both attempts time out
+	for range 2 {
+		job, err := q.Dequeue(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+	}
+	job, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	// 3. Assertions: the job is dead-lettered instead of running a third time
+	assert.Nil(t, job)
+	deadJobs, total, err := q.Dead(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, 2, deadJobs[0].Attempts)
+	assert.Equal(t, errLeaseExpired.Error(), deadJobs[0].LastError)
+	assert.NotNil(t, deadJobs[0].FailedAt)
+}
+
+func TestQueue_RetryResetsAttempts(t *testing.T) {
+	// 1. Setup Queue with a dead-lettered job
+	ctx := context.Background()
+	q := testQueue(t, time.Minute, 1)
+	enqueued, err := q.Enqueue(ctx, "email.send", nil, EnqueueOptions{})
+	require.NoError(t, err)
+	job, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	_, err = q.Fail(ctx, job, Permanent(errors.New("bad payload")))
+	require.NoError(t, err)
+
+	// 2. Call Method
+	_, err = q.Retry(ctx, enqueued.ID)
+	require.NoError(t, err)
+	retried, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	// 3. Assertions
+	require.NotNil(t, retried)
+	assert.Equal(t, 1, retried.Attempts)
+	_, err = q.GetDead(ctx, enqueued.ID)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
@@ -0,0 +1,140 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+)
+
+// recordTimeout bounds storing a job's outcome. It gets its own context since
+// the handler may have used up the visibility timeout.
+const recordTimeout = 5 * time.Second
+
+// Handler processes one job. Returning an error retries the job unless it
+// is wrapped with Permanent.
+type Handler func(ctx context.Context, job *Job) error
+
+// Worker runs handlers for the jobs of a queue
+type Worker struct {
+	queue        *Queue
+	handlers     map[string]Handler
+	concurrency  int
+	pollInterval time.Duration
+}
+
+// NewWorker creates a Worker running up to concurrency jobs at once and
+// polling every pollInterval while the queue is empty
+func NewWorker(queue *Queue, concurrency int, pollInterval time.Duration) *Worker {
+	return &Worker{
+		queue:        queue,
+		handlers:     map[string]Handler{},
+		concurrency:  max(concurrency, 1),
+		pollInterval: pollInterval,
+	}
+}
+
+// Handle registers the handler for a job type
+func (w *Worker) Handle(jobType string, handler Handler) {
+	w.handlers[jobType] = handler
+}
+
+// Run processes jobs until ctx is cancelled, then waits for the running
+// jobs to finish. Running jobs are not cancelled with ctx; each is bounded
+// by the visibility timeout instead.
+func (w *Worker) Run(ctx context.Context) {
+	logger.Info("starting job worker",
+		zap.Int("concurrency", w.concurrency),
+		zap.Int("handlers", len(w.handlers)),
+	)
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, w.concurrency)
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			logger.Info("job worker stopped")
+			return
+		case slots <- struct{}{}:
+		}
+
+		job, err := w.queue.Dequeue(ctx)
+		if err != nil || job == nil {
+			<-slots
+			if err != nil && ctx.Err() == nil {
+				logger.Error("failed to dequeue job", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.pollInterval):
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			w.process(job)
+		}()
+	}
+}
+
+// process runs a job's handler and records the outcome
+func (w *Worker) process(job *Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.queue.visibilityTimeout)
+	defer cancel()
+
+	fields := []zap.Field{
+		zap.String("job_id", job.ID),
+		zap.String("job_type", job.Type),
+		zap.Int("attempt", job.Attempts),
+	}
+
+	start := time.Now()
+	err := w.handle(ctx, job)
+
+	recordCtx, cancelRecord := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancelRecord()
+
+	if err == nil {
+		if err := w.queue.Complete(recordCtx, job); err != nil {
+			logger.Error("failed to complete job", append(fields, zap.Error(err))...)
+			return
+		}
+		logger.Debug("job completed", append(fields, zap.Duration("duration", time.Since(start)))...)
+		return
+	}
+
+	dead, failErr := w.queue.Fail(recordCtx, job, err)
+	if failErr != nil {
+		logger.Error("failed to record job failure", append(fields, zap.Error(failErr))...)
+		return
+	}
+	if dead {
+		logger.Error("job moved to dead-letter queue", append(fields, zap.Error(err))...)
+		return
+	}
+	logger.Warn("job failed, will retry", append(fields, zap.Error(err))...)
+}
+
+// handle runs the job's handler, turning panics into errors
+func (w *Worker) handle(ctx context.Context, job *Job) (err error) {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("%w %q", ErrUnknownJobType, job.Type))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
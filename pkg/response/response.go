@@ -2,8 +2,11 @@ package response
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/pagination"
 )
 
 // Response represents a standard API response
@@ -56,6 +59,28 @@ type Meta struct {
 	PrevPageURL  *string    `json:"prevPageUrl"`
 }
 
+// CursorPaginatedResponse represents a cursor-paginated API response
+type CursorPaginatedResponse struct {
+	Success bool        `json:"success"`
+	Code    int         `json:"code"`
+	Status  string      `json:"status"`
+	Message string      `json:"message,omitempty"`
+	Data    any         `json:"data,omitempty"`
+	Meta    *CursorMeta `json:"meta,omitempty"`
+}
+
+// CursorMeta holds cursor pagination metadata. Total is only present when
+// the client asked for it.
+type CursorMeta struct {
+	Path        string  `json:"path"`
+	PerPage     int     `json:"perPage"`
+	Total       *int64  `json:"total,omitempty"`
+	NextCursor  *string `json:"nextCursor"`
+	PrevCursor  *string `json:"prevCursor"`
+	NextPageURL *string `json:"nextPageUrl"`
+	PrevPageURL *string `json:"prevPageUrl"`
+}
+
 // Pagination is kept for backward compatibility but deprecated
 type Pagination = Meta
 
@@ -90,6 +115,8 @@ func getStatus(code int) string {
 		return "OK"
 	case fiber.StatusCreated:
 		return "CREATED"
+	case fiber.StatusAccepted:
+		return "ACCEPTED"
 	case fiber.StatusNoContent:
 		return "NO_CONTENT"
 	case fiber.StatusBadRequest:
@@ -102,6 +129,10 @@ func getStatus(code int) string {
 		return "NOT_FOUND"
 	case fiber.StatusConflict:
 		return "CONFLICT"
+	case fiber.StatusPreconditionFailed:
+		return "PRECONDITION_FAILED"
+	case fiber.StatusUnsupportedMediaType:
+		return "UNSUPPORTED_MEDIA_TYPE"
 	case fiber.StatusUnprocessableEntity:
 		return "UNPROCESSABLE_ENTITY"
 	case fiber.StatusInternalServerError:
@@ -219,6 +250,53 @@ func Paginated(c *fiber.Ctx, statusCode int, message string, data any, page, per
 	})
 }
 
+// CursorPaginated sends a cursor-paginated response. Page URLs keep the
+// current query and replace the after/before cursor.
+func CursorPaginated(c *fiber.Ctx, statusCode int, message string, data any, page *pagination.CursorPage) error {
+	baseUrl := c.BaseURL() + c.Path()
+
+	generateUrl := func(param, cursor string) *string {
+		if cursor == "" {
+			return nil
+		}
+		query := url.Values{}
+		for k, v := range c.Queries() {
+			if k != "after" && k != "before" && k != "page" {
+				query.Set(k, v)
+			}
+		}
+		query.Set(param, cursor)
+		u := baseUrl + "?" + query.Encode()
+		return &u
+	}
+
+	optional := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+
+	meta := &CursorMeta{
+		Path:        baseUrl,
+		PerPage:     page.PerPage,
+		Total:       page.Total,
+		NextCursor:  optional(page.NextCursor),
+		PrevCursor:  optional(page.PrevCursor),
+		NextPageURL: generateUrl("after", page.NextCursor),
+		PrevPageURL: generateUrl("before", page.PrevCursor),
+	}
+
+	return c.Status(statusCode).JSON(CursorPaginatedResponse{
+		Success: true,
+		Code:    statusCode,
+		Status:  getStatus(statusCode),
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
 // BadRequest sends a 400 Bad Request response
 func BadRequest(c *fiber.Ctx, message string, details string) error {
 	return Error(c, fiber.StatusBadRequest, message, "BAD_REQUEST", details)
@@ -248,3 +326,18 @@ func InternalServerError(c *fiber.Ctx, message string) error {
 func Conflict(c *fiber.Ctx, message string, details string) error {
 	return Error(c, fiber.StatusConflict, message, "CONFLICT", details)
 }
+
+// PreconditionFailed sends a 412 Precondition Failed response
+func PreconditionFailed(c *fiber.Ctx, message string) error {
+	return Error(c, fiber.StatusPreconditionFailed, message, "PRECONDITION_FAILED", "")
+}
+
+// UnsupportedMediaType sends a 415 Unsupported Media Type response
+func UnsupportedMediaType(c *fiber.Ctx, message string) error {
+	return Error(c, fiber.StatusUnsupportedMediaType, message, "UNSUPPORTED_MEDIA_TYPE", "")
+}
+
+// UnprocessableEntity sends a 422 Unprocessable Entity response
+func UnprocessableEntity(c *fiber.Ctx, message string, details string) error {
+	return Error(c, fiber.StatusUnprocessableEntity, message, "UNPROCESSABLE_ENTITY", details)
+}
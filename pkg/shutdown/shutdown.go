@@ -0,0 +1,55 @@
+package shutdown
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/itsahyarr/gofiber-boilerplate/pkg/logger"
+)
+
+// Hook releases one resource during shutdown
+type Hook struct {
+	Name string
+	Fn   func(ctx context.Context) error
+}
+
+// Wait blocks until the process receives SIGINT or SIGTERM
+func Wait() {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+	signal.Stop(quit)
+
+	logger.Info("Shutdown signal received", zap.String("signal", sig.String()))
+}
+
+// Run calls the hooks in order, all within timeout. A failing hook is
+// logged and does not stop the ones after it.
+func Run(timeout time.Duration, hooks ...Hook) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, hook := range hooks {
+		if err := hook.Fn(ctx); err != nil {
+			logger.Error("Shutdown step failed", zap.String("step", hook.Name), zap.Error(err))
+		}
+	}
+}
+
+// Done returns a hook that waits for done to be closed, e.g. by a worker
+// that finishes its in-flight work after its context is cancelled
+func Done(name string, done <-chan struct{}) Hook {
+	return Hook{Name: name, Fn: func(ctx context.Context) error {
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}}
+}
@@ -0,0 +1,77 @@
+package useragent
+
+import "strings"
+
+// Agent is the browser and operating system a User-Agent header names.
+// Versions are left out: they change with every update, while the pair
+// stays stable for the same device.
+type Agent struct {
+	Browser string
+	OS      string
+}
+
+// Unknown names a browser or OS the header doesn't identify
+const Unknown = "Unknown"
+
+// browsers are checked in order; Chromium-based browsers also claim to be
+// Chrome and Safari, so they come first
+var browsers = []struct {
+	token string
+	name  string
+}{
+	{"Edg/", "Edge"},
+	{"EdgiOS/", "Edge"},
+	{"OPR/", "Opera"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"Firefox/", "Firefox"},
+	{"FxiOS/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+	{"PostmanRuntime/", "Postman"},
+	{"okhttp/", "OkHttp"},
+	{"Go-http-client/", "Go"},
+}
+
+// systems are checked in order; Android and ChromeOS also claim Linux, and
+// iOS claims Mac OS X
+var systems = []struct {
+	token string
+	name  string
+}{
+	{"Windows", "Windows"},
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iOS"},
+	{"CrOS", "ChromeOS"},
+	{"Mac OS X", "macOS"},
+	{"Macintosh", "macOS"},
+	{"Linux", "Linux"},
+}
+
+// Parse identifies the browser and OS of a User-Agent header
+func Parse(header string) Agent {
+	agent := Agent{Browser: Unknown, OS: Unknown}
+	for _, b := range browsers {
+		if strings.Contains(header, b.token) {
+			agent.Browser = b.name
+			break
+		}
+	}
+	for _, s := range systems {
+		if strings.Contains(header, s.token) {
+			agent.OS = s.name
+			break
+		}
+	}
+	return agent
+}
+
+// String describes the agent as "Chrome on Windows"
+func (a Agent) String() string {
+	if a.OS == Unknown {
+		return a.Browser
+	}
+	return a.Browser + " on " + a.OS
+}
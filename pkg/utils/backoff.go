@@ -0,0 +1,13 @@
+package utils
+
+import "time"
+
+// Backoff is the wait after the given failed attempt: base after the first,
+// doubling with every further attempt, capped at max
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	return min(delay, max)
+}
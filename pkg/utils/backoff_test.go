@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, Backoff(0, 5*time.Second, time.Hour))
+	assert.Equal(t, 5*time.Second, Backoff(1, 5*time.Second, time.Hour))
+	assert.Equal(t, 10*time.Second, Backoff(2, 5*time.Second, time.Hour))
+	assert.Equal(t, 40*time.Second, Backoff(4, 5*time.Second, time.Hour))
+	assert.Equal(t, time.Hour, Backoff(20, 5*time.Second, time.Hour))
+	// A large attempt count must not overflow
+	assert.Equal(t, time.Hour, Backoff(1000, 5*time.Second, time.Hour))
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FormatETag formats a resource version as a strong entity tag, e.g. "3"
+func FormatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ParseETag extracts the version from an entity tag built by FormatETag.
+// Weak tags are rejected because If-Match requires strong comparison. Version
+// 0 is valid: documents created before versioning are served as "0".
+func ParseETag(etag string) (int64, bool) {
+	etag = strings.TrimSpace(etag)
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(etag[1:len(etag)-1], 10, 64)
+	if err != nil || version < 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// ParseIfMatch parses an If-Match header into the versions it lists. A
+// missing header or "*" matches any version and returns nil. ok is false when
+// no listed tag can match a version this API issues.
+func ParseIfMatch(header string) ([]int64, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	var versions []int64
+	for _, etag := range strings.Split(header, ",") {
+		if version, ok := ParseETag(etag); ok {
+			versions = append(versions, version)
+		}
+	}
+	return versions, len(versions) > 0
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseETag(t *testing.T) {
+	tests := []struct {
+		etag   string
+		want   int64
+		wantOK bool
+	}{
+		{`"3"`, 3, true},
+		{` "3" `, 3, true},
+		// Documents created before versioning are served as "0"
+		{`"0"`, 0, true},
+		{`"-1"`, 0, false},
+		{`W/"3"`, 0, false},
+		{`3`, 0, false},
+		{`"abc"`, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.etag, func(t *testing.T) {
+			version, ok := ParseETag(tt.etag)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, version)
+		})
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []int64
+		wantOK bool
+	}{
+		{"", nil, true},
+		{"*", nil, true},
+		{`"0"`, []int64{0}, true},
+		{`"2", "5"`, []int64{2, 5}, true},
+		// Tags this API never issues are skipped
+		{`W/"2", "5"`, []int64{5}, true},
+		{`W/"2"`, nil, false},
+		{`"x"`, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			versions, ok := ParseIfMatch(tt.header)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, versions)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+// NewInstanceID returns an identifier that is unique per call and names the
+// host and process, e.g. "api-7f9c-12-3fa2c1d0". It marks which process holds
+// a lock or lease.
+func NewInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
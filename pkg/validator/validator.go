@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
@@ -26,17 +28,19 @@ func ParseAndValidate(c *fiber.Ctx, s any) error {
 
 // FormatValidationErrors formats validation errors into a readable string
 func FormatValidationErrors(err error) string {
+	return strings.Join(ValidationMessages(err), "; ")
+}
+
+// ValidationMessages returns one readable message per failed field
+func ValidationMessages(err error) []string {
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		var errStr string
-		for _, e := range validationErrors {
-			if errStr != "" {
-				errStr += "; "
-			}
-			errStr += formatFieldError(e)
+		messages := make([]string, len(validationErrors))
+		for i, e := range validationErrors {
+			messages[i] = formatFieldError(e)
 		}
-		return errStr
+		return messages
 	}
-	return err.Error()
+	return []string{err.Error()}
 }
 
 // formatFieldError formats a single field error
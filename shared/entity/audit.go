@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AuditOutcome tells whether an audited action succeeded
+type AuditOutcome string
+
+const (
+	AuditSuccess AuditOutcome = "success"
+	AuditFailure AuditOutcome = "failure"
+)
+
+// AuditChange is the before and after value of one changed field. Values
+// are stored JSON encoded so they hash the same after a database round trip;
+// both are empty for secrets such as passwords.
+type AuditChange struct {
+	Field  string `bson:"field" json:"field"`
+	Before string `bson:"before,omitempty" json:"before,omitempty"`
+	After  string `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+// AuditLog is an append-only record of a security or admin action. Entries
+// form a hash chain ordered by Seq: Hash covers the entry and the previous
+// entry's Hash, so editing, inserting or removing an entry breaks the chain.
+type AuditLog struct {
+	ID  bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Seq int64         `bson:"seq" json:"seq"`
+	// ActorID is empty for actions without a known actor, such as failed
+	// logins for unknown emails or system tasks
+	ActorID    string        `bson:"actorId,omitempty" json:"actorId,omitempty"`
+	ActorRole  string        `bson:"actorRole,omitempty" json:"actorRole,omitempty"`
+	Action     string        `bson:"action" json:"action"`
+	TargetType string        `bson:"targetType,omitempty" json:"targetType,omitempty"`
+	TargetID   string        `bson:"targetId,omitempty" json:"targetId,omitempty"`
+	Outcome    AuditOutcome  `bson:"outcome" json:"outcome"`
+	Reason     string        `bson:"reason,omitempty" json:"reason,omitempty"`
+	Changes    []AuditChange `bson:"changes,omitempty" json:"changes,omitempty"`
+	IP         string        `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent  string        `bson:"userAgent,omitempty" json:"userAgent,omitempty"`
+	RequestID  string        `bson:"requestId,omitempty" json:"requestId,omitempty"`
+	CreatedAt  time.Time     `bson:"createdAt" json:"createdAt"`
+	PrevHash   string        `bson:"prevHash" json:"prevHash"`
+	Hash       string        `bson:"hash" json:"hash"`
+}
+
+// TableName returns the collection name for audit logs
+func (a *AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// EmailStatus is the delivery state of an email
+type EmailStatus string
+
+const (
+	EmailQueued EmailStatus = "queued"
+	EmailSent   EmailStatus = "sent"
+	EmailFailed EmailStatus = "failed"
+)
+
+// Email is an outgoing email and its delivery status. It is rendered when
+// queued, so retries and resends send exactly the same message.
+type Email struct {
+	ID bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	// Key deduplicates emails triggered by at-least-once events
+	Key       string      `bson:"key,omitempty" json:"key,omitempty"`
+	From      string      `bson:"from" json:"from"`
+	To        string      `bson:"to" json:"to"`
+	Template  string      `bson:"template" json:"template"`
+	Locale    string      `bson:"locale" json:"locale"`
+	Subject   string      `bson:"subject" json:"subject"`
+	Text      string      `bson:"text" json:"text"`
+	HTML      string      `bson:"html,omitempty" json:"html,omitempty"`
+	Status    EmailStatus `bson:"status" json:"status"`
+	Attempts  int         `bson:"attempts" json:"attempts"`
+	LastError string      `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	SentAt    *time.Time  `bson:"sentAt,omitempty" json:"sentAt,omitempty"`
+	CreatedAt time.Time   `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time   `bson:"updatedAt" json:"updatedAt"`
+}
+
+// TableName returns the collection name for emails
+func (e *Email) TableName() string {
+	return "emails"
+}
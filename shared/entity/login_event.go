@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// LoginOutcome tells whether a login attempt succeeded
+type LoginOutcome string
+
+const (
+	LoginSucceeded LoginOutcome = "success"
+	LoginFailed    LoginOutcome = "failure"
+)
+
+// LoginEvent records one login attempt. UserID is unset for attempts with an
+// email that matches no account.
+type LoginEvent struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    bson.ObjectID `bson:"userId,omitempty" json:"userId,omitempty"`
+	Email     string        `bson:"email" json:"email"`
+	Outcome   LoginOutcome  `bson:"outcome" json:"outcome"`
+	Reason    string        `bson:"reason,omitempty" json:"reason,omitempty"`
+	IP        string        `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent string        `bson:"userAgent,omitempty" json:"userAgent,omitempty"`
+	// Device is the browser and OS, e.g. "Chrome on Windows"
+	Device      string `bson:"device" json:"device"`
+	Country     string `bson:"country,omitempty" json:"country,omitempty"`
+	CountryName string `bson:"countryName,omitempty" json:"countryName,omitempty"`
+	City        string `bson:"city,omitempty" json:"city,omitempty"`
+	// NewDevice and UnusualLocation flag successful logins that differ from
+	// the user's earlier ones
+	NewDevice       bool      `bson:"newDevice" json:"newDevice"`
+	UnusualLocation bool      `bson:"unusualLocation" json:"unusualLocation"`
+	CreatedAt       time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+// TableName returns the collection name for login events
+func (e *LoginEvent) TableName() string {
+	return "login_events"
+}
+
+// IsSuspicious reports whether the login was flagged
+func (e *LoginEvent) IsSuspicious() bool {
+	return e.NewDevice || e.UnusualLocation
+}
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Notification is an in-app message in a user's inbox
+type Notification struct {
+	ID     bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID bson.ObjectID `bson:"userId" json:"userId"`
+	// Key deduplicates notifications triggered by at-least-once events
+	Key string `bson:"key,omitempty" json:"-"`
+	// Type lets clients pick an icon or a link, e.g. "user.password_changed"
+	Type      string         `bson:"type" json:"type"`
+	Title     string         `bson:"title" json:"title"`
+	Body      string         `bson:"body,omitempty" json:"body,omitempty"`
+	Data      map[string]any `bson:"data,omitempty" json:"data,omitempty"`
+	ReadAt    *time.Time     `bson:"readAt,omitempty" json:"readAt,omitempty"`
+	CreatedAt time.Time      `bson:"createdAt" json:"createdAt"`
+}
+
+// TableName returns the collection name for notifications
+func (n *Notification) TableName() string {
+	return "notifications"
+}
+
+// IsRead checks if the user has read the notification
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TaskRunStatus is the state of a scheduled task run
+type TaskRunStatus string
+
+const (
+	TaskRunRunning   TaskRunStatus = "running"
+	TaskRunSucceeded TaskRunStatus = "succeeded"
+	TaskRunFailed    TaskRunStatus = "failed"
+)
+
+// TaskTrigger is what started a task run
+type TaskTrigger string
+
+const (
+	TaskTriggerSchedule TaskTrigger = "schedule"
+	TaskTriggerManual   TaskTrigger = "manual"
+)
+
+// TaskRun is one execution of a scheduled task
+type TaskRun struct {
+	ID      bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Task    string        `bson:"task" json:"task"`
+	Trigger TaskTrigger   `bson:"trigger" json:"trigger"`
+	// TriggeredBy is the user who started a manual run
+	TriggeredBy string `bson:"triggeredBy,omitempty" json:"triggeredBy,omitempty"`
+	// ScheduledAt is the schedule tick a scheduled run belongs to
+	ScheduledAt *time.Time    `bson:"scheduledAt,omitempty" json:"scheduledAt,omitempty"`
+	Status      TaskRunStatus `bson:"status" json:"status"`
+	Error       string        `bson:"error,omitempty" json:"error,omitempty"`
+	// Instance is the process that executed the run
+	Instance   string     `bson:"instance" json:"instance"`
+	StartedAt  time.Time  `bson:"startedAt" json:"startedAt"`
+	FinishedAt *time.Time `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+}
+
+// TableName returns the collection name for the task run
+func (r *TaskRun) TableName() string {
+	return "scheduler_runs"
+}
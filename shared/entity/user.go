@@ -14,17 +14,26 @@ const (
 	RoleUser  Role = "USER"
 )
 
-// User represents the user entity
+// User represents the user entity. The activity fields LastLoginAt,
+// LoginCount and LastSeenAt don't change Version or UpdatedAt, since they
+// aren't edits of the user; LastSeenAt is only written every few minutes.
 type User struct {
-	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
-	Email     string        `bson:"email" json:"email"`
-	Password  string        `bson:"password" json:"-"`
-	FirstName string        `bson:"firstName" json:"firstName"`
-	LastName  string        `bson:"lastName" json:"lastName"`
-	Role      Role          `bson:"role" json:"role"`
-	IsActive  bool          `bson:"isActive" json:"isActive"`
-	CreatedAt time.Time     `bson:"createdAt" json:"createdAt"`
-	UpdatedAt time.Time     `bson:"updatedAt" json:"updatedAt"`
+	ID                 bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email              string        `bson:"email" json:"email"`
+	Password           string        `bson:"password" json:"-"`
+	FirstName          string        `bson:"firstName" json:"firstName"`
+	LastName           string        `bson:"lastName" json:"lastName"`
+	Role               Role          `bson:"role" json:"role"`
+	Team               string        `bson:"team,omitempty" json:"team,omitempty"`
+	IsActive           bool          `bson:"isActive" json:"isActive"`
+	MustChangePassword bool          `bson:"mustChangePassword" json:"mustChangePassword"`
+	LastLoginAt        *time.Time    `bson:"lastLoginAt,omitempty" json:"lastLoginAt,omitempty"`
+	LoginCount         int64         `bson:"loginCount" json:"loginCount"`
+	LastSeenAt         *time.Time    `bson:"lastSeenAt,omitempty" json:"lastSeenAt,omitempty"`
+	Version            int64         `bson:"version" json:"version"`
+	CreatedAt          time.Time     `bson:"createdAt" json:"createdAt"`
+	UpdatedAt          time.Time     `bson:"updatedAt" json:"updatedAt"`
+	DeletedAt          *time.Time    `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
 }
 
 // TableName returns the collection name for the user
@@ -37,6 +46,11 @@ func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
 }
 
+// IsDeleted checks if the user has been soft-deleted
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
 // FullName returns the user's full name
 func (u *User) FullName() string {
 	return u.FirstName + " " + u.LastName
@@ -0,0 +1,11 @@
+package entity
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// UserStats holds per-user counters kept alongside the user document
+type UserStats struct {
+	ID         bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     bson.ObjectID `bson:"user_id" json:"userId"`
+	LoginCount int64         `bson:"login_count" json:"loginCount"`
+	Points     int64         `bson:"points" json:"points"`
+}
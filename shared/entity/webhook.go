@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Webhook is an endpoint that receives domain events it subscribed to
+type Webhook struct {
+	ID  bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	URL string        `bson:"url" json:"url"`
+	// Events are event types, "user.*" style prefixes, or "*" for all
+	Events      []string  `bson:"events" json:"events"`
+	Secret      string    `bson:"secret" json:"-"`
+	Description string    `bson:"description,omitempty" json:"description,omitempty"`
+	IsActive    bool      `bson:"isActive" json:"isActive"`
+	CreatedAt   time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// TableName returns the collection name for the webhook
+func (w *Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Subscribes reports whether the webhook wants events of eventType
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, pattern := range w.Events {
+		if pattern == "*" || pattern == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus is the state of a webhook delivery
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event sent (or to be sent) to one webhook. Payload
+// is the exact request body, so retries and redeliveries are identical.
+type WebhookDelivery struct {
+	ID             bson.ObjectID  `bson:"_id,omitempty" json:"id"`
+	WebhookID      bson.ObjectID  `bson:"webhookId" json:"webhookId"`
+	EventID        string         `bson:"eventId" json:"eventId"`
+	EventType      string         `bson:"eventType" json:"eventType"`
+	Payload        string         `bson:"payload" json:"payload"`
+	Status         DeliveryStatus `bson:"status" json:"status"`
+	Attempts       int            `bson:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time      `bson:"nextAttemptAt" json:"nextAttemptAt"`
+	LastAttemptAt  *time.Time     `bson:"lastAttemptAt,omitempty" json:"lastAttemptAt,omitempty"`
+	ResponseStatus int            `bson:"responseStatus,omitempty" json:"responseStatus,omitempty"`
+	LastError      string         `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	DeliveredAt    *time.Time     `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time      `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time      `bson:"updatedAt" json:"updatedAt"`
+}
+
+// TableName returns the collection name for webhook deliveries
+func (d *WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}